@@ -0,0 +1,502 @@
+package alert
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nick0323/K8sVision/model"
+	"go.uber.org/zap"
+)
+
+// 告警状态
+const (
+	StatePending  = "pending"
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+// 保留的已恢复告警数量
+const maxResolvedHistory = 200
+
+var ErrSinkNotFound = errors.New("通知渠道不存在")
+
+// SnapshotFunc 采集一次集群状态
+type SnapshotFunc func(ctx context.Context) (*model.ClusterSnapshot, error)
+
+// Alert 一条告警实例，同一规则、同一组标签只保留一个实例
+type Alert struct {
+	Fingerprint string            `json:"fingerprint"`
+	Rule        string            `json:"rule"`
+	Severity    string            `json:"severity"`
+	State       string            `json:"state"`
+	Labels      map[string]string `json:"labels"`
+	Message     string            `json:"message"`
+	ActiveAt    time.Time         `json:"activeAt"`
+	FiredAt     time.Time         `json:"firedAt"`
+	ResolvedAt  *time.Time        `json:"resolvedAt,omitempty"`
+	SilencedBy  string            `json:"silencedBy,omitempty"`
+
+	lastNotified time.Time
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (a Alert) GetSearchableFields() map[string]string {
+	fields := map[string]string{
+		"Rule":     a.Rule,
+		"Severity": a.Severity,
+		"State":    a.State,
+		"Message":  a.Message,
+	}
+	for k, v := range a.Labels {
+		fields[k] = v
+	}
+	return fields
+}
+
+// Status 引擎运行状态
+type Status struct {
+	Rules        int       `json:"rules"`
+	Sinks        []string  `json:"sinks"`
+	LastEval     time.Time `json:"lastEval"`
+	LastEvalErr  string    `json:"lastEvalError,omitempty"`
+	EvalDuration string    `json:"evalDuration"`
+}
+
+// Engine 周期性评估规则，维护告警状态并发送通知
+type Engine struct {
+	mu       sync.RWMutex
+	rules    []Rule
+	active   map[string]*Alert
+	resolved []Alert
+	status   Status
+
+	restarts *restartTracker
+	silences *silenceStore
+	sinks    []Sink
+	snapshot SnapshotFunc
+
+	interval    time.Duration
+	repeat      time.Duration
+	sendTimeout time.Duration
+	logger      *zap.Logger
+
+	cancel  context.CancelFunc
+	done    chan struct{}
+	sending sync.WaitGroup
+}
+
+var globalEngine *Engine
+
+// NewEngine 创建告警引擎
+func NewEngine(cfg *model.AlertingConfig, rules []Rule, logger *zap.Logger, snapshot SnapshotFunc) (*Engine, error) {
+	silences, err := newSilenceStore(cfg.SilenceFile)
+	if err != nil {
+		return nil, fmt.Errorf("加载静默规则失败: %w", err)
+	}
+
+	var retention time.Duration
+	for _, r := range rules {
+		if r.Type == RulePodRestarts && r.Window.Duration > retention {
+			retention = r.Window.Duration
+		}
+	}
+
+	e := &Engine{
+		rules:       rules,
+		active:      make(map[string]*Alert),
+		restarts:    newRestartTracker(retention),
+		silences:    silences,
+		sinks:       buildSinks(cfg),
+		snapshot:    snapshot,
+		interval:    cfg.Interval,
+		repeat:      cfg.RepeatInterval,
+		sendTimeout: cfg.SendTimeout,
+		logger:      logger,
+		done:        make(chan struct{}),
+	}
+	e.status.Rules = len(rules)
+	for _, s := range e.sinks {
+		e.status.Sinks = append(e.status.Sinks, s.Name())
+	}
+	return e, nil
+}
+
+// Init 加载规则并启动全局告警引擎
+func Init(cfg *model.AlertingConfig, logger *zap.Logger, snapshot SnapshotFunc) {
+	if !cfg.Enabled {
+		logger.Info("告警引擎未启用")
+		return
+	}
+
+	rules, err := LoadRules(cfg.RulesFile)
+	if err != nil {
+		logger.Error("告警引擎初始化失败", zap.String("file", cfg.RulesFile), zap.Error(err))
+		return
+	}
+	e, err := NewEngine(cfg, rules, logger, snapshot)
+	if err != nil {
+		logger.Error("告警引擎初始化失败", zap.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	go e.run(ctx)
+
+	globalEngine = e
+	logger.Info("告警引擎已启动",
+		zap.Int("rules", len(rules)),
+		zap.Int("sinks", len(e.sinks)),
+		zap.Duration("interval", cfg.Interval),
+	)
+}
+
+// Default 返回全局告警引擎，未启用时为 nil
+func Default() *Engine {
+	return globalEngine
+}
+
+// Close 停止全局告警引擎
+func Close() {
+	if globalEngine != nil {
+		globalEngine.Close()
+	}
+}
+
+func (e *Engine) run(ctx context.Context) {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	e.evaluate(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			e.evaluate(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// evaluate 执行一轮评估：更新告警状态并发送状态变化的通知
+func (e *Engine) evaluate(ctx context.Context) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, e.interval)
+	defer cancel()
+
+	snapshot, err := e.snapshot(ctx)
+	if err != nil {
+		e.logger.Warn("采集告警评估数据失败", zap.Error(err))
+		e.mu.Lock()
+		e.status.LastEval, e.status.LastEvalErr = start, err.Error()
+		e.status.EvalDuration = time.Since(start).String()
+		e.mu.Unlock()
+		return
+	}
+	e.restarts.observe(snapshot)
+	now := snapshot.Time
+
+	e.mu.Lock()
+	seen := make(map[string]bool, len(e.active))
+	var firing, resolved []Alert
+	for i := range e.rules {
+		rule := &e.rules[i]
+		for _, f := range rule.evaluate(snapshot, e.restarts) {
+			labels := mergeLabels(rule.Labels, f.labels)
+			fp := fingerprint(rule.Name, labels)
+			seen[fp] = true
+
+			a, exists := e.active[fp]
+			if !exists {
+				a = &Alert{
+					Fingerprint: fp,
+					Rule:        rule.Name,
+					Severity:    rule.Severity,
+					State:       StatePending,
+					Labels:      labels,
+					ActiveAt:    now,
+				}
+				e.active[fp] = a
+			}
+			a.Message = f.message
+			if a.State == StatePending && now.Sub(a.ActiveAt) >= rule.For.Duration {
+				a.State, a.FiredAt = StateFiring, now
+			}
+		}
+	}
+
+	for fp, a := range e.active {
+		if !seen[fp] {
+			delete(e.active, fp)
+			if a.State != StateFiring {
+				continue
+			}
+			a.State, a.ResolvedAt = StateResolved, &now
+			e.appendResolved(*a)
+			// 只有通知过触发的告警才发送恢复通知
+			if !a.lastNotified.IsZero() {
+				resolved = append(resolved, *a)
+			}
+			continue
+		}
+
+		a.SilencedBy = e.silences.Match(a, now)
+		if a.State != StateFiring || a.SilencedBy != "" {
+			continue
+		}
+		if a.lastNotified.IsZero() || (e.repeat > 0 && now.Sub(a.lastNotified) >= e.repeat) {
+			a.lastNotified = now
+			firing = append(firing, *a)
+		}
+	}
+
+	e.status.LastEval, e.status.LastEvalErr = start, ""
+	e.status.EvalDuration = time.Since(start).String()
+	e.mu.Unlock()
+
+	e.silences.Prune(now)
+	if len(firing) > 0 {
+		e.notify(&Notification{Status: StateFiring, Alerts: firing, SentAt: now})
+	}
+	if len(resolved) > 0 {
+		e.notify(&Notification{Status: StateResolved, Alerts: resolved, SentAt: now})
+	}
+}
+
+func (e *Engine) appendResolved(a Alert) {
+	e.resolved = append(e.resolved, a)
+	if len(e.resolved) > maxResolvedHistory {
+		e.resolved = e.resolved[len(e.resolved)-maxResolvedHistory:]
+	}
+}
+
+// notify 异步发送到所有通知渠道，单个渠道失败不影响其他渠道
+func (e *Engine) notify(n *Notification) {
+	for _, s := range e.sinks {
+		e.sending.Add(1)
+		go func(s Sink) {
+			defer e.sending.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), e.sendTimeout)
+			defer cancel()
+			if err := s.Send(ctx, n); err != nil {
+				e.logger.Warn("发送告警通知失败",
+					zap.String("sink", s.Name()),
+					zap.String("status", n.Status),
+					zap.Error(err),
+				)
+				return
+			}
+			e.logger.Info("已发送告警通知",
+				zap.String("sink", s.Name()),
+				zap.String("status", n.Status),
+				zap.Int("alerts", len(n.Alerts)),
+			)
+		}(s)
+	}
+}
+
+// SendTest 向指定通知渠道（为空时所有渠道）同步发送测试通知，返回各渠道的发送结果
+func (e *Engine) SendTest(ctx context.Context, sinkName string) (map[string]string, error) {
+	now := time.Now()
+	n := &Notification{
+		Status: StateFiring,
+		SentAt: now,
+		Alerts: []Alert{{
+			Fingerprint: "test",
+			Rule:        "TestNotification",
+			Severity:    SeverityInfo,
+			State:       StateFiring,
+			Labels:      map[string]string{},
+			Message:     "这是一条测试通知",
+			ActiveAt:    now,
+			FiredAt:     now,
+		}},
+	}
+
+	results := make(map[string]string)
+	for _, s := range e.sinks {
+		if sinkName != "" && s.Name() != sinkName {
+			continue
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, e.sendTimeout)
+		if err := s.Send(sendCtx, n); err != nil {
+			results[s.Name()] = err.Error()
+		} else {
+			results[s.Name()] = "ok"
+		}
+		cancel()
+	}
+	if len(results) == 0 {
+		return nil, ErrSinkNotFound
+	}
+	return results, nil
+}
+
+// Alerts 返回告警列表，state 为空时返回当前告警和最近恢复的告警
+func (e *Engine) Alerts(state string) []Alert {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	result := make([]Alert, 0, len(e.active))
+	for _, a := range e.active {
+		if state == "" || a.State == state {
+			result = append(result, *a)
+		}
+	}
+	if state == "" || state == StateResolved {
+		result = append(result, e.resolved...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ActiveAt.After(result[j].ActiveAt)
+	})
+	return result
+}
+
+// Rules 返回已加载的规则
+func (e *Engine) Rules() []Rule {
+	return e.rules
+}
+
+// Status 返回引擎运行状态
+func (e *Engine) Status() Status {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.status
+}
+
+// Silences 返回全部静默规则
+func (e *Engine) Silences() []Silence {
+	return e.silences.List()
+}
+
+// AddSilence 创建静默规则，对当前告警立即生效
+func (e *Engine) AddSilence(s Silence) (*Silence, error) {
+	created, err := e.silences.Add(s)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	e.mu.Lock()
+	for _, a := range e.active {
+		if a.SilencedBy == "" && created.Active(now) && created.Matches(a) {
+			a.SilencedBy = created.ID
+		}
+	}
+	e.mu.Unlock()
+	return created, nil
+}
+
+// DeleteSilence 删除静默规则，下一轮评估时恢复通知
+func (e *Engine) DeleteSilence(id string) error {
+	return e.silences.Delete(id)
+}
+
+// Close 停止评估并等待进行中的通知发送完成
+func (e *Engine) Close() {
+	if e.cancel != nil {
+		e.cancel()
+		<-e.done
+	}
+	e.sending.Wait()
+	e.logger.Info("告警引擎已关闭")
+}
+
+func mergeLabels(ruleLabels, labels map[string]string) map[string]string {
+	merged := make(map[string]string, len(ruleLabels)+len(labels))
+	for k, v := range ruleLabels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged
+}
+
+// fingerprint 由规则名称和排序后的标签计算，用于告警去重
+func fingerprint(rule string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(rule)
+	for _, k := range keys {
+		b.WriteString("\x00")
+		b.WriteString(k)
+		b.WriteString("=")
+		b.WriteString(labels[k])
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// restartTracker 记录Pod重启次数的历史采样，用于计算窗口内的增量
+type restartTracker struct {
+	retention time.Duration
+	samples   map[string][]restartSample
+}
+
+type restartSample struct {
+	t        time.Time
+	restarts int32
+}
+
+func newRestartTracker(retention time.Duration) *restartTracker {
+	return &restartTracker{retention: retention, samples: make(map[string][]restartSample)}
+}
+
+// observe 追加本轮采样，丢弃已消失的Pod和超出保留时间的采样（保留窗口起点前的最后一个）
+func (t *restartTracker) observe(s *model.ClusterSnapshot) {
+	if t.retention == 0 {
+		return
+	}
+	cutoff := s.Time.Add(-t.retention)
+	current := make(map[string]bool, len(s.Pods))
+	for _, p := range s.Pods {
+		key := p.Namespace + "/" + p.Name
+		current[key] = true
+
+		samples := append(t.samples[key], restartSample{t: s.Time, restarts: p.Restarts})
+		drop := 0
+		for drop+1 < len(samples) && !samples[drop+1].t.After(cutoff) {
+			drop++
+		}
+		t.samples[key] = samples[drop:]
+	}
+	for key := range t.samples {
+		if !current[key] {
+			delete(t.samples, key)
+		}
+	}
+}
+
+// increase 返回 since 以来的重启增量；计数回退（Pod重建）时以当前值为增量
+func (t *restartTracker) increase(namespace, name string, since time.Time) int32 {
+	samples := t.samples[namespace+"/"+name]
+	if len(samples) == 0 {
+		return 0
+	}
+	baseline := samples[0]
+	for _, s := range samples {
+		if s.t.After(since) {
+			break
+		}
+		baseline = s
+	}
+	latest := samples[len(samples)-1].restarts
+	if latest < baseline.restarts {
+		return latest
+	}
+	return latest - baseline.restarts
+}
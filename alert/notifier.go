@@ -0,0 +1,163 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nick0323/K8sVision/model"
+)
+
+// Notification 一次通知的内容，同一轮评估中状态变化的告警合并发送
+type Notification struct {
+	Status string    `json:"status"`
+	Alerts []Alert   `json:"alerts"`
+	SentAt time.Time `json:"sentAt"`
+}
+
+// Sink 告警通知渠道
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, n *Notification) error
+}
+
+// buildSinks 根据配置创建通知渠道
+func buildSinks(cfg *model.AlertingConfig) []Sink {
+	client := &http.Client{Timeout: cfg.SendTimeout}
+	sinks := make([]Sink, 0, len(cfg.Webhooks)+len(cfg.Slack)+len(cfg.Email))
+	for _, w := range cfg.Webhooks {
+		sinks = append(sinks, &WebhookSink{cfg: w, client: client})
+	}
+	for _, s := range cfg.Slack {
+		sinks = append(sinks, &SlackSink{cfg: s, client: client})
+	}
+	for _, e := range cfg.Email {
+		sinks = append(sinks, &EmailSink{cfg: e})
+	}
+	return sinks
+}
+
+// WebhookSink 以JSON格式POST完整通知内容
+type WebhookSink struct {
+	cfg    model.WebhookSinkConfig
+	client *http.Client
+}
+
+func (w *WebhookSink) Name() string { return w.cfg.Name }
+
+func (w *WebhookSink) Send(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, w.client, w.cfg.URL, w.cfg.Headers, body)
+}
+
+// SlackSink 发送Slack Incoming Webhook格式的文本消息
+type SlackSink struct {
+	cfg    model.SlackSinkConfig
+	client *http.Client
+}
+
+func (s *SlackSink) Name() string { return s.cfg.Name }
+
+func (s *SlackSink) Send(ctx context.Context, n *Notification) error {
+	payload := map[string]string{"text": formatText(n)}
+	if s.cfg.Channel != "" {
+		payload["channel"] = s.cfg.Channel
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.client, s.cfg.WebhookURL, nil, body)
+}
+
+// EmailSink 通过SMTP发送纯文本邮件
+type EmailSink struct {
+	cfg model.EmailSinkConfig
+}
+
+func (e *EmailSink) Name() string { return e.cfg.Name }
+
+func (e *EmailSink) Send(ctx context.Context, n *Notification) error {
+	subject := fmt.Sprintf("[K8sVision] %s: %s", strings.ToUpper(n.Status), summarize(n))
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "Date: %s\r\n", n.SentAt.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(formatText(n), "\n", "\r\n"))
+
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+	var auth smtp.Auth
+	if e.cfg.Username != "" {
+		auth = smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)
+	}
+
+	// smtp.SendMail 不支持 context，在单独的协程中执行以遵守超时
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(addr, auth, e.cfg.From, e.cfg.To, msg.Bytes())
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("通知接收方返回 %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+func summarize(n *Notification) string {
+	if len(n.Alerts) == 1 {
+		return n.Alerts[0].Rule
+	}
+	return fmt.Sprintf("%d 条告警", len(n.Alerts))
+}
+
+// formatText 生成人类可读的通知文本
+func formatText(n *Notification) string {
+	var b strings.Builder
+	for i, a := range n.Alerts {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%s][%s] %s: %s", strings.ToUpper(a.State), a.Severity, a.Rule, a.Message)
+		if a.State == StateResolved && a.ResolvedAt != nil {
+			fmt.Fprintf(&b, " (恢复于 %s)", a.ResolvedAt.Local().Format(model.TimeFormat))
+		} else if !a.FiredAt.IsZero() {
+			fmt.Fprintf(&b, " (触发于 %s)", a.FiredAt.Local().Format(model.TimeFormat))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,259 @@
+// Package alert 提供基于YAML规则的集群告警引擎，支持去重、静默和多种通知渠道
+package alert
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nick0323/K8sVision/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// 规则类型
+const (
+	RuleDeploymentUnavailable = "deploymentUnavailable"
+	RuleNodeNotReady          = "nodeNotReady"
+	RulePodRestarts           = "podRestarts"
+	RulePVCPhase              = "pvcPhase"
+	RuleWarningEvent          = "warningEvent"
+)
+
+// 告警级别
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// 默认统计窗口
+const (
+	defaultRestartWindow = 10 * time.Minute
+	defaultEventWindow   = 10 * time.Minute
+	defaultPVCPhase      = "Lost"
+)
+
+// Rule 一条告警规则
+// For: 条件持续多久后才触发；Namespaces: 命名空间通配符，为空表示全部
+// Threshold/Window: podRestarts 在窗口内的重启次数阈值；warningEvent 的事件回溯窗口
+// Phase: pvcPhase 匹配的阶段；Reason/Message: warningEvent 的正则匹配条件
+type Rule struct {
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	Severity    string            `json:"severity"`
+	For         metav1.Duration   `json:"for"`
+	Namespaces  []string          `json:"namespaces,omitempty"`
+	Threshold   int32             `json:"threshold,omitempty"`
+	Window      metav1.Duration   `json:"window,omitempty"`
+	Phase       string            `json:"phase,omitempty"`
+	Reason      string            `json:"reason,omitempty"`
+	Message     string            `json:"message,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Description string            `json:"description,omitempty"`
+
+	reasonRe  *regexp.Regexp
+	messageRe *regexp.Regexp
+}
+
+// ruleFile 规则文件结构
+type ruleFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadRules 从YAML文件加载并校验规则
+func LoadRules(file string) ([]Rule, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("读取告警规则文件失败: %w", err)
+	}
+	return ParseRules(data)
+}
+
+// ParseRules 解析YAML规则并补全默认值
+func ParseRules(data []byte) ([]Rule, error) {
+	var rf ruleFile
+	if err := yaml.UnmarshalStrict(data, &rf); err != nil {
+		return nil, fmt.Errorf("解析告警规则失败: %w", err)
+	}
+
+	names := make(map[string]bool, len(rf.Rules))
+	for i := range rf.Rules {
+		r := &rf.Rules[i]
+		if err := r.prepare(); err != nil {
+			return nil, fmt.Errorf("规则 %q: %w", r.Name, err)
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("规则名称重复: %s", r.Name)
+		}
+		names[r.Name] = true
+	}
+	return rf.Rules, nil
+}
+
+func (r *Rule) prepare() error {
+	if r.Name == "" {
+		return fmt.Errorf("规则名称不能为空")
+	}
+	if r.For.Duration < 0 || r.Window.Duration < 0 {
+		return fmt.Errorf("for 和 window 不能为负数")
+	}
+	switch r.Severity {
+	case "":
+		r.Severity = SeverityWarning
+	case SeverityCritical, SeverityWarning, SeverityInfo:
+	default:
+		return fmt.Errorf("不支持的告警级别: %s", r.Severity)
+	}
+	for _, ns := range r.Namespaces {
+		if _, err := path.Match(ns, ""); err != nil {
+			return fmt.Errorf("命名空间通配符无效: %s", ns)
+		}
+	}
+
+	switch r.Type {
+	case RuleDeploymentUnavailable, RuleNodeNotReady:
+	case RulePodRestarts:
+		if r.Threshold <= 0 {
+			return fmt.Errorf("podRestarts 规则需要配置大于0的 threshold")
+		}
+		if r.Window.Duration == 0 {
+			r.Window.Duration = defaultRestartWindow
+		}
+	case RulePVCPhase:
+		if r.Phase == "" {
+			r.Phase = defaultPVCPhase
+		}
+	case RuleWarningEvent:
+		if r.Window.Duration == 0 {
+			r.Window.Duration = defaultEventWindow
+		}
+		var err error
+		if r.Reason != "" {
+			if r.reasonRe, err = regexp.Compile(r.Reason); err != nil {
+				return fmt.Errorf("reason 正则无效: %w", err)
+			}
+		}
+		if r.Message != "" {
+			if r.messageRe, err = regexp.Compile(r.Message); err != nil {
+				return fmt.Errorf("message 正则无效: %w", err)
+			}
+		}
+	default:
+		return fmt.Errorf("不支持的规则类型: %s", r.Type)
+	}
+	return nil
+}
+
+// matchNamespace 判断命名空间是否在规则范围内
+func (r *Rule) matchNamespace(ns string) bool {
+	if len(r.Namespaces) == 0 {
+		return true
+	}
+	for _, pattern := range r.Namespaces {
+		if ok, _ := path.Match(pattern, ns); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// finding 一次评估中满足规则条件的对象
+type finding struct {
+	labels  map[string]string
+	message string
+}
+
+// evaluate 基于快照评估规则，返回当前满足条件的对象
+func (r *Rule) evaluate(s *model.ClusterSnapshot, restarts *restartTracker) []finding {
+	var result []finding
+	switch r.Type {
+	case RuleDeploymentUnavailable:
+		for _, d := range s.Deployments {
+			if r.matchNamespace(d.Namespace) && d.Available < d.Desired {
+				result = append(result, finding{
+					labels:  map[string]string{"namespace": d.Namespace, "deployment": d.Name},
+					message: fmt.Sprintf("Deployment %s/%s 可用副本 %d/%d", d.Namespace, d.Name, d.Available, d.Desired),
+				})
+			}
+		}
+	case RuleNodeNotReady:
+		for _, n := range s.Nodes {
+			if n.Status != model.StatusActive {
+				result = append(result, finding{
+					labels:  map[string]string{"node": n.Name},
+					message: fmt.Sprintf("节点 %s 未就绪", n.Name),
+				})
+			}
+		}
+	case RulePodRestarts:
+		for _, p := range s.Pods {
+			if !r.matchNamespace(p.Namespace) {
+				continue
+			}
+			if inc := restarts.increase(p.Namespace, p.Name, s.Time.Add(-r.Window.Duration)); inc > r.Threshold {
+				result = append(result, finding{
+					labels:  map[string]string{"namespace": p.Namespace, "pod": p.Name},
+					message: fmt.Sprintf("Pod %s/%s 在 %s 内重启 %d 次", p.Namespace, p.Name, r.Window.Duration, inc),
+				})
+			}
+		}
+	case RulePVCPhase:
+		for _, pvc := range s.PVCs {
+			if r.matchNamespace(pvc.Namespace) && pvc.Status == r.Phase {
+				result = append(result, finding{
+					labels:  map[string]string{"namespace": pvc.Namespace, "pvc": pvc.Name},
+					message: fmt.Sprintf("PVC %s/%s 处于 %s 状态", pvc.Namespace, pvc.Name, pvc.Status),
+				})
+			}
+		}
+	case RuleWarningEvent:
+		result = r.evaluateEvents(s)
+	}
+	return result
+}
+
+// evaluateEvents 同一对象、同一原因的事件合并为一个告警，窗口内无新事件后告警恢复
+func (r *Rule) evaluateEvents(s *model.ClusterSnapshot) []finding {
+	since := s.Time.Add(-r.Window.Duration)
+	seen := make(map[string]bool)
+	var result []finding
+	for _, e := range s.Events {
+		if e.Type != "Warning" || !r.matchNamespace(e.Namespace) {
+			continue
+		}
+		if r.reasonRe != nil && !r.reasonRe.MatchString(e.Reason) {
+			continue
+		}
+		if r.messageRe != nil && !r.messageRe.MatchString(e.Message) {
+			continue
+		}
+		// 没有任何时间信息的事件无法判断是否过期，按仍在发生处理，不静默丢弃
+		if !e.LastSeen.IsZero() && e.LastSeen.Before(since) {
+			continue
+		}
+
+		object := eventObjectName(e.Name)
+		key := e.Namespace + "/" + object + "/" + e.Reason
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, finding{
+			labels:  map[string]string{"namespace": e.Namespace, "object": object, "reason": e.Reason},
+			message: fmt.Sprintf("%s/%s %s: %s", e.Namespace, object, e.Reason, e.Message),
+		})
+	}
+	return result
+}
+
+// eventObjectName 事件名称形如 <对象名>.<随机后缀>，去掉后缀得到对象名
+func eventObjectName(name string) string {
+	if i := strings.LastIndex(name, "."); i > 0 {
+		return name[:i]
+	}
+	return name
+}
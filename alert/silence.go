@@ -0,0 +1,198 @@
+package alert
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nick0323/K8sVision/store"
+)
+
+var (
+	ErrSilenceNotFound = errors.New("静默规则不存在")
+	ErrInvalidSilence  = errors.New("静默规则无效")
+)
+
+// Matcher 静默匹配条件，Value 支持通配符
+type Matcher struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Silence 在时间范围内抑制匹配告警的通知，告警本身仍会记录
+type Silence struct {
+	ID        string    `json:"id"`
+	Matchers  []Matcher `json:"matchers"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (s Silence) GetSearchableFields() map[string]string {
+	fields := map[string]string{
+		"ID":        s.ID,
+		"CreatedBy": s.CreatedBy,
+		"Comment":   s.Comment,
+	}
+	for _, m := range s.Matchers {
+		fields[m.Name] = m.Value
+	}
+	return fields
+}
+
+// Active 判断静默在指定时间是否生效
+func (s *Silence) Active(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+// Matches 所有条件均满足时匹配，规则名称可通过 alertname 匹配
+func (s *Silence) Matches(a *Alert) bool {
+	for _, m := range s.Matchers {
+		value, ok := a.Labels[m.Name]
+		if m.Name == "alertname" {
+			value, ok = a.Rule, true
+		}
+		if !ok {
+			return false
+		}
+		if matched, _ := path.Match(m.Value, value); !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// silenceStore 静默规则存储，变更后持久化到文件
+type silenceStore struct {
+	mu       sync.RWMutex
+	file     string
+	silences map[string]*Silence
+}
+
+func newSilenceStore(file string) (*silenceStore, error) {
+	s := &silenceStore{file: file, silences: make(map[string]*Silence)}
+	if file == "" {
+		return s, nil
+	}
+	var list []*Silence
+	if err := store.LoadJSON(file, &list); err != nil {
+		return nil, err
+	}
+	for _, sl := range list {
+		s.silences[sl.ID] = sl
+	}
+	return s, nil
+}
+
+// Add 校验并保存静默规则
+func (s *silenceStore) Add(sl Silence) (*Silence, error) {
+	if len(sl.Matchers) == 0 {
+		return nil, fmt.Errorf("%w: 至少需要一个匹配条件", ErrInvalidSilence)
+	}
+	for _, m := range sl.Matchers {
+		if m.Name == "" || m.Value == "" {
+			return nil, fmt.Errorf("%w: 匹配条件的 name 和 value 不能为空", ErrInvalidSilence)
+		}
+		if _, err := path.Match(m.Value, ""); err != nil {
+			return nil, fmt.Errorf("%w: 通配符无效 %q", ErrInvalidSilence, m.Value)
+		}
+	}
+	now := time.Now()
+	if sl.StartsAt.IsZero() {
+		sl.StartsAt = now
+	}
+	if !sl.EndsAt.After(sl.StartsAt) || !sl.EndsAt.After(now) {
+		return nil, fmt.Errorf("%w: endsAt 必须晚于 startsAt 和当前时间", ErrInvalidSilence)
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("生成静默ID失败: %w", err)
+	}
+	sl.ID = hex.EncodeToString(id)
+	sl.CreatedAt = now
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.silences[sl.ID] = &sl
+	if err := s.saveLocked(); err != nil {
+		delete(s.silences, sl.ID)
+		return nil, err
+	}
+	return &sl, nil
+}
+
+// Delete 删除静默规则
+func (s *silenceStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sl, ok := s.silences[id]
+	if !ok {
+		return ErrSilenceNotFound
+	}
+	delete(s.silences, id)
+	if err := s.saveLocked(); err != nil {
+		s.silences[id] = sl
+		return err
+	}
+	return nil
+}
+
+// List 返回全部静默规则，按结束时间从晚到早排列
+func (s *silenceStore) List() []Silence {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]Silence, 0, len(s.silences))
+	for _, sl := range s.silences {
+		result = append(result, *sl)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].EndsAt.After(result[j].EndsAt) })
+	return result
+}
+
+// Match 返回对告警生效的第一个静默ID
+func (s *silenceStore) Match(a *Alert, now time.Time) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, sl := range s.silences {
+		if sl.Active(now) && sl.Matches(a) {
+			return sl.ID
+		}
+	}
+	return ""
+}
+
+// Prune 删除已过期超过一天的静默规则
+func (s *silenceStore) Prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := false
+	for id, sl := range s.silences {
+		if now.Sub(sl.EndsAt) > 24*time.Hour {
+			delete(s.silences, id)
+			removed = true
+		}
+	}
+	if removed {
+		_ = s.saveLocked()
+	}
+}
+
+func (s *silenceStore) saveLocked() error {
+	if s.file == "" {
+		return nil
+	}
+	list := make([]*Silence, 0, len(s.silences))
+	for _, sl := range s.silences {
+		list = append(list, sl)
+	}
+	return store.SaveJSON(s.file, list)
+}
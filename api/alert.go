@@ -0,0 +1,314 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nick0323/K8sVision/alert"
+	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/audit"
+	"github.com/nick0323/K8sVision/model"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SilenceCreateRequest 创建静默请求，Duration 与 EndsAt 二选一
+type SilenceCreateRequest struct {
+	Matchers []alert.Matcher `json:"matchers" binding:"required"`
+	StartsAt time.Time       `json:"startsAt"`
+	EndsAt   time.Time       `json:"endsAt"`
+	Duration string          `json:"duration"`
+	Comment  string          `json:"comment"`
+}
+
+// RegisterAlerts 注册告警查询路由
+func RegisterAlerts(r *gin.RouterGroup, logger *zap.Logger, getK8sClient K8sClientProvider) {
+	r.GET("/alerts", listAlerts(logger, getK8sClient))
+	r.GET("/alerts/rules", listAlertRules(logger))
+	r.GET("/alerts/silences", listSilences(logger, getK8sClient))
+}
+
+// RegisterAlertsAdmin 注册告警静默与通知测试路由（仅管理员）
+func RegisterAlertsAdmin(r *gin.RouterGroup, logger *zap.Logger) {
+	r.POST("/admin/alerts/silences", createSilence(logger))
+	r.DELETE("/admin/alerts/silences/:id", deleteSilence(logger))
+	r.POST("/admin/alerts/test", testAlertNotification(logger))
+}
+
+// listAlerts 列出告警，state 可选 pending、firing、resolved
+func listAlerts(logger *zap.Logger, getK8sClient K8sClientProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state := c.Query("state")
+		switch state {
+		case "", alert.StatePending, alert.StateFiring, alert.StateResolved:
+		default:
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeValidationFailed,
+				Message: "查询参数错误",
+				Details: "state 只能为 pending、firing 或 resolved",
+			}, http.StatusBadRequest)
+			return
+		}
+
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]alert.Alert, error) {
+			engine := alert.Default()
+			if engine == nil {
+				return nil, alertingUnavailable()
+			}
+			visibility, err := newAlertVisibility(ctx, getK8sClient)
+			if err != nil {
+				return nil, err
+			}
+			alerts := engine.Alerts(state)
+			result := make([]alert.Alert, 0, len(alerts))
+			for _, a := range alerts {
+				if visibility.canSee(a.Labels) {
+					result = append(result, a)
+				}
+			}
+			return result, nil
+		}, ListSuccessMessage)
+	}
+}
+
+// listAlertRules 返回已加载的规则和引擎运行状态
+func listAlertRules(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		engine := alert.Default()
+		if engine == nil {
+			middleware.ResponseError(c, logger, alertingUnavailable(), http.StatusServiceUnavailable)
+			return
+		}
+		middleware.ResponseSuccess(c, gin.H{
+			"rules":  engine.Rules(),
+			"status": engine.Status(),
+		}, SuccessMessage, nil)
+	}
+}
+
+func listSilences(logger *zap.Logger, getK8sClient K8sClientProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]alert.Silence, error) {
+			engine := alert.Default()
+			if engine == nil {
+				return nil, alertingUnavailable()
+			}
+			visibility, err := newAlertVisibility(ctx, getK8sClient)
+			if err != nil {
+				return nil, err
+			}
+			silences := engine.Silences()
+			result := make([]alert.Silence, 0, len(silences))
+			for _, silence := range silences {
+				// 只按精确匹配的标签判断，通配符匹配条件不指向具体对象
+				labels := make(map[string]string, len(silence.Matchers))
+				for _, m := range silence.Matchers {
+					if !strings.ContainsAny(m.Value, "*?[") {
+						labels[m.Name] = m.Value
+					}
+				}
+				if visibility.canSee(labels) {
+					result = append(result, silence)
+				}
+			}
+			return result, nil
+		}, ListSuccessMessage)
+	}
+}
+
+// alertVisibility 告警由服务自身身份根据全集群快照生成，启用模拟身份时按当前用户在集群RBAC中的权限过滤：
+// 带 namespace 标签的告警需要能列举该命名空间的Pod，节点告警需要能列举节点，结果在同一请求内缓存。
+// 未启用模拟身份时所有用户均以服务身份访问集群，不做过滤
+type alertVisibility struct {
+	ctx       context.Context
+	clientset *kubernetes.Clientset
+	allowed   map[string]bool
+}
+
+// newAlertVisibility 未启用模拟身份时返回 nil，表示全部可见
+func newAlertVisibility(ctx context.Context, getK8sClient K8sClientProvider) (*alertVisibility, error) {
+	if configManager == nil || !configManager.GetConfig().Kubernetes.Impersonation.Enabled {
+		return nil, nil
+	}
+	clientset, _, err := getK8sClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &alertVisibility{ctx: ctx, clientset: clientset, allowed: make(map[string]bool)}, nil
+}
+
+func (v *alertVisibility) canSee(labels map[string]string) bool {
+	if v == nil {
+		return true
+	}
+	if ns := labels["namespace"]; ns != "" {
+		return v.check("namespace/"+ns, func() error {
+			_, err := v.clientset.CoreV1().Pods(ns).List(v.ctx, metav1.ListOptions{Limit: 1})
+			return err
+		})
+	}
+	if labels["node"] != "" {
+		return v.check("nodes", func() error {
+			_, err := v.clientset.CoreV1().Nodes().List(v.ctx, metav1.ListOptions{Limit: 1})
+			return err
+		})
+	}
+	return true
+}
+
+func (v *alertVisibility) check(key string, list func() error) bool {
+	allowed, cached := v.allowed[key]
+	if !cached {
+		allowed = list() == nil
+		v.allowed[key] = allowed
+	}
+	return allowed
+}
+
+// createSilence 创建静默，匹配条件的 value 支持通配符，alertname 匹配规则名称
+func createSilence(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		engine := alert.Default()
+		if engine == nil {
+			middleware.ResponseError(c, logger, alertingUnavailable(), http.StatusServiceUnavailable)
+			return
+		}
+
+		var req SilenceCreateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeBadRequest,
+				Message: "请求参数格式错误",
+				Details: err.Error(),
+			}, http.StatusBadRequest)
+			return
+		}
+
+		silence := alert.Silence{
+			Matchers:  req.Matchers,
+			StartsAt:  req.StartsAt,
+			EndsAt:    req.EndsAt,
+			CreatedBy: c.GetString("username"),
+			Comment:   req.Comment,
+		}
+		if req.Duration != "" {
+			d, err := time.ParseDuration(req.Duration)
+			if err != nil || d <= 0 {
+				middleware.ResponseError(c, logger, &model.APIError{
+					Code:    model.CodeValidationFailed,
+					Message: "请求参数错误",
+					Details: "duration 必须为正的时长，如 2h",
+				}, http.StatusBadRequest)
+				return
+			}
+			start := req.StartsAt
+			if start.IsZero() {
+				start = time.Now()
+			}
+			silence.StartsAt, silence.EndsAt = start, start.Add(d)
+		}
+
+		created, err := engine.AddSilence(silence)
+		if err != nil {
+			middleware.RecordAudit(c, &audit.Event{
+				Action:   audit.ActionSilenceCreate,
+				Resource: audit.Resource{Kind: "silence"},
+				Outcome:  audit.OutcomeFailure,
+				Reason:   err.Error(),
+			})
+			httpCode, code := http.StatusInternalServerError, model.CodeInternalServerError
+			if errors.Is(err, alert.ErrInvalidSilence) {
+				httpCode, code = http.StatusBadRequest, model.CodeValidationFailed
+			}
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    code,
+				Message: "创建静默失败",
+				Details: err.Error(),
+			}, httpCode)
+			return
+		}
+
+		middleware.RecordAudit(c, &audit.Event{
+			Action:   audit.ActionSilenceCreate,
+			Resource: audit.Resource{Kind: "silence", Name: created.ID},
+			Outcome:  audit.OutcomeSuccess,
+			Details: map[string]interface{}{
+				"matchers": created.Matchers,
+				"endsAt":   created.EndsAt,
+			},
+		})
+		middleware.ResponseSuccess(c, created, CreateSuccessMessage, nil)
+	}
+}
+
+func deleteSilence(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		engine := alert.Default()
+		if engine == nil {
+			middleware.ResponseError(c, logger, alertingUnavailable(), http.StatusServiceUnavailable)
+			return
+		}
+
+		id := c.Param("id")
+		if err := engine.DeleteSilence(id); err != nil {
+			middleware.RecordAudit(c, &audit.Event{
+				Action:   audit.ActionSilenceDelete,
+				Resource: audit.Resource{Kind: "silence", Name: id},
+				Outcome:  audit.OutcomeFailure,
+				Reason:   err.Error(),
+			})
+			httpCode, code := http.StatusInternalServerError, model.CodeInternalServerError
+			if errors.Is(err, alert.ErrSilenceNotFound) {
+				httpCode, code = http.StatusNotFound, model.CodeResourceNotFound
+			}
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    code,
+				Message: model.GetErrorMessage(code),
+				Details: err.Error(),
+			}, httpCode)
+			return
+		}
+
+		middleware.RecordAudit(c, &audit.Event{
+			Action:   audit.ActionSilenceDelete,
+			Resource: audit.Resource{Kind: "silence", Name: id},
+			Outcome:  audit.OutcomeSuccess,
+		})
+		middleware.ResponseSuccess(c, gin.H{"id": id}, DeleteSuccessMessage, nil)
+	}
+}
+
+// testAlertNotification 向 sink 指定的通知渠道（默认全部）发送测试通知，返回各渠道结果
+func testAlertNotification(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		engine := alert.Default()
+		if engine == nil {
+			middleware.ResponseError(c, logger, alertingUnavailable(), http.StatusServiceUnavailable)
+			return
+		}
+
+		results, err := engine.SendTest(GetRequestContext(c), c.Query("sink"))
+		if err != nil {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeResourceNotFound,
+				Message: model.GetErrorMessage(model.CodeResourceNotFound),
+				Details: err.Error(),
+			}, http.StatusNotFound)
+			return
+		}
+		middleware.ResponseSuccess(c, results, SuccessMessage, nil)
+	}
+}
+
+func alertingUnavailable() error {
+	return &model.APIError{
+		Code:    model.CodeServiceUnavailable,
+		Message: model.GetErrorMessage(model.CodeServiceUnavailable),
+		Details: "告警引擎未启用",
+	}
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/audit"
+	"github.com/nick0323/K8sVision/model"
+	"go.uber.org/zap"
+)
+
+// RegisterAudit 注册审计日志查询与导出路由（仅管理员）
+func RegisterAudit(r *gin.RouterGroup, logger *zap.Logger) {
+	r.GET("/admin/audit", listAuditEvents(logger))
+	r.GET("/admin/audit/export", exportAuditEvents(logger))
+}
+
+// listAuditEvents 按条件分页查询审计事件，结果从新到旧
+func listAuditEvents(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query, err := parseAuditQuery(c)
+		if err != nil {
+			middleware.ResponseError(c, logger, err, http.StatusBadRequest)
+			return
+		}
+
+		recorder := audit.Default()
+		if recorder == nil {
+			middleware.ResponseError(c, logger, auditUnavailable(), http.StatusServiceUnavailable)
+			return
+		}
+
+		// 审计日志可能很大，分页与关键字搜索在读取时完成，不整体载入内存
+		params := ParsePaginationParams(c)
+		var match func(e *audit.Event) bool
+		if params.Search != "" {
+			searchLower := strings.ToLower(params.Search)
+			match = func(e *audit.Event) bool { return matchesSearchOptimized(*e, searchLower) }
+		}
+		events, total, err := recorder.Query(query, match, params.Offset, params.Limit)
+		if err != nil {
+			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
+			return
+		}
+		middleware.ResponseSuccess(c, events, ListSuccessMessage, &model.PageMeta{
+			Total:  total,
+			Limit:  params.Limit,
+			Offset: params.Offset,
+		})
+	}
+}
+
+// exportAuditEvents 以JSON Lines格式导出匹配的审计事件，便于导入外部系统
+func exportAuditEvents(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		recorder := audit.Default()
+		if recorder == nil {
+			middleware.ResponseError(c, logger, auditUnavailable(), http.StatusServiceUnavailable)
+			return
+		}
+
+		query, err := parseAuditQuery(c)
+		if err != nil {
+			middleware.ResponseError(c, logger, err, http.StatusBadRequest)
+			return
+		}
+
+		filename := fmt.Sprintf("audit-%s.jsonl", time.Now().Format("20060102-150405"))
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Header("Content-Disposition", "attachment; filename="+filename)
+		c.Status(http.StatusOK)
+
+		count, err := recorder.Export(c.Writer, query)
+		if err != nil {
+			// 响应体已开始写出，只能记录错误
+			logger.Error("导出审计日志失败", zap.Int("exported", count), zap.Error(err))
+			return
+		}
+		logger.Info("导出审计日志",
+			zap.String("operator", c.GetString("username")),
+			zap.Int("count", count),
+		)
+	}
+}
+
+// parseAuditQuery 解析查询参数，时间支持RFC3339或Unix秒
+func parseAuditQuery(c *gin.Context) (audit.Query, error) {
+	q := audit.Query{
+		Actor:     c.Query("actor"),
+		Action:    c.Query("action"),
+		Outcome:   c.Query("outcome"),
+		Kind:      c.Query("kind"),
+		Namespace: c.Query("namespace"),
+		Name:      c.Query("name"),
+		ClientIP:  c.Query("clientIP"),
+		TraceID:   c.Query("traceId"),
+	}
+
+	var err error
+	if q.From, err = parseTimeParam(c.Query("from")); err != nil {
+		return q, invalidAuditParam("from", err)
+	}
+	if q.To, err = parseTimeParam(c.Query("to")); err != nil {
+		return q, invalidAuditParam("to", err)
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		return q, &model.APIError{
+			Code:    model.CodeValidationFailed,
+			Message: "查询参数错误",
+			Details: "to 不能早于 from",
+		}
+	}
+	return q, nil
+}
+
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(sec, 0), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func invalidAuditParam(name string, err error) error {
+	return &model.APIError{
+		Code:    model.CodeValidationFailed,
+		Message: "查询参数错误",
+		Details: fmt.Sprintf("%s: %v", name, err),
+	}
+}
+
+func auditUnavailable() error {
+	return &model.APIError{
+		Code:    model.CodeServiceUnavailable,
+		Message: model.GetErrorMessage(model.CodeServiceUnavailable),
+		Details: "审计存储未启用",
+	}
+}
@@ -116,7 +116,8 @@ func HandleListWithPagination[T SearchableItem](
 	})
 }
 
-type K8sClientProvider func() (*kubernetes.Clientset, *versioned.Clientset, error)
+// K8sClientProvider 按请求上下文获取客户端，启用模拟身份时上下文中的用户决定客户端身份
+type K8sClientProvider func(ctx context.Context) (*kubernetes.Clientset, *versioned.Clientset, error)
 
 func HandleDetailWithK8s[T any](
 	c *gin.Context,
@@ -125,13 +126,13 @@ func HandleDetailWithK8s[T any](
 	operation func(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (T, error),
 	successMessage string,
 ) {
-	clientset, _, err := getK8sClient()
+	ctx := GetRequestContext(c)
+	clientset, _, err := getK8sClient(ctx)
 	if err != nil {
 		middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
 		return
 	}
 
-	ctx := GetRequestContext(c)
 	namespace := c.Param("namespace")
 	name := c.Param("name")
 
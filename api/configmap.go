@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/audit"
 	"github.com/nick0323/K8sVision/model"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +22,7 @@ func RegisterConfigMap(
 ) {
 	r.GET("/configmaps", getConfigMapList(logger, getK8sClient, listConfigMaps))
 	r.GET("/configmaps/:namespace/:name", getConfigMapDetail(logger, getK8sClient))
+	r.GET("/configmaps/:namespace/:name"+middleware.RevealPathSuffix, revealHandler(logger, getK8sClient, "configmaps", audit.ActionConfigMapReveal, fetchConfigMapData))
 }
 
 func getConfigMapList(
@@ -30,7 +32,7 @@ func getConfigMapList(
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.ConfigMapStatus, error) {
-			clientset, _, err := getK8sClient()
+			clientset, _, err := getK8sClient(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -44,12 +46,12 @@ func getConfigMapDetail(
 	getK8sClient K8sClientProvider,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientset, _, err := getK8sClient()
+		ctx := GetRequestContext(c)
+		clientset, _, err := getK8sClient(ctx)
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
 			return
 		}
-		ctx := GetRequestContext(c)
 		ns := c.Param("namespace")
 		name := c.Param("name")
 		configMap, err := clientset.CoreV1().ConfigMaps(ns).Get(ctx, name, metav1.GetOptions{})
@@ -58,10 +60,16 @@ func getConfigMapDetail(
 			return
 		}
 
-		keys := make([]string, 0, len(configMap.Data))
-		for key := range configMap.Data {
-			keys = append(keys, key)
+		// 匹配敏感规则的键只返回长度，明文需通过 reveal 接口获取
+		sizes := make(map[string]int, len(configMap.Data))
+		data := make(map[string]string, len(configMap.Data))
+		for key, value := range configMap.Data {
+			sizes[key] = len(value)
+			if !isSensitiveConfigMapKey(key) {
+				data[key] = value
+			}
 		}
+		keys, entries := buildDataEntries(sizes, isSensitiveConfigMapKey)
 
 		configMapDetail := model.ConfigMapDetail{
 			CommonResourceFields: model.CommonResourceFields{
@@ -75,8 +83,18 @@ func getConfigMapDetail(
 			},
 			DataCount: len(configMap.Data),
 			Keys:      keys,
-			Data:      configMap.Data,
+			Data:      data,
+			Entries:   entries,
 		}
 		middleware.ResponseSuccess(c, configMapDetail, DetailSuccessMessage, nil)
 	}
 }
+
+// fetchConfigMapData 读取ConfigMap的数据
+func fetchConfigMapData(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (map[string]string, error) {
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return configMap.Data, nil
+}
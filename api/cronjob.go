@@ -6,6 +6,7 @@ import (
 
 	"github.com/nick0323/K8sVision/api/middleware"
 	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -21,6 +22,7 @@ func RegisterCronJob(
 ) {
 	r.GET("/cronjobs", getCronJobList(logger, getK8sClient, listCronJobs))
 	r.GET("/cronjobs/:namespace/:name", getCronJobDetail(logger, getK8sClient))
+	r.GET("/cronjobs/:namespace/:name/timeline", getWorkloadTimeline(logger, getK8sClient, service.WorkloadCronJob))
 }
 
 func getCronJobList(
@@ -30,7 +32,7 @@ func getCronJobList(
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.CronJobStatus, error) {
-			clientset, _, err := getK8sClient()
+			clientset, _, err := getK8sClient(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -44,12 +46,12 @@ func getCronJobDetail(
 	getK8sClient K8sClientProvider,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientset, _, err := getK8sClient()
+		ctx := GetRequestContext(c)
+		clientset, _, err := getK8sClient(ctx)
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
 			return
 		}
-		ctx := GetRequestContext(c)
 		ns := c.Param("namespace")
 		name := c.Param("name")
 		cronjob, err := clientset.BatchV1().CronJobs(ns).Get(ctx, name, metav1.GetOptions{})
@@ -6,6 +6,7 @@ import (
 
 	"github.com/nick0323/K8sVision/api/middleware"
 	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -22,6 +23,7 @@ func RegisterDaemonSet(
 ) {
 	r.GET("/daemonsets", getDaemonSetList(logger, getK8sClient, listDaemonSets))
 	r.GET("/daemonsets/:namespace/:name", getDaemonSetDetail(logger, getK8sClient))
+	r.GET("/daemonsets/:namespace/:name/timeline", getWorkloadTimeline(logger, getK8sClient, service.WorkloadDaemonSet))
 }
 
 // getDaemonSetList 获取DaemonSet列表的处理函数
@@ -32,7 +34,7 @@ func getDaemonSetList(
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.DaemonSetStatus, error) {
-			clientset, _, err := getK8sClient()
+			clientset, _, err := getK8sClient(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -47,12 +49,12 @@ func getDaemonSetDetail(
 	getK8sClient K8sClientProvider,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientset, _, err := getK8sClient()
+		ctx := GetRequestContext(c)
+		clientset, _, err := getK8sClient(ctx)
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
 			return
 		}
-		ctx := GetRequestContext(c)
 		ns := c.Param("namespace")
 		name := c.Param("name")
 		ds, err := clientset.AppsV1().DaemonSets(ns).Get(ctx, name, metav1.GetOptions{})
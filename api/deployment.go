@@ -20,6 +20,7 @@ func RegisterDeployment(
 ) {
 	r.GET("/deployments", getDeploymentList(logger, getK8sClient, listDeployments))
 	r.GET("/deployments/:namespace/:name", getDeploymentDetail(logger, getK8sClient))
+	r.GET("/deployments/:namespace/:name/timeline", getWorkloadTimeline(logger, getK8sClient, service.WorkloadDeployment))
 }
 
 func getDeploymentList(
@@ -29,7 +30,7 @@ func getDeploymentList(
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.DeploymentStatus, error) {
-			clientset, _, err := getK8sClient()
+			clientset, _, err := getK8sClient(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -55,6 +56,12 @@ func getDeploymentDetail(
 					image = dep.Spec.Template.Spec.Containers[0].Image
 				}
 
+				// HPA 仅作为补充信息，无权限或查询失败时不影响详情返回
+				hpa, err := service.FindHPAForTarget(ctx, clientset, namespace, service.WorkloadDeployment, name)
+				if err != nil {
+					logger.Debug("查询Deployment关联的HPA失败", zap.String("namespace", namespace), zap.String("name", name), zap.Error(err))
+				}
+
 				return model.DeploymentDetail{
 					WorkloadCommonFields: model.WorkloadCommonFields{
 						CommonResourceFields: model.CommonResourceFields{
@@ -73,6 +80,7 @@ func getDeploymentDetail(
 					},
 					Replicas: *dep.Spec.Replicas,
 					Strategy: string(dep.Spec.Strategy.Type),
+					HPA:      hpa,
 				}, nil
 			}, DetailSuccessMessage)
 	}
@@ -0,0 +1,284 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	rpprof "runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/archive"
+	"github.com/nick0323/K8sVision/audit"
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/version"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DebugPprofPath pprof路由前缀（位于管理员路由组下）
+const DebugPprofPath = "/admin/debug/pprof"
+
+// 运行时快照中保留的最近GC暂停次数
+const recentGCPauses = 10
+
+// LogLevelRequest 修改日志级别请求
+type LogLevelRequest struct {
+	Level string `json:"level" binding:"required"`
+}
+
+// ProfilingRates 互斥锁与阻塞分析的采样设置，均为0时 mutex、block 剖析数据为空
+// MutexFraction: 每 N 次锁竞争采样一次；BlockRate: 每阻塞 N 纳秒采样一次
+type ProfilingRates struct {
+	MutexFraction int `json:"mutexFraction"`
+	BlockRate     int `json:"blockRate"`
+}
+
+// RuntimeMemory 内存统计，单位为字节
+type RuntimeMemory struct {
+	HeapAlloc   uint64 `json:"heapAlloc"`
+	HeapInuse   uint64 `json:"heapInuse"`
+	HeapIdle    uint64 `json:"heapIdle"`
+	HeapObjects uint64 `json:"heapObjects"`
+	StackInuse  uint64 `json:"stackInuse"`
+	Sys         uint64 `json:"sys"`
+	TotalAlloc  uint64 `json:"totalAlloc"`
+	Mallocs     uint64 `json:"mallocs"`
+	Frees       uint64 `json:"frees"`
+}
+
+// RuntimeGC 垃圾回收统计
+type RuntimeGC struct {
+	NumGC         int64    `json:"numGC"`
+	LastGC        string   `json:"lastGC"`
+	PauseTotal    string   `json:"pauseTotal"`
+	RecentPauses  []string `json:"recentPauses"`
+	NextGC        uint64   `json:"nextGC"`
+	GCCPUFraction float64  `json:"gcCPUFraction"`
+	MemoryLimit   int64    `json:"memoryLimit"`
+}
+
+// RuntimeSnapshot 进程运行时快照
+type RuntimeSnapshot struct {
+	Version       version.Info           `json:"version"`
+	Uptime        string                 `json:"uptime"`
+	GOMAXPROCS    int                    `json:"gomaxprocs"`
+	NumCPU        int                    `json:"numCPU"`
+	Goroutines    int                    `json:"goroutines"`
+	Memory        RuntimeMemory          `json:"memory"`
+	GC            RuntimeGC              `json:"gc"`
+	LogLevel      string                 `json:"logLevel"`
+	Profiling     ProfilingRates         `json:"profiling"`
+	Informers     []model.InformerStatus `json:"informers"`
+	Caches        map[string]interface{} `json:"caches"`
+	GoroutineDump string                 `json:"goroutineDump,omitempty"`
+}
+
+var (
+	profilingMutex sync.Mutex
+	profilingRates ProfilingRates
+)
+
+// RegisterDiagnosticsAdmin 注册pprof剖析、运行时快照和日志级别路由（仅管理员）
+// level 为日志实际使用的 AtomicLevel，修改后立即生效；cacheStats 返回各缓存的统计
+func RegisterDiagnosticsAdmin(r *gin.RouterGroup, logger *zap.Logger, level zap.AtomicLevel, cacheStats func() map[string]interface{}) {
+	r.GET(DebugPprofPath+"/", gin.WrapF(pprof.Index))
+	r.GET(DebugPprofPath+"/cmdline", gin.WrapF(pprof.Cmdline))
+	r.GET(DebugPprofPath+"/profile", gin.WrapF(pprof.Profile))
+	r.GET(DebugPprofPath+"/symbol", gin.WrapF(pprof.Symbol))
+	r.POST(DebugPprofPath+"/symbol", gin.WrapF(pprof.Symbol))
+	r.GET(DebugPprofPath+"/trace", gin.WrapF(pprof.Trace))
+	r.GET(DebugPprofPath+"/:profile", getPprofProfile(logger))
+	r.GET("/admin/debug/profiling", getProfilingRates(logger))
+	r.PUT("/admin/debug/profiling", setProfilingRates(logger))
+
+	r.GET("/admin/debug/runtime", getRuntimeSnapshot(logger, level, cacheStats))
+	r.GET("/admin/log-level", getLogLevel(logger, level))
+	r.PUT("/admin/log-level", setLogLevel(logger, level))
+}
+
+// getPprofProfile 按名称输出 heap、goroutine、mutex、block、allocs、threadcreate 等剖析数据
+// pprof.Index 依赖固定的 /debug/pprof/ 前缀解析名称，因此这里直接查找对应的 Handler
+func getPprofProfile(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("profile")
+		if rpprof.Lookup(name) == nil {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeResourceNotFound,
+				Message: model.GetErrorMessage(model.CodeResourceNotFound),
+				Details: "未知的剖析类型: " + name,
+			}, http.StatusNotFound)
+			return
+		}
+		pprof.Handler(name).ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+func getProfilingRates(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		profilingMutex.Lock()
+		rates := profilingRates
+		profilingMutex.Unlock()
+		middleware.ResponseSuccess(c, rates, SuccessMessage, nil)
+	}
+}
+
+// setProfilingRates 开启或关闭互斥锁与阻塞剖析采样，分析结束后应设回0以避免额外开销
+func setProfilingRates(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ProfilingRates
+		if err := c.ShouldBindJSON(&req); err != nil || req.MutexFraction < 0 || req.BlockRate < 0 {
+			details := "mutexFraction 和 blockRate 不能为负数"
+			if err != nil {
+				details = err.Error()
+			}
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeBadRequest,
+				Message: "请求参数格式错误",
+				Details: details,
+			}, http.StatusBadRequest)
+			return
+		}
+
+		profilingMutex.Lock()
+		runtime.SetMutexProfileFraction(req.MutexFraction)
+		runtime.SetBlockProfileRate(req.BlockRate)
+		profilingRates = req
+		profilingMutex.Unlock()
+
+		logger.Info("修改剖析采样设置",
+			zap.String("operator", c.GetString("username")),
+			zap.Int("mutexFraction", req.MutexFraction),
+			zap.Int("blockRate", req.BlockRate),
+		)
+		middleware.ResponseSuccess(c, req, UpdateSuccessMessage, nil)
+	}
+}
+
+// getRuntimeSnapshot 返回GC、内存、Informer和缓存等运行时信息，goroutines=true 时附带完整协程堆栈
+func getRuntimeSnapshot(logger *zap.Logger, level zap.AtomicLevel, cacheStats func() map[string]interface{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+		var gc debug.GCStats
+		debug.ReadGCStats(&gc)
+
+		snapshot := RuntimeSnapshot{
+			Version:    version.Get(),
+			Uptime:     time.Since(processStartTime).Round(time.Second).String(),
+			GOMAXPROCS: runtime.GOMAXPROCS(0),
+			NumCPU:     runtime.NumCPU(),
+			Goroutines: runtime.NumGoroutine(),
+			Memory: RuntimeMemory{
+				HeapAlloc:   mem.HeapAlloc,
+				HeapInuse:   mem.HeapInuse,
+				HeapIdle:    mem.HeapIdle,
+				HeapObjects: mem.HeapObjects,
+				StackInuse:  mem.StackInuse,
+				Sys:         mem.Sys,
+				TotalAlloc:  mem.TotalAlloc,
+				Mallocs:     mem.Mallocs,
+				Frees:       mem.Frees,
+			},
+			GC: RuntimeGC{
+				NumGC:         gc.NumGC,
+				PauseTotal:    gc.PauseTotal.String(),
+				RecentPauses:  make([]string, 0, recentGCPauses),
+				NextGC:        mem.NextGC,
+				GCCPUFraction: mem.GCCPUFraction,
+				MemoryLimit:   debug.SetMemoryLimit(-1), // 负数只读取当前值
+			},
+			LogLevel:  level.Level().String(),
+			Informers: archive.Informers(),
+			Caches:    cacheStats(),
+		}
+		if !gc.LastGC.IsZero() {
+			snapshot.GC.LastGC = gc.LastGC.Format(model.TimeFormat)
+		}
+		for i, pause := range gc.Pause {
+			if i >= recentGCPauses {
+				break
+			}
+			snapshot.GC.RecentPauses = append(snapshot.GC.RecentPauses, pause.String())
+		}
+		if snapshot.Informers == nil {
+			snapshot.Informers = []model.InformerStatus{}
+		}
+
+		profilingMutex.Lock()
+		snapshot.Profiling = profilingRates
+		profilingMutex.Unlock()
+
+		if c.Query("goroutines") == "true" {
+			var buf bytes.Buffer
+			if err := rpprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+				middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
+				return
+			}
+			snapshot.GoroutineDump = buf.String()
+		}
+
+		middleware.ResponseSuccess(c, snapshot, SuccessMessage, nil)
+	}
+}
+
+func getLogLevel(logger *zap.Logger, level zap.AtomicLevel) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		middleware.ResponseSuccess(c, gin.H{"level": level.Level().String()}, SuccessMessage, nil)
+	}
+}
+
+// setLogLevel 运行时修改日志级别，立即对所有日志生效，重启后恢复为配置文件中的级别
+func setLogLevel(logger *zap.Logger, level zap.AtomicLevel) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req LogLevelRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeBadRequest,
+				Message: "请求参数格式错误",
+				Details: err.Error(),
+			}, http.StatusBadRequest)
+			return
+		}
+
+		newLevel, err := zapcore.ParseLevel(strings.ToLower(req.Level))
+		if err != nil || newLevel > zapcore.ErrorLevel {
+			middleware.RecordAudit(c, &audit.Event{
+				Action:   audit.ActionLogLevelChange,
+				Resource: audit.Resource{Kind: "loglevel"},
+				Outcome:  audit.OutcomeFailure,
+				Reason:   "无效的日志级别: " + req.Level,
+			})
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeValidationFailed,
+				Message: "请求参数错误",
+				Details: "level 只能为 debug、info、warn 或 error",
+			}, http.StatusBadRequest)
+			return
+		}
+
+		oldLevel := level.Level()
+		level.SetLevel(newLevel)
+
+		middleware.RecordAudit(c, &audit.Event{
+			Action:   audit.ActionLogLevelChange,
+			Resource: audit.Resource{Kind: "loglevel", Name: newLevel.String()},
+			Outcome:  audit.OutcomeSuccess,
+			Details: map[string]interface{}{
+				"from": oldLevel.String(),
+				"to":   newLevel.String(),
+			},
+		})
+		logger.Warn("日志级别已修改",
+			zap.String("operator", c.GetString("username")),
+			zap.String("from", oldLevel.String()),
+			zap.String("to", newLevel.String()),
+		)
+		middleware.ResponseSuccess(c, gin.H{"level": newLevel.String()}, UpdateSuccessMessage, nil)
+	}
+}
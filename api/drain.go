@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/audit"
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// setNodeSchedulable 封锁（cordon）或解除封锁（uncordon）节点
+func setNodeSchedulable(logger *zap.Logger, getK8sClient K8sClientProvider, unschedulable bool) gin.HandlerFunc {
+	action := audit.ActionNodeUncordon
+	if unschedulable {
+		action = audit.ActionNodeCordon
+	}
+	return func(c *gin.Context) {
+		ctx := GetRequestContext(c)
+		name := c.Param("name")
+		resource := audit.Resource{Kind: "node", Name: name}
+
+		clientset, _, err := getK8sClient(ctx)
+		if err == nil {
+			err = service.CordonNode(ctx, clientset, name, unschedulable)
+		}
+		if err != nil {
+			middleware.RecordAudit(c, &audit.Event{
+				Action:   action,
+				Resource: resource,
+				Outcome:  audit.OutcomeFailure,
+				Reason:   err.Error(),
+			})
+			respondDrainError(c, logger, err)
+			return
+		}
+
+		middleware.RecordAudit(c, &audit.Event{
+			Action:   action,
+			Resource: resource,
+			Outcome:  audit.OutcomeSuccess,
+		})
+		middleware.ResponseSuccess(c, gin.H{"node": name, "unschedulable": unschedulable}, UpdateSuccessMessage, nil)
+	}
+}
+
+// startDrain 封锁节点并启动后台驱逐任务，请求体可省略，返回任务ID及初始进度
+func startDrain(logger *zap.Logger, getK8sClient K8sClientProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var opts model.DrainOptions
+		if err := c.ShouldBindJSON(&opts); err != nil && !errors.Is(err, io.EOF) {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeBadRequest,
+				Message: "请求参数格式错误",
+				Details: err.Error(),
+			}, http.StatusBadRequest)
+			return
+		}
+
+		ctx := GetRequestContext(c)
+		name := c.Param("name")
+		resource := audit.Resource{Kind: "node", Name: name}
+		details := map[string]interface{}{
+			"timeoutSeconds":     opts.TimeoutSeconds,
+			"ignoreDaemonSets":   opts.IgnoreDaemonSets,
+			"deleteEmptyDirData": opts.DeleteEmptyDirData,
+			"force":              opts.Force,
+		}
+		if opts.GracePeriodSeconds != nil {
+			details["gracePeriodSeconds"] = *opts.GracePeriodSeconds
+		}
+
+		var job *model.DrainJob
+		clientset, _, err := getK8sClient(ctx)
+		if err == nil {
+			job, err = service.StartDrain(ctx, clientset, name, opts, c.GetString("username"))
+		}
+		if err != nil {
+			middleware.RecordAudit(c, &audit.Event{
+				Action:   audit.ActionNodeDrain,
+				Resource: resource,
+				Outcome:  audit.OutcomeFailure,
+				Reason:   err.Error(),
+				Details:  details,
+			})
+			respondDrainError(c, logger, err)
+			return
+		}
+
+		details["jobId"] = job.ID
+		middleware.RecordAudit(c, &audit.Event{
+			Action:   audit.ActionNodeDrain,
+			Resource: resource,
+			Outcome:  audit.OutcomeSuccess,
+			Details:  details,
+		})
+		middleware.ResponseSuccess(c, job, CreateSuccessMessage, nil)
+	}
+}
+
+// canAccessDrainJob 驱逐任务仅对创建者和管理员可见，查看与取消使用同一规则
+func canAccessDrainJob(c *gin.Context, job *model.DrainJob) bool {
+	return c.GetString("role") == model.RoleAdmin || c.GetString("username") == job.CreatedBy
+}
+
+// listDrainJobs 列出当前用户可见的驱逐任务
+func listDrainJobs(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.DrainJob, error) {
+			jobs := service.ListDrainJobs()
+			visible := make([]model.DrainJob, 0, len(jobs))
+			for i := range jobs {
+				if canAccessDrainJob(c, &jobs[i]) {
+					visible = append(visible, jobs[i])
+				}
+			}
+			return visible, nil
+		}, ListSuccessMessage)
+	}
+}
+
+// getDrainJob 查看驱逐任务进度，对无权查看的用户与任务不存在时一样返回404
+func getDrainJob(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		job, err := service.GetDrainJob(c.Param("id"))
+		if err == nil && !canAccessDrainJob(c, job) {
+			err = service.ErrDrainJobNotFound
+		}
+		if err != nil {
+			respondDrainError(c, logger, err)
+			return
+		}
+		middleware.ResponseSuccess(c, job, DetailSuccessMessage, nil)
+	}
+}
+
+// cancelDrainJob 取消驱逐任务，仅任务创建者和管理员可以取消
+func cancelDrainJob(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		job, err := service.GetDrainJob(id)
+		if err != nil {
+			respondDrainError(c, logger, err)
+			return
+		}
+		resource := audit.Resource{Kind: "node", Name: job.Node}
+		if !canAccessDrainJob(c, job) {
+			middleware.RecordAudit(c, &audit.Event{
+				Action:   audit.ActionNodeDrainCancel,
+				Resource: resource,
+				Outcome:  audit.OutcomeDenied,
+				Reason:   "只有任务创建者或管理员可以取消",
+				Details:  map[string]interface{}{"jobId": id},
+			})
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodePermissionDenied,
+				Message: model.GetErrorMessage(model.CodePermissionDenied),
+				Details: "只有任务创建者或管理员可以取消",
+			}, http.StatusForbidden)
+			return
+		}
+
+		job, err = service.CancelDrainJob(id)
+		if err != nil {
+			middleware.RecordAudit(c, &audit.Event{
+				Action:   audit.ActionNodeDrainCancel,
+				Resource: resource,
+				Outcome:  audit.OutcomeFailure,
+				Reason:   err.Error(),
+				Details:  map[string]interface{}{"jobId": id},
+			})
+			respondDrainError(c, logger, err)
+			return
+		}
+		middleware.RecordAudit(c, &audit.Event{
+			Action:   audit.ActionNodeDrainCancel,
+			Resource: resource,
+			Outcome:  audit.OutcomeSuccess,
+			Details:  map[string]interface{}{"jobId": id},
+		})
+		middleware.ResponseSuccess(c, job, SuccessMessage, nil)
+	}
+}
+
+// respondDrainError 将节点操作与驱逐任务的错误映射为对应的状态码
+func respondDrainError(c *gin.Context, logger *zap.Logger, err error) {
+	httpCode, code := 0, 0
+	switch {
+	case errors.Is(err, service.ErrDrainJobNotFound):
+		httpCode, code = http.StatusNotFound, model.CodeResourceNotFound
+	case errors.Is(err, service.ErrDrainJobFinished), errors.Is(err, service.ErrDrainInProgress):
+		httpCode, code = http.StatusConflict, model.CodeConflict
+	case errors.Is(err, service.ErrDrainNotAllowed), errors.Is(err, service.ErrInvalidDrainOpts):
+		httpCode, code = http.StatusBadRequest, model.CodeValidationFailed
+	case apierrors.IsNotFound(err):
+		middleware.ResponseError(c, logger, err, http.StatusNotFound)
+		return
+	default:
+		middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
+		return
+	}
+	middleware.ResponseError(c, logger, &model.APIError{
+		Code:    code,
+		Message: model.GetErrorMessage(code),
+		Details: err.Error(),
+	}, httpCode)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/archive"
 	"github.com/nick0323/K8sVision/model"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +16,9 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+// 事件聚合默认统计范围
+const defaultEventAggregateRange = 24 * time.Hour
+
 // RegisterEvent 注册 Event 相关路由
 func RegisterEvent(
 	r *gin.RouterGroup,
@@ -23,18 +27,40 @@ func RegisterEvent(
 	listEvents func(context.Context, *kubernetes.Clientset, string) ([]model.EventStatus, error),
 ) {
 	r.GET("/events", getEventList(logger, getK8sClient, listEvents))
+	r.GET("/events/aggregate", getEventAggregate(logger, getK8sClient))
 	r.GET("/events/:namespace/:name", getEventDetail(logger, getK8sClient))
 }
 
 // getEventList 获取Event列表的处理函数
+// 指定 from/to 时查询事件归档，可查到apiserver已清理的历史事件，并支持按 type 过滤
 func getEventList(
 	logger *zap.Logger,
 	getK8sClient K8sClientProvider,
 	listEvents func(context.Context, *kubernetes.Clientset, string) ([]model.EventStatus, error),
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if c.Query("from") != "" || c.Query("to") != "" {
+			query, ok := parseEventArchiveQuery(c, logger)
+			if !ok {
+				return
+			}
+			HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.EventStatus, error) {
+				st, err := authorizeEventArchive(ctx, getK8sClient, query.Namespace)
+				if err != nil {
+					return nil, err
+				}
+				records := st.Query(query)
+				events := make([]model.EventStatus, 0, len(records))
+				for i := range records {
+					events = append(events, records[i].Status())
+				}
+				return events, nil
+			}, ListSuccessMessage)
+			return
+		}
+
 		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.EventStatus, error) {
-			clientset, _, err := getK8sClient()
+			clientset, _, err := getK8sClient(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -49,18 +75,89 @@ func getEventList(
 	}
 }
 
+// getEventAggregate 按 by=reason|object|namespace 汇总归档事件，from/to 默认为最近24小时
+func getEventAggregate(logger *zap.Logger, getK8sClient K8sClientProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		by := c.DefaultQuery("by", model.EventGroupByReason)
+		switch by {
+		case model.EventGroupByReason, model.EventGroupByObject, model.EventGroupByNamespace:
+		default:
+			middleware.ResponseError(c, logger, invalidQueryParam("by", "仅支持 reason、object、namespace"), http.StatusBadRequest)
+			return
+		}
+		query, ok := parseEventArchiveQuery(c, logger)
+		if !ok {
+			return
+		}
+		if query.From.IsZero() {
+			query.From = time.Now().Add(-defaultEventAggregateRange)
+		}
+
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.EventAggregate, error) {
+			st, err := authorizeEventArchive(ctx, getK8sClient, query.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			return st.Aggregate(query, by)
+		}, ListSuccessMessage)
+	}
+}
+
+// parseEventArchiveQuery 解析归档查询参数，时间支持RFC3339或Unix秒
+func parseEventArchiveQuery(c *gin.Context, logger *zap.Logger) (archive.Query, bool) {
+	query := archive.Query{
+		Namespace: c.Query("namespace"),
+		Type:      c.Query("type"),
+	}
+	var err error
+	if query.From, err = parseTimeParam(c.Query("from")); err != nil {
+		middleware.ResponseError(c, logger, invalidQueryParam("from", err.Error()), http.StatusBadRequest)
+		return query, false
+	}
+	if query.To, err = parseTimeParam(c.Query("to")); err != nil {
+		middleware.ResponseError(c, logger, invalidQueryParam("to", err.Error()), http.StatusBadRequest)
+		return query, false
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		middleware.ResponseError(c, logger, invalidQueryParam("to", "不能早于 from"), http.StatusBadRequest)
+		return query, false
+	}
+	return query, true
+}
+
+// authorizeEventArchive 归档由服务自身身份写入，读取前先以当前用户身份列举事件，
+// 确保用户在集群RBAC中有权查看该命名空间（为空时为全部命名空间）的事件
+func authorizeEventArchive(ctx context.Context, getK8sClient K8sClientProvider, namespace string) (*archive.Store, error) {
+	st := archive.Default()
+	if st == nil {
+		return nil, &model.APIError{
+			Code:    model.CodeServiceUnavailable,
+			Message: model.GetErrorMessage(model.CodeServiceUnavailable),
+			Details: "事件归档未启用",
+		}
+	}
+	clientset, _, err := getK8sClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
 // getEventDetail 获取Event详情的处理函数
 func getEventDetail(
 	logger *zap.Logger,
 	getK8sClient K8sClientProvider,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientset, _, err := getK8sClient()
+		ctx := GetRequestContext(c)
+		clientset, _, err := getK8sClient(ctx)
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
 			return
 		}
-		ctx := GetRequestContext(c)
 		ns := c.Param("namespace")
 		name := c.Param("name")
 		event, err := clientset.CoreV1().Events(ns).Get(ctx, name, metav1.GetOptions{})
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nick0323/K8sVision/version"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 探针路径
+const (
+	LivezPath  = "/livez"
+	ReadyzPath = "/readyz"
+)
+
+// 检查结果状态
+const (
+	CheckStatusOK       = "ok"
+	CheckStatusFailed   = "failed"
+	CheckStatusDegraded = "degraded"
+)
+
+// readinessCheckTimeout 单项检查超时，需小于探针的 timeoutSeconds
+const readinessCheckTimeout = 2 * time.Second
+
+// ReadinessCheck 就绪检查项
+// Optional 为 true 时失败只标记为 degraded，不影响就绪状态（如 metrics-server 不可用时仍可浏览资源）
+type ReadinessCheck struct {
+	Name     string
+	Optional bool
+	Check    func(ctx context.Context) (detail string, err error)
+}
+
+// CheckResult 单项检查结果
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Optional  bool    `json:"optional,omitempty"`
+	LatencyMs float64 `json:"latencyMs"`
+	Detail    string  `json:"detail,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ProbeResponse 探针响应，Checks 仅在 ?verbose 时返回
+type ProbeResponse struct {
+	Status    string        `json:"status"`
+	Timestamp int64         `json:"timestamp"`
+	Version   *version.Info `json:"version,omitempty"`
+	Uptime    string        `json:"uptime,omitempty"`
+	Checks    []CheckResult `json:"checks,omitempty"`
+}
+
+var processStartTime = time.Now()
+
+// RegisterHealth 注册存活与就绪探针（无需认证）
+// /livez 只反映进程是否可响应，不检查外部依赖，避免apiserver抖动导致容器被重启
+// /readyz 检查各依赖，任一必需项失败时返回 503
+func RegisterHealth(r *gin.RouterGroup, logger *zap.Logger, checks []ReadinessCheck) {
+	r.GET(LivezPath, livezHandler())
+	r.GET(ReadyzPath, readyzHandler(logger, checks))
+}
+
+func livezHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp := ProbeResponse{Status: CheckStatusOK, Timestamp: time.Now().Unix()}
+		if isVerbose(c) {
+			info := version.Get()
+			resp.Version = &info
+			resp.Uptime = time.Since(processStartTime).Round(time.Second).String()
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func readyzHandler(logger *zap.Logger, checks []ReadinessCheck) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		results := runReadinessChecks(c.Request.Context(), checks)
+
+		status := CheckStatusOK
+		for _, result := range results {
+			if result.Status == CheckStatusFailed {
+				status = CheckStatusFailed
+				break
+			}
+			if result.Status == CheckStatusDegraded {
+				status = CheckStatusDegraded
+			}
+		}
+
+		httpStatus := http.StatusOK
+		if status == CheckStatusFailed {
+			httpStatus = http.StatusServiceUnavailable
+			logger.Warn("就绪检查失败", zap.Any("checks", results))
+		}
+
+		resp := ProbeResponse{Status: status, Timestamp: time.Now().Unix()}
+		if isVerbose(c) {
+			info := version.Get()
+			resp.Version = &info
+			resp.Uptime = time.Since(processStartTime).Round(time.Second).String()
+			resp.Checks = results
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(httpStatus, resp)
+	}
+}
+
+// runReadinessChecks 并发执行所有检查，结果顺序与注册顺序一致
+func runReadinessChecks(ctx context.Context, checks []ReadinessCheck) []CheckResult {
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check ReadinessCheck) {
+			defer wg.Done()
+			results[i] = runReadinessCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+	return results
+}
+
+func runReadinessCheck(ctx context.Context, check ReadinessCheck) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check.Check(ctx)
+	result := CheckResult{
+		Name:      check.Name,
+		Status:    CheckStatusOK,
+		Optional:  check.Optional,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Detail:    detail,
+	}
+	if err != nil {
+		result.Error = err.Error()
+		result.Status = CheckStatusFailed
+		if check.Optional {
+			result.Status = CheckStatusDegraded
+		}
+	}
+	return result
+}
+
+// isVerbose 兼容 ?verbose 与 ?verbose=true 两种写法
+func isVerbose(c *gin.Context) bool {
+	value, exists := c.GetQuery("verbose")
+	if !exists {
+		return false
+	}
+	if value == "" {
+		return true
+	}
+	verbose, _ := strconv.ParseBool(value)
+	return verbose
+}
+
+// APIServerCheck 通过 /version 检查apiserver可达性，使用服务自身身份
+func APIServerCheck(getK8sClient K8sClientProvider) ReadinessCheck {
+	return ReadinessCheck{
+		Name: "apiserver",
+		Check: func(ctx context.Context) (string, error) {
+			clientset, _, err := getK8sClient(ctx)
+			if err != nil {
+				return "", err
+			}
+			body, err := clientset.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+			if err != nil {
+				return "", err
+			}
+			var info struct {
+				GitVersion string `json:"gitVersion"`
+			}
+			if err := json.Unmarshal(body, &info); err != nil {
+				return "", fmt.Errorf("解析版本信息失败: %w", err)
+			}
+			return info.GitVersion, nil
+		},
+	}
+}
+
+// MetricsServerCheck 检查metrics-server是否可用，不可用时仅影响资源用量展示
+func MetricsServerCheck(getK8sClient K8sClientProvider) ReadinessCheck {
+	return ReadinessCheck{
+		Name:     "metrics-server",
+		Optional: true,
+		Check: func(ctx context.Context) (string, error) {
+			_, metricsClient, err := getK8sClient(ctx)
+			if err != nil {
+				return "", err
+			}
+			_, err = metricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{Limit: 1})
+			return "", err
+		},
+	}
+}
+
+// CacheBackend 缓存健康检查所需的最小接口
+type CacheBackend interface {
+	IsEnabled() bool
+	SetWithTTL(key string, value interface{}, ttl time.Duration)
+	Get(key string) (interface{}, bool)
+	Delete(key string)
+}
+
+// CacheCheck 对缓存后端做一次写入、读取、删除
+func CacheCheck(backend CacheBackend, enabled bool) ReadinessCheck {
+	return ReadinessCheck{
+		Name: "cache",
+		Check: func(ctx context.Context) (string, error) {
+			if !enabled {
+				return "disabled", nil
+			}
+			if !backend.IsEnabled() {
+				return "", fmt.Errorf("默认缓存不可用")
+			}
+			key := "readyz:" + strconv.FormatInt(time.Now().UnixNano(), 36)
+			backend.SetWithTTL(key, key, time.Minute)
+			defer backend.Delete(key)
+			if value, ok := backend.Get(key); !ok || value != key {
+				return "", fmt.Errorf("缓存读写校验失败")
+			}
+			return "", nil
+		},
+	}
+}
+
+// ConfigCheck 校验当前生效的配置（热加载后同样适用）
+func ConfigCheck(validate func() error) ReadinessCheck {
+	return ReadinessCheck{
+		Name: "config",
+		Check: func(ctx context.Context) (string, error) {
+			return "", validate()
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RegisterHPA 注册 HorizontalPodAutoscaler 相关路由
+func RegisterHPA(
+	r *gin.RouterGroup,
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listHPAs func(context.Context, *kubernetes.Clientset, string) ([]model.HPAStatus, error),
+) {
+	r.GET("/hpas", getHPAList(logger, getK8sClient, listHPAs))
+	r.GET("/hpas/:namespace/:name", getHPADetail(logger, getK8sClient))
+}
+
+func getHPAList(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listHPAs func(context.Context, *kubernetes.Clientset, string) ([]model.HPAStatus, error),
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.HPAStatus, error) {
+			clientset, _, err := getK8sClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return listHPAs(ctx, clientset, params.Namespace)
+		}, ListSuccessMessage)
+	}
+}
+
+func getHPADetail(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleDetailWithK8s(c, logger, getK8sClient, service.GetHPADetail, DetailSuccessMessage)
+	}
+}
@@ -22,6 +22,7 @@ func RegisterJob(
 ) {
 	r.GET("/jobs", getJobList(logger, getK8sClient, listJobs))
 	r.GET("/jobs/:namespace/:name", getJobDetail(logger, getK8sClient))
+	r.GET("/jobs/:namespace/:name/timeline", getWorkloadTimeline(logger, getK8sClient, service.WorkloadJob))
 }
 
 func getJobList(
@@ -31,7 +32,7 @@ func getJobList(
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.JobStatus, error) {
-			clientset, _, err := getK8sClient()
+			clientset, _, err := getK8sClient(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -45,12 +46,12 @@ func getJobDetail(
 	getK8sClient K8sClientProvider,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientset, _, err := getK8sClient()
+		ctx := GetRequestContext(c)
+		clientset, _, err := getK8sClient(ctx)
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
 			return
 		}
-		ctx := GetRequestContext(c)
 		ns := c.Param("namespace")
 		name := c.Param("name")
 		job, err := clientset.BatchV1().Jobs(ns).Get(ctx, name, metav1.GetOptions{})
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RegisterLimitRange 注册 LimitRange 相关路由
+func RegisterLimitRange(
+	r *gin.RouterGroup,
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listLimitRanges func(context.Context, *kubernetes.Clientset, string) ([]model.LimitRangeStatus, error),
+) {
+	r.GET("/limitranges", getLimitRangeList(logger, getK8sClient, listLimitRanges))
+	r.GET("/limitranges/:namespace/:name", getLimitRangeDetail(logger, getK8sClient))
+}
+
+func getLimitRangeList(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listLimitRanges func(context.Context, *kubernetes.Clientset, string) ([]model.LimitRangeStatus, error),
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.LimitRangeStatus, error) {
+			clientset, _, err := getK8sClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return listLimitRanges(ctx, clientset, params.Namespace)
+		}, ListSuccessMessage)
+	}
+}
+
+func getLimitRangeDetail(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleDetailWithK8s(c, logger, getK8sClient, service.GetLimitRangeDetail, DetailSuccessMessage)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/audit"
 	"github.com/nick0323/K8sVision/model"
 	"go.uber.org/zap"
 )
@@ -19,6 +20,8 @@ var (
 	authManager *AuthManager
 )
 
+const twoFactorChallengeAudience = "k8svision-2fa"
+
 // generateJTI 生成JWT ID
 func generateJTI() string {
 	bytes := make([]byte, 16)
@@ -101,6 +104,12 @@ func LoginHandler(logger *zap.Logger) gin.HandlerFunc {
 		if authManager != nil && authManager.IsLocked(username, clientIP) {
 			remainingAttempts := authManager.GetRemainingAttempts(username, clientIP)
 			lockTime := authManager.GetLockTime(username, clientIP)
+			middleware.RecordAudit(c, &audit.Event{
+				Actor:   username,
+				Action:  audit.ActionLogin,
+				Outcome: audit.OutcomeDenied,
+				Reason:  "locked",
+			})
 			middleware.ResponseError(c, logger, &model.APIError{
 				Code:    model.CodeRequestTimeout,
 				Message: "登录失败次数过多，请稍后再试",
@@ -127,6 +136,42 @@ func LoginHandler(logger *zap.Logger) gin.HandlerFunc {
 		}
 
 		if usernameMatch && passwordMatch {
+			// 已启用双因素认证：返回短期挑战令牌，等待第二步验证
+			if twoFactorManager != nil && twoFactorManager.IsEnabled(username) {
+				challenge, err := issueTwoFactorChallenge(username)
+				if err != nil {
+					logger.Error("双因素认证挑战生成失败",
+						zap.String("username", username),
+						zap.Error(err),
+					)
+					middleware.ResponseError(c, logger, &model.APIError{
+						Code:    model.CodeAuthError,
+						Message: model.GetErrorMessage(model.CodeAuthError),
+						Details: "Token生成失败",
+					}, http.StatusInternalServerError)
+					return
+				}
+
+				middleware.RecordAudit(c, &audit.Event{
+					Actor:   username,
+					Action:  audit.ActionLogin,
+					Outcome: audit.OutcomeSuccess,
+					Reason:  "2fa_challenge",
+				})
+
+				logger.Info("密码验证通过，等待双因素认证",
+					zap.String("username", req.Username),
+					zap.String("clientIP", c.ClientIP()),
+					zap.String("event", "login_2fa_challenge"),
+				)
+
+				middleware.ResponseSuccess(c, gin.H{
+					"twoFactorRequired": true,
+					"challengeToken":    challenge,
+				}, "需要双因素认证", nil)
+				return
+			}
+
 			logger.Info("用户登录成功",
 				zap.String("username", req.Username),
 				zap.String("clientIP", c.ClientIP()),
@@ -134,18 +179,13 @@ func LoginHandler(logger *zap.Logger) gin.HandlerFunc {
 				zap.String("event", "login_success"),
 			)
 
-			secret := configManager.GetJWTSecret()
-			authConfig := configManager.GetAuthConfig()
+			// 强制2FA但尚未绑定：签发仅允许访问绑定接口的受限会话
+			scope := ""
+			if twoFactorManager != nil && twoFactorManager.IsEnforced() {
+				scope = model.TokenScopeTwoFactorSetup
+			}
 
-			token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-				"username": req.Username,
-				"iat":      time.Now().Unix(),                                // 签发时间
-				"exp":      time.Now().Add(authConfig.SessionTimeout).Unix(), // 从配置读取过期时间
-				"iss":      "k8svision",                                      // 签发者
-				"aud":      "k8svision-client",                               // 受众
-				"jti":      generateJTI(),                                    // JWT ID，用于撤销
-			})
-			tokenString, err := token.SignedString(secret)
+			tokenString, err := issueSessionToken(username, scope)
 			if err != nil {
 				logger.Error("Token生成失败",
 					zap.String("username", req.Username),
@@ -167,9 +207,20 @@ func LoginHandler(logger *zap.Logger) gin.HandlerFunc {
 				authManager.RecordSuccess(username, clientIP)
 			}
 
-			middleware.ResponseSuccess(c, map[string]string{
-				"token": tokenString,
-			}, "登录成功", nil)
+			middleware.RecordAudit(c, &audit.Event{
+				Actor:      username,
+				Role:       model.RoleAdmin,
+				AuthMethod: model.AuthMethodJWT,
+				Action:     audit.ActionLogin,
+				Outcome:    audit.OutcomeSuccess,
+				Details:    map[string]interface{}{"scope": scope},
+			})
+
+			data := gin.H{"token": tokenString}
+			if scope != "" {
+				data["twoFactorSetupRequired"] = true
+			}
+			middleware.ResponseSuccess(c, data, "登录成功", nil)
 			return
 		}
 
@@ -183,6 +234,13 @@ func LoginHandler(logger *zap.Logger) gin.HandlerFunc {
 		}
 
 		// 记录登录失败审计日志
+		middleware.RecordAudit(c, &audit.Event{
+			Actor:   username,
+			Action:  audit.ActionLogin,
+			Outcome: audit.OutcomeFailure,
+			Reason:  "invalid_credentials",
+			Details: map[string]interface{}{"remainingAttempts": remainingAttempts},
+		})
 		logger.Warn("用户登录失败",
 			zap.String("username", req.Username),
 			zap.String("clientIP", c.ClientIP()),
@@ -202,3 +260,190 @@ func LoginHandler(logger *zap.Logger) gin.HandlerFunc {
 		}, http.StatusUnauthorized)
 	}
 }
+
+// issueSessionToken 签发登录会话JWT；scope非空时为受限会话
+func issueSessionToken(username, scope string) (string, error) {
+	authConfig := configManager.GetAuthConfig()
+	claims := jwt.MapClaims{
+		"username": username,
+		"role":     model.RoleAdmin,
+		"iat":      time.Now().Unix(),                                // 签发时间
+		"exp":      time.Now().Add(authConfig.SessionTimeout).Unix(), // 从配置读取过期时间
+		"iss":      "k8svision",                                      // 签发者
+		"aud":      "k8svision-client",                               // 受众
+		"jti":      generateJTI(),                                    // JWT ID，用于撤销
+	}
+	if scope != "" {
+		claims["scope"] = scope
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(configManager.GetJWTSecret())
+}
+
+// issueTwoFactorChallenge 签发登录第二步使用的挑战令牌
+// 受众与会话令牌不同，不能直接用于访问API
+func issueTwoFactorChallenge(username string) (string, error) {
+	ttl := configManager.GetAuthConfig().TwoFactor.ChallengeTTL
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"username": username,
+		"iat":      time.Now().Unix(),
+		"exp":      time.Now().Add(ttl).Unix(),
+		"iss":      "k8svision",
+		"aud":      twoFactorChallengeAudience,
+		"jti":      generateJTI(),
+	})
+	return token.SignedString(configManager.GetJWTSecret())
+}
+
+// parseTwoFactorChallenge 解析挑战令牌并返回用户名
+func parseTwoFactorChallenge(challenge string) (string, error) {
+	token, err := jwt.Parse(challenge, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return configManager.GetJWTSecret(), nil
+	})
+	if err != nil || !token.Valid {
+		return "", fmt.Errorf("挑战令牌无效或已过期")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !claims.VerifyAudience(twoFactorChallengeAudience, true) {
+		return "", fmt.Errorf("挑战令牌受众无效")
+	}
+	username, ok := claims["username"].(string)
+	if !ok || username == "" {
+		return "", fmt.Errorf("挑战令牌缺少用户名")
+	}
+	return username, nil
+}
+
+// TwoFactorLoginHandler 登录第二步：校验TOTP验证码或恢复码
+// 错误验证码与错误密码共用 AuthManager 的失败计数和锁定策略
+func TwoFactorLoginHandler(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req model.TwoFactorLoginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeBadRequest,
+				Message: model.GetErrorMessage(model.CodeBadRequest),
+				Details: "请求参数格式错误",
+			}, http.StatusBadRequest)
+			return
+		}
+
+		if configManager == nil || twoFactorManager == nil {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeInternalServerError,
+				Message: "系统配置未初始化",
+			}, http.StatusInternalServerError)
+			return
+		}
+
+		username, err := parseTwoFactorChallenge(req.ChallengeToken)
+		if err != nil {
+			middleware.RecordAudit(c, &audit.Event{
+				Action:  audit.ActionLoginTwoFactor,
+				Outcome: audit.OutcomeDenied,
+				Reason:  "invalid_challenge",
+			})
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeAuthError,
+				Message: model.GetErrorMessage(model.CodeAuthError),
+				Details: err.Error(),
+			}, http.StatusUnauthorized)
+			return
+		}
+
+		authConfig := configManager.GetAuthConfig()
+		clientIP := c.ClientIP()
+
+		if authManager != nil && authManager.IsLocked(username, clientIP) {
+			middleware.RecordAudit(c, &audit.Event{
+				Actor:   username,
+				Action:  audit.ActionLoginTwoFactor,
+				Outcome: audit.OutcomeDenied,
+				Reason:  "locked",
+			})
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeRequestTimeout,
+				Message: "登录失败次数过多，请稍后再试",
+				Details: map[string]interface{}{
+					"remainingAttempts": authManager.GetRemainingAttempts(username, clientIP),
+					"maxFailCount":      authConfig.MaxLoginFail,
+					"lockDuration":      authConfig.LockDuration.String(),
+					"lockTime":          authManager.GetLockTime(username, clientIP).String(),
+				},
+			}, http.StatusTooManyRequests)
+			return
+		}
+
+		if err := twoFactorManager.Verify(username, req.Code); err != nil {
+			if authManager != nil {
+				authManager.RecordFailure(username, clientIP)
+			}
+
+			remainingAttempts := authConfig.MaxLoginFail
+			if authManager != nil {
+				remainingAttempts = authManager.GetRemainingAttempts(username, clientIP)
+			}
+
+			middleware.RecordAudit(c, &audit.Event{
+				Actor:   username,
+				Action:  audit.ActionLoginTwoFactor,
+				Outcome: audit.OutcomeFailure,
+				Reason:  "invalid_code",
+				Details: map[string]interface{}{"remainingAttempts": remainingAttempts},
+			})
+
+			logger.Warn("双因素认证失败",
+				zap.String("username", username),
+				zap.String("clientIP", clientIP),
+				zap.String("event", "login_2fa_failed"),
+				zap.Int("remainingAttempts", remainingAttempts),
+			)
+
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeAuthError,
+				Message: "验证码错误",
+				Details: map[string]interface{}{
+					"remainingAttempts": remainingAttempts,
+					"maxFailCount":      authConfig.MaxLoginFail,
+				},
+			}, http.StatusUnauthorized)
+			return
+		}
+
+		tokenString, err := issueSessionToken(username, "")
+		if err != nil {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeAuthError,
+				Message: model.GetErrorMessage(model.CodeAuthError),
+				Details: "Token生成失败",
+			}, http.StatusInternalServerError)
+			return
+		}
+
+		if authManager != nil {
+			authManager.RecordSuccess(username, clientIP)
+		}
+
+		middleware.RecordAudit(c, &audit.Event{
+			Actor:      username,
+			Role:       model.RoleAdmin,
+			AuthMethod: model.AuthMethodJWT,
+			Action:     audit.ActionLoginTwoFactor,
+			Outcome:    audit.OutcomeSuccess,
+		})
+
+		logger.Info("用户登录成功",
+			zap.String("username", username),
+			zap.String("clientIP", clientIP),
+			zap.String("userAgent", c.GetHeader("User-Agent")),
+			zap.String("event", "login_success"),
+			zap.Bool("twoFactor", true),
+		)
+
+		middleware.ResponseSuccess(c, gin.H{"token": tokenString}, "登录成功", nil)
+	}
+}
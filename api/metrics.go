@@ -1,14 +1,19 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/history"
 	"github.com/nick0323/K8sVision/model"
 	"github.com/nick0323/K8sVision/monitor"
 	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // MetricsResponse 指标响应
@@ -19,11 +24,12 @@ type MetricsResponse struct {
 }
 
 // RegisterMetrics 注册指标相关路由
-func RegisterMetrics(r *gin.RouterGroup, logger *zap.Logger) {
+func RegisterMetrics(r *gin.RouterGroup, logger *zap.Logger, getK8sClient K8sClientProvider) {
 	r.GET("/metrics", getMetrics(logger))
 	r.GET("/metrics/business", getBusinessMetrics(logger))
 	r.GET("/metrics/system", getSystemMetrics(logger))
 	r.GET("/metrics/health", getHealthMetrics(logger))
+	r.GET("/metrics/history", getMetricsHistory(logger, getK8sClient))
 }
 
 // getMetrics 获取所有指标
@@ -81,6 +87,7 @@ func getSystemMetrics(logger *zap.Logger) gin.HandlerFunc {
 
 		response := map[string]interface{}{
 			"metrics": systemMetrics,
+			"latency": metricsManager.GetMetrics().LatencyReport(),
 			"health":  healthStatus,
 		}
 
@@ -88,6 +95,136 @@ func getSystemMetrics(logger *zap.Logger) gin.HandlerFunc {
 	}
 }
 
+// defaultHistoryRange 未指定 from 时默认查询最近1小时
+const defaultHistoryRange = time.Hour
+
+// getMetricsHistory 查询节点、Pod或命名空间的CPU/内存用量历史
+// 参数: kind=node|pod|namespace, name, namespace（kind=pod 时必填）, from/to（RFC3339或Unix秒）, step（如 5m）
+func getMetricsHistory(logger *zap.Logger, getK8sClient K8sClientProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		store := history.Default()
+		if store == nil {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeServiceUnavailable,
+				Message: model.GetErrorMessage(model.CodeServiceUnavailable),
+				Details: "资源用量历史未启用",
+			}, http.StatusServiceUnavailable)
+			return
+		}
+
+		key := history.SeriesKey{Kind: c.Query("kind"), Name: c.Query("name")}
+		switch key.Kind {
+		case model.UsageKindNode, model.UsageKindNamespace:
+		case model.UsageKindPod:
+			key.Namespace = c.Query("namespace")
+			if key.Namespace == "" {
+				middleware.ResponseError(c, logger, invalidQueryParam("namespace", "kind=pod 时必填"), http.StatusBadRequest)
+				return
+			}
+		default:
+			middleware.ResponseError(c, logger, invalidQueryParam("kind", "仅支持 node、pod、namespace"), http.StatusBadRequest)
+			return
+		}
+		if key.Name == "" {
+			middleware.ResponseError(c, logger, invalidQueryParam("name", "不能为空"), http.StatusBadRequest)
+			return
+		}
+
+		if err := authorizeUsageHistory(GetRequestContext(c), getK8sClient, key); err != nil {
+			// 仅集群明确拒绝时返回403，连接失败等其他错误按服务端错误处理
+			status := http.StatusInternalServerError
+			if apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err) {
+				status = http.StatusForbidden
+			}
+			middleware.ResponseError(c, logger, err, status)
+			return
+		}
+
+		now := time.Now()
+		to, err := parseTimeParam(c.Query("to"))
+		if err != nil {
+			middleware.ResponseError(c, logger, invalidQueryParam("to", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if to.IsZero() || to.After(now) {
+			to = now
+		}
+		from, err := parseTimeParam(c.Query("from"))
+		if err != nil {
+			middleware.ResponseError(c, logger, invalidQueryParam("from", err.Error()), http.StatusBadRequest)
+			return
+		}
+		if from.IsZero() {
+			from = to.Add(-defaultHistoryRange)
+		}
+		if oldest := now.Add(-store.MaxRetention()); from.Before(oldest) {
+			from = oldest
+		}
+		if !from.Before(to) {
+			middleware.ResponseError(c, logger, invalidQueryParam("from", "必须早于 to"), http.StatusBadRequest)
+			return
+		}
+
+		var step time.Duration
+		if raw := c.Query("step"); raw != "" {
+			if step, err = time.ParseDuration(raw); err != nil || step <= 0 {
+				middleware.ResponseError(c, logger, invalidQueryParam("step", "无效的时长"), http.StatusBadRequest)
+				return
+			}
+		}
+
+		points, step, err := store.Query(key, from, to, step, now)
+		if err != nil && !errors.Is(err, history.ErrSeriesNotFound) {
+			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
+			return
+		}
+		if points == nil {
+			// 尚未采样到或已过期的对象返回空序列，便于前端直接绘图
+			points = []model.UsagePoint{}
+		}
+
+		middleware.ResponseSuccess(c, model.UsageHistory{
+			Kind:      key.Kind,
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			From:      from.Unix(),
+			To:        to.Unix(),
+			Step:      step.String(),
+			Points:    points,
+		}, "用量历史获取成功", nil)
+	}
+}
+
+// authorizeUsageHistory 用量历史由服务自身身份采样，查询前先以当前用户身份访问对应对象，
+// 确保用户在集群RBAC中有权查看：Pod 为 get 该Pod，命名空间为列举其中的Pod，节点为 get 该节点。
+// 对象不存在说明已通过鉴权（如已删除的Pod），仍允许查看其历史
+func authorizeUsageHistory(ctx context.Context, getK8sClient K8sClientProvider, key history.SeriesKey) error {
+	clientset, _, err := getK8sClient(ctx)
+	if err != nil {
+		return err
+	}
+	switch key.Kind {
+	case model.UsageKindPod:
+		_, err = clientset.CoreV1().Pods(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
+	case model.UsageKindNamespace:
+		_, err = clientset.CoreV1().Pods(key.Name).List(ctx, metav1.ListOptions{Limit: 1})
+	case model.UsageKindNode:
+		_, err = clientset.CoreV1().Nodes().Get(ctx, key.Name, metav1.GetOptions{})
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func invalidQueryParam(name, reason string) error {
+	return &model.APIError{
+		Code:    model.CodeValidationFailed,
+		Message: "查询参数错误",
+		Details: name + ": " + reason,
+	}
+}
+
 // getHealthMetrics 获取健康指标
 func getHealthMetrics(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -124,6 +261,15 @@ func getHealthMetrics(logger *zap.Logger) gin.HandlerFunc {
 	}
 }
 
+// 健康评分阈值：错误率为 5xx 百分比，延迟为 p99 毫秒
+const (
+	healthMinSamples        = 20
+	healthErrorRateWarning  = 1.0
+	healthErrorRateCritical = 5.0
+	healthP99Warning        = 2000.0
+	healthP99Critical       = 5000.0
+)
+
 // calculateSystemHealth 计算系统健康状态
 func calculateSystemHealth(metrics map[string]interface{}) map[string]interface{} {
 	healthScore := 100.0
@@ -153,6 +299,41 @@ func calculateSystemHealth(metrics map[string]interface{}) map[string]interface{
 		checks["business_metrics"] = "degraded"
 	}
 
+	// 检查最近5分钟的服务端错误率与尾延迟，样本过少时不参与评分
+	if m := monitor.GetMetricsManager(); m != nil {
+		recent := m.GetMetrics().LatencyWindow("5m", 5*time.Minute).Overall
+		checks["request_count_5m"] = recent.Count
+		checks["error_rate_5m"] = recent.ErrorRate
+		checks["p99_ms_5m"] = recent.P99Ms
+
+		if recent.Count < healthMinSamples {
+			checks["error_rate"] = "insufficient_data"
+			checks["latency"] = "insufficient_data"
+		} else {
+			switch {
+			case recent.ErrorRate >= healthErrorRateCritical:
+				healthScore -= 30
+				checks["error_rate"] = "critical"
+			case recent.ErrorRate >= healthErrorRateWarning:
+				healthScore -= 10
+				checks["error_rate"] = "degraded"
+			default:
+				checks["error_rate"] = "ok"
+			}
+
+			switch {
+			case recent.P99Ms >= healthP99Critical:
+				healthScore -= 20
+				checks["latency"] = "critical"
+			case recent.P99Ms >= healthP99Warning:
+				healthScore -= 10
+				checks["latency"] = "degraded"
+			default:
+				checks["latency"] = "ok"
+			}
+		}
+	}
+
 	// 确定整体状态
 	var status string
 	if healthScore >= 90 {
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nick0323/K8sVision/audit"
+)
+
+const auditRecordedKey = "auditRecorded"
+
+// RecordAudit 记录与当前请求关联的审计事件，未填写的字段从请求上下文补全
+func RecordAudit(c *gin.Context, e *audit.Event) {
+	if e.Actor == "" {
+		e.Actor = c.GetString("username")
+	}
+	if e.Role == "" {
+		e.Role = c.GetString("role")
+	}
+	if e.AuthMethod == "" {
+		e.AuthMethod = c.GetString("authMethod")
+	}
+	if e.TokenID == "" {
+		e.TokenID = c.GetString("tokenId")
+	}
+	if e.Method == "" {
+		e.Method = c.Request.Method
+	}
+	if e.Path == "" {
+		e.Path = c.Request.URL.Path
+	}
+	if e.ClientIP == "" {
+		e.ClientIP = c.ClientIP()
+	}
+	if e.UserAgent == "" {
+		e.UserAgent = c.Request.UserAgent()
+	}
+	if e.TraceID == "" {
+		e.TraceID = c.GetString("traceId")
+	}
+
+	c.Set(auditRecordedKey, true)
+	audit.Record(e)
+}
+
+// AuditMiddleware 为所有变更类请求记录审计事件
+// 处理函数已通过 RecordAudit 记录了更具体的事件时不再重复记录
+func AuditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case "GET", "HEAD", "OPTIONS":
+			return
+		}
+		if c.GetBool(auditRecordedKey) {
+			return
+		}
+
+		status := c.Writer.Status()
+		outcome := audit.OutcomeSuccess
+		switch {
+		case status == 401 || status == 403:
+			outcome = audit.OutcomeDenied
+		case status >= 400:
+			outcome = audit.OutcomeFailure
+		}
+
+		RecordAudit(c, &audit.Event{
+			Action:     audit.ActionMutation,
+			Resource:   resourceFromPath(c),
+			StatusCode: status,
+			Outcome:    outcome,
+		})
+	}
+}
+
+// resourceFromPath 从路由参数和路径推断资源，如 /api/nodes/:name/cordon
+func resourceFromPath(c *gin.Context) audit.Resource {
+	kind := ""
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(route, "/api"), "/"), "/")
+	if len(segments) > 0 {
+		kind = segments[0]
+		if kind == "admin" && len(segments) > 1 {
+			kind = segments[1]
+		}
+	}
+	return audit.Resource{
+		Kind:      kind,
+		Namespace: c.Param("namespace"),
+		Name:      c.Param("name"),
+	}
+}
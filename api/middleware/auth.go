@@ -14,6 +14,14 @@ type ConfigProvider interface {
 	GetJWTSecret() []byte
 }
 
+// TwoFactorSetupPathPrefix 受限会话（强制2FA但未绑定）允许访问的路径前缀
+const TwoFactorSetupPathPrefix = "/api/auth/2fa"
+
+// TokenValidator API令牌校验接口，由长期令牌管理器实现
+type TokenValidator interface {
+	ValidateToken(token, clientIP string) (*model.AuthIdentity, error)
+}
+
 func getJWTSecret(provider ConfigProvider) []byte {
 	if provider == nil {
 		panic("配置提供者未初始化")
@@ -36,7 +44,37 @@ func safeStringClaim(claims jwt.MapClaims, key string) (string, bool) {
 	return str, ok
 }
 
-func JWTAuthMiddleware(logger *zap.Logger, configProvider ConfigProvider) gin.HandlerFunc {
+// setIdentity 将认证身份写入上下文
+func setIdentity(c *gin.Context, identity *model.AuthIdentity) {
+	c.Set("username", identity.Username)
+	c.Set("role", identity.Role)
+	c.Set("authMethod", identity.Method)
+	if identity.TokenID != "" {
+		c.Set("tokenId", identity.TokenID)
+	}
+	c.Set("identity", identity)
+	c.Request = c.Request.WithContext(model.ContextWithIdentity(c.Request.Context(), identity))
+}
+
+// GetIdentity 从上下文中获取认证身份
+func GetIdentity(c *gin.Context) *model.AuthIdentity {
+	if v, ok := c.Get("identity"); ok {
+		if identity, ok := v.(*model.AuthIdentity); ok {
+			return identity
+		}
+	}
+	return nil
+}
+
+// roleAllowsMethod 只读角色仅允许安全方法
+func roleAllowsMethod(role, method string) bool {
+	if role != model.RoleViewer {
+		return true
+	}
+	return method == "GET" || method == "HEAD" || method == "OPTIONS"
+}
+
+func JWTAuthMiddleware(logger *zap.Logger, configProvider ConfigProvider, tokenValidator TokenValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		traceId := c.GetString("traceId")
 
@@ -73,6 +111,58 @@ func JWTAuthMiddleware(logger *zap.Logger, configProvider ConfigProvider) gin.Ha
 
 		tokenStr = strings.TrimPrefix(tokenStr, "Bearer ")
 
+		// 长期API令牌（kv_前缀），用于自动化脚本和CI
+		if strings.HasPrefix(tokenStr, model.APITokenPrefix) {
+			if tokenValidator == nil {
+				ResponseError(c, logger, &model.APIError{
+					Code:    model.CodeAuthError,
+					Message: model.GetErrorMessage(model.CodeAuthError),
+					Details: "API令牌认证未启用",
+				}, 401)
+				c.Abort()
+				return
+			}
+
+			identity, err := tokenValidator.ValidateToken(tokenStr, c.ClientIP())
+			if err != nil {
+				logger.Warn("api token rejected",
+					zap.String("traceId", traceId),
+					zap.String("clientIP", c.ClientIP()),
+					zap.Error(err),
+				)
+				ResponseError(c, logger, &model.APIError{
+					Code:    model.CodeAuthError,
+					Message: model.GetErrorMessage(model.CodeAuthError),
+					Details: "API令牌无效: " + err.Error(),
+				}, 401)
+				c.Abort()
+				return
+			}
+
+			if !roleAllowsMethod(identity.Role, c.Request.Method) {
+				ResponseError(c, logger, &model.APIError{
+					Code:    model.CodeForbidden,
+					Message: model.GetErrorMessage(model.CodeForbidden),
+					Details: "当前令牌为只读权限",
+				}, 403)
+				c.Abort()
+				return
+			}
+
+			setIdentity(c, identity)
+
+			logger.Info("authentication successful",
+				zap.String("traceId", traceId),
+				zap.String("clientIP", c.ClientIP()),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("authMethod", model.AuthMethodAPIToken),
+				zap.String("tokenId", identity.TokenID),
+			)
+
+			c.Next()
+			return
+		}
+
 		segments := strings.Split(tokenStr, ".")
 		if len(segments) != 3 {
 			logger.Warn("invalid token format - wrong number of segments",
@@ -195,7 +285,34 @@ func JWTAuthMiddleware(logger *zap.Logger, configProvider ConfigProvider) gin.Ha
 				return
 			}
 
-			c.Set("username", username)
+			role, roleExists := safeStringClaim(claims, "role")
+			if !roleExists || role == "" {
+				role = model.RoleAdmin
+			}
+
+			scope, _ := safeStringClaim(claims, "scope")
+			if scope == model.TokenScopeTwoFactorSetup && !strings.HasPrefix(c.Request.URL.Path, TwoFactorSetupPathPrefix) {
+				logger.Warn("two-factor setup required",
+					zap.String("traceId", traceId),
+					zap.String("clientIP", c.ClientIP()),
+					zap.String("username", username),
+					zap.String("path", c.Request.URL.Path),
+				)
+				ResponseError(c, logger, &model.APIError{
+					Code:    model.CodeForbidden,
+					Message: model.GetErrorMessage(model.CodeForbidden),
+					Details: "管理员要求启用双因素认证，请先完成绑定",
+				}, 403)
+				c.Abort()
+				return
+			}
+			c.Set("tokenScope", scope)
+
+			setIdentity(c, &model.AuthIdentity{
+				Username: username,
+				Role:     role,
+				Method:   model.AuthMethodJWT,
+			})
 
 			// 安全地设置JTI（如果存在）
 			if jtiExists && jti != "" {
@@ -213,3 +330,29 @@ func JWTAuthMiddleware(logger *zap.Logger, configProvider ConfigProvider) gin.Ha
 		c.Next()
 	}
 }
+
+// RequireRole 角色校验中间件，需在 JWTAuthMiddleware 之后使用
+func RequireRole(logger *zap.Logger, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
+			}
+		}
+
+		logger.Warn("permission denied",
+			zap.String("traceId", c.GetString("traceId")),
+			zap.String("username", c.GetString("username")),
+			zap.String("role", role),
+			zap.String("path", c.Request.URL.Path),
+		)
+		ResponseError(c, logger, &model.APIError{
+			Code:    model.CodePermissionDenied,
+			Message: model.GetErrorMessage(model.CodePermissionDenied),
+			Details: fmt.Sprintf("需要角色: %s", strings.Join(roles, ",")),
+		}, 403)
+		c.Abort()
+	}
+}
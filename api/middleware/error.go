@@ -42,6 +42,10 @@ func ResponseError(c *gin.Context, logger *zap.Logger, err error, httpCode int)
 		apiError = e
 	case *errors.StatusError:
 		apiError = ConvertK8sError(e)
+		// 集群RBAC拒绝（含模拟身份无权限）时如实返回403，而非调用方给定的通用状态码
+		if errors.IsForbidden(e) {
+			httpCode = http.StatusForbidden
+		}
 	default:
 		apiError = &model.APIError{
 			Code:    model.CodeInternalServerError,
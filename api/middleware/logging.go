@@ -1,11 +1,18 @@
 package middleware
 
 import (
-	"crypto/rand"
-	"encoding/hex"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/monitor"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -50,30 +57,49 @@ func LoggingMiddleware(logger *zap.Logger) gin.HandlerFunc {
 }
 
 // TraceMiddleware 请求追踪中间件
+// 按W3C traceparent 继承上游trace并为每个请求创建服务端Span，traceId 即 OpenTelemetry trace ID
 func TraceMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 从请求头获取traceId，如果没有则生成新的
-		traceId := c.GetHeader("X-Trace-ID")
-		if traceId == "" {
-			traceId = generateTraceID()
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		spanName := c.Request.Method
+		if route != "" {
+			spanName += " " + route
 		}
+		ctx, span := monitor.StartSpan(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+				semconv.ClientAddress(c.ClientIP()),
+				semconv.UserAgentOriginal(c.Request.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		traceId := span.SpanContext().TraceID().String()
 
-		// 设置traceId到context中
+		// 设置traceId到context中，同时写入请求上下文供服务层使用
 		c.Set("traceId", traceId)
+		c.Request = c.Request.WithContext(model.ContextWithTraceID(ctx, traceId))
 
 		// 在响应头中返回traceId
 		c.Header("X-Trace-ID", traceId)
 
 		c.Next()
-	}
-}
 
-// generateTraceID 生成追踪ID
-// 格式: YYYYMMDDHHMMSS-XXXXXXXX (时间戳-8位随机十六进制)
-func generateTraceID() string {
-	timestamp := time.Now().Format("20060102150405")
-	randomBytes := make([]byte, 4)
-	rand.Read(randomBytes)
-	randomHex := hex.EncodeToString(randomBytes)
-	return timestamp + "-" + randomHex
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if username := c.GetString("username"); username != "" {
+			span.SetAttributes(semconv.EnduserID(username))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		if len(c.Errors) > 0 {
+			span.SetAttributes(attribute.String("error.message", c.Errors.String()))
+		}
+	}
 }
@@ -1,10 +1,14 @@
 package middleware
 
 import (
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nick0323/K8sVision/monitor"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // MetricsRecorder 性能指标记录接口
@@ -17,10 +21,21 @@ type MetricsRecorder interface {
 	RecordCacheMiss()
 }
 
+// RouteMetricsRecorder 按路由记录请求的扩展接口
+type RouteMetricsRecorder interface {
+	RecordRouteRequest(method, route string, status int, responseTime time.Duration)
+}
+
+// unmatchedRoute 未匹配路由的统一标签，避免任意路径导致指标基数膨胀
+const unmatchedRoute = "unmatched"
+
 // MetricsMiddleware 性能监控中间件
 func MetricsMiddleware(recorder MetricsRecorder) gin.HandlerFunc {
+	routeRecorder, _ := recorder.(RouteMetricsRecorder)
+
 	return func(c *gin.Context) {
 		start := time.Now()
+		c.Set("metrics", recorder)
 
 		// 记录连接
 		recorder.RecordConnection()
@@ -37,6 +52,13 @@ func MetricsMiddleware(recorder MetricsRecorder) gin.HandlerFunc {
 
 		// 记录请求统计
 		recorder.RecordRequest(success, responseTime)
+		if routeRecorder != nil {
+			route := c.FullPath()
+			if route == "" {
+				route = unmatchedRoute
+			}
+			routeRecorder.RecordRouteRequest(c.Request.Method, route, c.Writer.Status(), responseTime)
+		}
 
 		// 记录错误
 		if !success {
@@ -45,11 +67,18 @@ func MetricsMiddleware(recorder MetricsRecorder) gin.HandlerFunc {
 	}
 }
 
+// RevealPathSuffix 敏感数据明文接口的路径后缀，此类响应不进入缓存
+const RevealPathSuffix = "/reveal"
+
+// DrainJobsPath 驱逐任务接口的路径，任务进度实时变化，不进入缓存
+const DrainJobsPath = "/drain-jobs"
+
 // CacheMiddleware 缓存中间件
 func CacheMiddleware(cacheManager interface{}, ttl time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 只对GET请求进行缓存
-		if c.Request.Method != "GET" {
+		// 只对GET请求进行缓存，敏感数据明文永不缓存
+		if c.Request.Method != "GET" || strings.HasSuffix(c.Request.URL.Path, RevealPathSuffix) ||
+			strings.Contains(c.Request.URL.Path, DrainJobsPath) {
 			c.Next()
 			return
 		}
@@ -61,7 +90,14 @@ func CacheMiddleware(cacheManager interface{}, ttl time.Duration) gin.HandlerFun
 		if cache, ok := cacheManager.(interface {
 			Get(key string) (interface{}, bool)
 		}); ok {
-			if value, exists := cache.Get(cacheKey); exists {
+			_, span := monitor.StartSpan(c.Request.Context(), "cache.get", trace.WithAttributes(
+				attribute.String("cache.name", "http_response"),
+			))
+			value, exists := cache.Get(cacheKey)
+			span.SetAttributes(attribute.Bool("cache.hit", exists))
+			span.End()
+
+			if exists {
 				// 缓存命中
 				if recorder, ok := c.Get("metrics"); ok {
 					if r, ok := recorder.(MetricsRecorder); ok {
@@ -104,11 +140,20 @@ func generateCacheKey(c *gin.Context) string {
 	return key
 }
 
-// ConcurrencyMiddleware 并发控制中间件
-func ConcurrencyMiddleware(maxConcurrency int) gin.HandlerFunc {
+// ConcurrencyMiddleware 并发控制中间件，exemptPaths 中的路径（如健康探针）不受限制，
+// 避免服务繁忙时探针失败导致容器被重启或摘除
+func ConcurrencyMiddleware(maxConcurrency int, exemptPaths ...string) gin.HandlerFunc {
 	semaphore := make(chan struct{}, maxConcurrency)
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = true
+	}
 
 	return func(c *gin.Context) {
+		if exempt[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
 		select {
 		case semaphore <- struct{}{}:
 			defer func() { <-semaphore }()
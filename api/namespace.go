@@ -6,10 +6,10 @@ import (
 
 	"github.com/nick0323/K8sVision/api/middleware"
 	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -30,12 +30,12 @@ func getNamespaceList(
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 命名空间用于下拉选择，返回全集更符合预期（不做分页）
-		clientset, _, err := getK8sClient()
+		ctx := GetRequestContext(c)
+		clientset, _, err := getK8sClient(ctx)
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
 			return
 		}
-		ctx := GetRequestContext(c)
 		ns, err := listNamespaces(ctx, clientset)
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
@@ -45,32 +45,23 @@ func getNamespaceList(
 	}
 }
 
+// getNamespaceDetail 获取命名空间详情，附带对象数量、资源用量、配额使用率和默认限制汇总
 func getNamespaceDetail(
 	logger *zap.Logger,
 	getK8sClient K8sClientProvider,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientset, _, err := getK8sClient()
+		ctx := GetRequestContext(c)
+		clientset, metricsClient, err := getK8sClient(ctx)
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
 			return
 		}
-		ctx := GetRequestContext(c)
-		name := c.Param("name")
-		ns, err := clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		namespaceDetail, err := service.GetNamespaceDetail(ctx, clientset, metricsClient, c.Param("name"))
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusNotFound)
 			return
 		}
-
-		namespaceDetail := model.NamespaceDetail{
-			Name:   ns.Name,
-			Status: string(ns.Status.Phase),
-			BaseMetadata: model.BaseMetadata{
-				Labels:      ns.Labels,
-				Annotations: ns.Annotations,
-			},
-		}
 		middleware.ResponseSuccess(c, namespaceDetail, DetailSuccessMessage, nil)
 	}
 }
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RegisterNetworkPolicy 注册 NetworkPolicy 相关路由
+func RegisterNetworkPolicy(
+	r *gin.RouterGroup,
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listNetworkPolicies func(context.Context, *kubernetes.Clientset, string) ([]model.NetworkPolicyStatus, error),
+) {
+	r.GET("/networkpolicies", getNetworkPolicyList(logger, getK8sClient, listNetworkPolicies))
+	r.GET("/networkpolicies/reachability", getReachability(logger, getK8sClient))
+	r.GET("/networkpolicies/:namespace/:name", getNetworkPolicyDetail(logger, getK8sClient))
+}
+
+func getNetworkPolicyList(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listNetworkPolicies func(context.Context, *kubernetes.Clientset, string) ([]model.NetworkPolicyStatus, error),
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.NetworkPolicyStatus, error) {
+			clientset, _, err := getK8sClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return listNetworkPolicies(ctx, clientset, params.Namespace)
+		}, ListSuccessMessage)
+	}
+}
+
+func getNetworkPolicyDetail(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleDetailWithK8s(c, logger, getK8sClient, service.GetNetworkPolicyDetail, DetailSuccessMessage)
+	}
+}
+
+// getPodNetworkPolicies 返回选中Pod的策略及合并后的入站、出站规则
+func getPodNetworkPolicies(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleDetailWithK8s(c, logger, getK8sClient, service.GetPodNetworkPolicies, DetailSuccessMessage)
+	}
+}
+
+// getReachability 分析两个Pod之间的连通性：from/to 为 namespace/pod，port 为端口号或目标Pod的命名端口，protocol 默认 TCP
+func getReachability(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		srcNamespace, srcName, ok := splitNamespacedName(c.Query("from"))
+		if !ok {
+			middleware.ResponseError(c, logger, invalidQueryParam("from", "格式应为 namespace/pod"), http.StatusBadRequest)
+			return
+		}
+		dstNamespace, dstName, ok := splitNamespacedName(c.Query("to"))
+		if !ok {
+			middleware.ResponseError(c, logger, invalidQueryParam("to", "格式应为 namespace/pod"), http.StatusBadRequest)
+			return
+		}
+		port := c.Query("port")
+		if port == "" {
+			middleware.ResponseError(c, logger, invalidQueryParam("port", "不能为空"), http.StatusBadRequest)
+			return
+		}
+		protocol := corev1.Protocol(strings.ToUpper(c.DefaultQuery("protocol", string(corev1.ProtocolTCP))))
+		switch protocol {
+		case corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP:
+		default:
+			middleware.ResponseError(c, logger, invalidQueryParam("protocol", "只能为 TCP、UDP 或 SCTP"), http.StatusBadRequest)
+			return
+		}
+
+		ctx := GetRequestContext(c)
+		clientset, _, err := getK8sClient(ctx)
+		if err != nil {
+			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
+			return
+		}
+		result, err := service.CheckReachability(ctx, clientset, srcNamespace, srcName, dstNamespace, dstName, port, protocol)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidPort) {
+				middleware.ResponseError(c, logger, invalidQueryParam("port", err.Error()), http.StatusBadRequest)
+				return
+			}
+			middleware.ResponseError(c, logger, err, http.StatusNotFound)
+			return
+		}
+		middleware.ResponseSuccess(c, result, SuccessMessage, nil)
+	}
+}
+
+// splitNamespacedName 解析 namespace/name 形式的参数
+func splitNamespacedName(value string) (string, string, bool) {
+	namespace, name, found := strings.Cut(value, "/")
+	if !found || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", false
+	}
+	return namespace, name, true
+}
@@ -3,10 +3,10 @@ package api
 import (
 	"context"
 	"net/http"
-	"strings"
 
 	"github.com/nick0323/K8sVision/api/middleware"
 	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -24,6 +24,13 @@ func RegisterNode(
 ) {
 	r.GET("/nodes", getNodeList(logger, getK8sClient, listPodsWithRaw, listNodes))
 	r.GET("/nodes/:name", getNodeDetail(logger, getK8sClient))
+	r.GET("/nodes/:name/drain-preview", getDrainPreview(logger, getK8sClient))
+	r.POST("/nodes/:name/cordon", setNodeSchedulable(logger, getK8sClient, true))
+	r.POST("/nodes/:name/uncordon", setNodeSchedulable(logger, getK8sClient, false))
+	r.POST("/nodes/:name/drain", startDrain(logger, getK8sClient))
+	r.GET(middleware.DrainJobsPath, listDrainJobs(logger))
+	r.GET(middleware.DrainJobsPath+"/:id", getDrainJob(logger))
+	r.DELETE(middleware.DrainJobsPath+"/:id", cancelDrainJob(logger))
 }
 
 func getNodeList(
@@ -34,7 +41,7 @@ func getNodeList(
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.NodeStatus, error) {
-			clientset, metricsClient, err := getK8sClient()
+			clientset, metricsClient, err := getK8sClient(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -64,80 +71,30 @@ func getNodeDetail(
 	getK8sClient K8sClientProvider,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientset, metricsClient, err := getK8sClient()
+		ctx := GetRequestContext(c)
+		clientset, metricsClient, err := getK8sClient(ctx)
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
 			return
 		}
-		ctx := GetRequestContext(c)
-		name := c.Param("name")
-		node, err := clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		nodeDetail, err := service.GetNodeDetail(ctx, clientset, metricsClient, c.Param("name"))
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusNotFound)
 			return
 		}
-		pods, _ := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
-		podsUsed := 0
-		for _, pod := range pods.Items {
-			if pod.Spec.NodeName == node.Name {
-				podsUsed++
-			}
-		}
-		podsCapacity := 0
-		if v, ok := node.Status.Allocatable["pods"]; ok {
-			podsCapacity = int(v.Value())
-		}
-		ip := ""
-		for _, addr := range node.Status.Addresses {
-			if addr.Type == "InternalIP" {
-				ip = addr.Address
-				break
-			}
-		}
-		roles := make([]string, 0)
-		for key := range node.Labels {
-			if strings.HasPrefix(key, "node-role.kubernetes.io/") {
-				role := strings.TrimPrefix(key, "node-role.kubernetes.io/")
-				if role == "" {
-					role = "worker"
-				}
-				roles = append(roles, role)
-			}
-		}
-		if len(roles) == 0 {
-			roles = append(roles, "worker")
-		}
-		metricsList, _ := metricsClient.MetricsV1beta1().NodeMetricses().Get(ctx, name, metav1.GetOptions{})
-		var cpuPercent, memPercent float64
-		if metricsList != nil {
-			cpuUsed := metricsList.Usage.Cpu().MilliValue()
-			memUsed := metricsList.Usage.Memory().Value()
-			cpuTotal := node.Status.Allocatable.Cpu().MilliValue()
-			memTotal := node.Status.Allocatable.Memory().Value()
-			if cpuTotal > 0 {
-				cpuPercent = float64(cpuUsed) / float64(cpuTotal) * 100
-			}
-			if memTotal > 0 {
-				memPercent = float64(memUsed) / float64(memTotal) * 100
-			}
-		}
-		nodeDetail := model.NodeDetail{
-			CommonResourceFields: model.CommonResourceFields{
-				Namespace: "", // Node没有namespace
-				Name:      node.Name,
-				Status:    string(node.Status.Conditions[len(node.Status.Conditions)-1].Type),
-				BaseMetadata: model.BaseMetadata{
-					Labels:      node.Labels,
-					Annotations: node.Annotations,
-				},
-			},
-			IP:           ip,
-			CPUUsage:     cpuPercent,
-			MemoryUsage:  memPercent,
-			Role:         roles,
-			PodsUsed:     podsUsed,
-			PodsCapacity: podsCapacity,
-		}
 		middleware.ResponseSuccess(c, nodeDetail, DetailSuccessMessage, nil)
 	}
 }
+
+// getDrainPreview 预览驱逐节点时各Pod的处理方式：DaemonSet、本地存储、无控制器以及被PDB阻塞的Pod
+func getDrainPreview(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleDetailWithK8s(c, logger, getK8sClient,
+			func(ctx context.Context, clientset *kubernetes.Clientset, _, name string) (*model.DrainPreview, error) {
+				return service.GetDrainPreview(ctx, clientset, name)
+			}, DetailSuccessMessage)
+	}
+}
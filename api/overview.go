@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/nick0323/K8sVision/api/middleware"
@@ -13,11 +14,11 @@ import (
 func RegisterOverview(
 	r *gin.RouterGroup,
 	logger *zap.Logger,
-	overviewFunc func(limit, offset int) (*model.OverviewStatus, string, error),
+	overviewFunc func(ctx context.Context, limit, offset int) (*model.OverviewStatus, string, error),
 ) {
 	r.GET("/overview", func(c *gin.Context) {
 		params := ParsePaginationParams(c)
-		overview, msg, err := overviewFunc(params.Limit, params.Offset)
+		overview, msg, err := overviewFunc(GetRequestContext(c), params.Limit, params.Offset)
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
 			return
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/audit"
 	"github.com/nick0323/K8sVision/model"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
@@ -250,6 +251,12 @@ func changePassword(logger *zap.Logger) gin.HandlerFunc {
 		}
 
 		if !oldPasswordMatch {
+			middleware.RecordAudit(c, &audit.Event{
+				Action:   audit.ActionPasswordChange,
+				Resource: audit.Resource{Kind: "password"},
+				Outcome:  audit.OutcomeFailure,
+				Reason:   "invalid_old_password",
+			})
 			middleware.ResponseError(c, logger, &model.APIError{
 				Code:    model.CodeAuthError,
 				Message: "旧密码错误",
@@ -306,6 +313,11 @@ func changePassword(logger *zap.Logger) gin.HandlerFunc {
 			username = "admin"
 		}
 		logger.Info("密码修改成功", zap.String("username", username))
+		middleware.RecordAudit(c, &audit.Event{
+			Action:   audit.ActionPasswordChange,
+			Resource: audit.Resource{Kind: "password"},
+			Outcome:  audit.OutcomeSuccess,
+		})
 
 		middleware.ResponseSuccess(c, gin.H{
 			"message": "密码修改成功",
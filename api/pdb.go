@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RegisterPDB 注册 PodDisruptionBudget 相关路由
+func RegisterPDB(
+	r *gin.RouterGroup,
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listPDBs func(context.Context, *kubernetes.Clientset, string) ([]model.PDBStatus, error),
+) {
+	r.GET("/pdbs", getPDBList(logger, getK8sClient, listPDBs))
+	r.GET("/pdbs/:namespace/:name", getPDBDetail(logger, getK8sClient))
+}
+
+func getPDBList(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listPDBs func(context.Context, *kubernetes.Clientset, string) ([]model.PDBStatus, error),
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.PDBStatus, error) {
+			clientset, _, err := getK8sClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return listPDBs(ctx, clientset, params.Namespace)
+		}, ListSuccessMessage)
+	}
+}
+
+func getPDBDetail(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleDetailWithK8s(c, logger, getK8sClient, service.GetPDBDetail, DetailSuccessMessage)
+	}
+}
@@ -2,17 +2,15 @@ package api
 
 import (
 	"context"
-	"net/http"
 
-	"github.com/nick0323/K8sVision/api/middleware"
 	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	versioned "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 func RegisterPod(
@@ -23,6 +21,7 @@ func RegisterPod(
 ) {
 	r.GET("/pods", getPodList(logger, getK8sClient, listPodsWithRaw))
 	r.GET("/pods/:namespace/:name", getPodDetail(logger, getK8sClient))
+	r.GET("/pods/:namespace/:name/networkpolicies", getPodNetworkPolicies(logger, getK8sClient))
 }
 
 func getPodList(
@@ -32,7 +31,7 @@ func getPodList(
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.PodStatus, error) {
-			clientset, metricsClient, err := getK8sClient()
+			clientset, metricsClient, err := getK8sClient(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -57,41 +56,9 @@ func getPodList(
 
 func getPodDetail(
 	logger *zap.Logger,
-	getK8sClient func() (*kubernetes.Clientset, *versioned.Clientset, error),
+	getK8sClient K8sClientProvider,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientset, _, err := getK8sClient()
-		if err != nil {
-			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
-			return
-		}
-		ctx := GetRequestContext(c)
-		ns := c.Param("namespace")
-		name := c.Param("name")
-		pod, err := clientset.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
-		if err != nil {
-			middleware.ResponseError(c, logger, err, http.StatusNotFound)
-			return
-		}
-		containers := make([]string, 0, len(pod.Spec.Containers))
-		for _, ctn := range pod.Spec.Containers {
-			containers = append(containers, ctn.Name+" ("+ctn.Image+")")
-		}
-		podDetail := model.PodDetail{
-			CommonResourceFields: model.CommonResourceFields{
-				Namespace: pod.Namespace,
-				Name:      pod.Name,
-				Status:    string(pod.Status.Phase),
-				BaseMetadata: model.BaseMetadata{
-					Labels:      pod.Labels,
-					Annotations: pod.Annotations,
-				},
-			},
-			PodIP:      pod.Status.PodIP,
-			NodeName:   pod.Spec.NodeName,
-			StartTime:  pod.Status.StartTime.Format("2006-01-02 15:04:05"),
-			Containers: containers,
-		}
-		middleware.ResponseSuccess(c, podDetail, "success", nil)
+		HandleDetailWithK8s(c, logger, getK8sClient, service.GetPodDetail, DetailSuccessMessage)
 	}
 }
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RegisterPriorityClass 注册 PriorityClass 相关路由
+func RegisterPriorityClass(
+	r *gin.RouterGroup,
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listPriorityClasses func(context.Context, *kubernetes.Clientset, string) ([]model.PriorityClassStatus, error),
+) {
+	r.GET("/priorityclasses", getPriorityClassList(logger, getK8sClient, listPriorityClasses))
+	r.GET("/priorityclasses/:name", getPriorityClassDetail(logger, getK8sClient))
+}
+
+func getPriorityClassList(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listPriorityClasses func(context.Context, *kubernetes.Clientset, string) ([]model.PriorityClassStatus, error),
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.PriorityClassStatus, error) {
+			clientset, _, err := getK8sClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return listPriorityClasses(ctx, clientset, params.Namespace)
+		}, ListSuccessMessage)
+	}
+}
+
+func getPriorityClassDetail(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleDetailWithK8s(c, logger, getK8sClient, service.GetPriorityClassDetail, DetailSuccessMessage)
+	}
+}
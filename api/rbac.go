@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RegisterRBAC 注册 Role、ClusterRole、RoleBinding、ClusterRoleBinding 及权限分析路由
+func RegisterRBAC(
+	r *gin.RouterGroup,
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) {
+	r.GET("/roles", getRBACList(logger, getK8sClient, service.ListRoles))
+	r.GET("/roles/:namespace/:name", getRBACDetail(logger, getK8sClient, service.GetRoleDetail))
+	r.GET("/clusterroles", getRBACList(logger, getK8sClient, service.ListClusterRoles))
+	r.GET("/clusterroles/:name", getRBACDetail(logger, getK8sClient, service.GetClusterRoleDetail))
+	r.GET("/rolebindings", getRBACList(logger, getK8sClient, service.ListRoleBindings))
+	r.GET("/rolebindings/:namespace/:name", getRBACDetail(logger, getK8sClient, service.GetRoleBindingDetail))
+	r.GET("/clusterrolebindings", getRBACList(logger, getK8sClient, service.ListClusterRoleBindings))
+	r.GET("/clusterrolebindings/:name", getRBACDetail(logger, getK8sClient, service.GetClusterRoleBindingDetail))
+
+	r.GET("/rbac/what-can", getWhatCan(logger, getK8sClient))
+	r.GET("/rbac/who-can", getWhoCan(logger, getK8sClient))
+}
+
+func getRBACList[T SearchableItem](
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	list func(context.Context, *kubernetes.Clientset, string) ([]T, error),
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]T, error) {
+			clientset, _, err := getK8sClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return list(ctx, clientset, params.Namespace)
+		}, ListSuccessMessage)
+	}
+}
+
+func getRBACDetail[T any](
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	get func(context.Context, *kubernetes.Clientset, string, string) (T, error),
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleDetailWithK8s(c, logger, getK8sClient, get, DetailSuccessMessage)
+	}
+}
+
+// getWhatCan 汇总主体在命名空间中的权限
+// subject: User:alice、Group:devs 或 ServiceAccount:ns/name；groups: 用户所属的组（逗号分隔），apiserver 无法得知用户的组
+// namespace 为空时只统计集群范围的绑定；verb+resource 可筛选规则，confirm=true 时以 SubjectAccessReview 确认该访问
+func getWhatCan(logger *zap.Logger, getK8sClient K8sClientProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject, ok := parseSubject(c.Query("subject"))
+		if !ok {
+			middleware.ResponseError(c, logger, invalidQueryParam("subject", "格式应为 User:名称、Group:名称 或 ServiceAccount:namespace/name"), http.StatusBadRequest)
+			return
+		}
+		namespace := c.Query("namespace")
+
+		var filter *service.AccessAttributes
+		verb, resourceArg := c.Query("verb"), c.Query("resource")
+		if verb != "" || resourceArg != "" {
+			if verb == "" || resourceArg == "" {
+				middleware.ResponseError(c, logger, invalidQueryParam("verb", "verb 与 resource 需同时指定"), http.StatusBadRequest)
+				return
+			}
+			resource, group, subresource := service.ParseResourceArg(resourceArg)
+			filter = &service.AccessAttributes{
+				Verb:        verb,
+				APIGroup:    group,
+				Resource:    resource,
+				Subresource: subresource,
+				Name:        c.Query("name"),
+				Namespace:   namespace,
+			}
+		}
+		confirm := c.Query("confirm") == "true"
+		if confirm && filter == nil {
+			middleware.ResponseError(c, logger, invalidQueryParam("confirm", "确认需要同时指定 verb 与 resource"), http.StatusBadRequest)
+			return
+		}
+
+		ctx := GetRequestContext(c)
+		clientset, _, err := getK8sClient(ctx)
+		if err != nil {
+			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
+			return
+		}
+		result, err := service.GetSubjectPermissions(ctx, clientset, subject, splitList(c.Query("groups")), namespace, filter, confirm)
+		if err != nil {
+			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
+			return
+		}
+		middleware.ResponseSuccess(c, result, SuccessMessage, nil)
+	}
+}
+
+// getWhoCan 查找拥有指定权限的主体，类似 kubectl who-can
+// verb、resource 必填，resource 支持 pods/log、deployments.apps 形式；name 限定资源名；namespace 为空时只统计集群范围的绑定
+func getWhoCan(logger *zap.Logger, getK8sClient K8sClientProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		verb, resourceArg := c.Query("verb"), c.Query("resource")
+		if verb == "" {
+			middleware.ResponseError(c, logger, invalidQueryParam("verb", "不能为空"), http.StatusBadRequest)
+			return
+		}
+		if resourceArg == "" {
+			middleware.ResponseError(c, logger, invalidQueryParam("resource", "不能为空"), http.StatusBadRequest)
+			return
+		}
+		resource, group, subresource := service.ParseResourceArg(resourceArg)
+		attrs := service.AccessAttributes{
+			Verb:        verb,
+			APIGroup:    group,
+			Resource:    resource,
+			Subresource: subresource,
+			Name:        c.Query("name"),
+			Namespace:   c.Query("namespace"),
+		}
+
+		ctx := GetRequestContext(c)
+		clientset, _, err := getK8sClient(ctx)
+		if err != nil {
+			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
+			return
+		}
+		result, err := service.WhoCan(ctx, clientset, attrs, c.Query("confirm") == "true")
+		if err != nil {
+			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
+			return
+		}
+		middleware.ResponseSuccess(c, result, SuccessMessage, nil)
+	}
+}
+
+// parseSubject 解析 Kind:名称 形式的主体，ServiceAccount 的名称为 namespace/name
+func parseSubject(value string) (model.RBACSubject, bool) {
+	kind, name, found := strings.Cut(value, ":")
+	if !found || name == "" {
+		return model.RBACSubject{}, false
+	}
+	switch kind {
+	case rbacv1.UserKind, rbacv1.GroupKind:
+		return model.RBACSubject{Kind: kind, Name: name}, true
+	case rbacv1.ServiceAccountKind:
+		namespace, saName, ok := splitNamespacedName(name)
+		if !ok {
+			return model.RBACSubject{}, false
+		}
+		return model.RBACSubject{Kind: kind, Name: saName, Namespace: namespace}, true
+	}
+	return model.RBACSubject{}, false
+}
+
+// splitList 解析逗号分隔的参数，忽略空项
+func splitList(value string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RegisterReplicaSet 注册 ReplicaSet 相关路由
+func RegisterReplicaSet(
+	r *gin.RouterGroup,
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listReplicaSets func(context.Context, *kubernetes.Clientset, string) ([]model.ReplicaSetStatus, error),
+) {
+	r.GET("/replicasets", getReplicaSetList(logger, getK8sClient, listReplicaSets))
+	r.GET("/replicasets/:namespace/:name", getReplicaSetDetail(logger, getK8sClient))
+}
+
+func getReplicaSetList(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listReplicaSets func(context.Context, *kubernetes.Clientset, string) ([]model.ReplicaSetStatus, error),
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.ReplicaSetStatus, error) {
+			clientset, _, err := getK8sClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return listReplicaSets(ctx, clientset, params.Namespace)
+		}, ListSuccessMessage)
+	}
+}
+
+func getReplicaSetDetail(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleDetailWithK8s(c, logger, getK8sClient, service.GetReplicaSetDetail, DetailSuccessMessage)
+	}
+}
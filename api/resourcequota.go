@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RegisterResourceQuota 注册 ResourceQuota 相关路由
+func RegisterResourceQuota(
+	r *gin.RouterGroup,
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listResourceQuotas func(context.Context, *kubernetes.Clientset, string) ([]model.ResourceQuotaStatus, error),
+) {
+	r.GET("/resourcequotas", getResourceQuotaList(logger, getK8sClient, listResourceQuotas))
+	r.GET("/resourcequotas/:namespace/:name", getResourceQuotaDetail(logger, getK8sClient))
+}
+
+func getResourceQuotaList(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listResourceQuotas func(context.Context, *kubernetes.Clientset, string) ([]model.ResourceQuotaStatus, error),
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.ResourceQuotaStatus, error) {
+			clientset, _, err := getK8sClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return listResourceQuotas(ctx, clientset, params.Namespace)
+		}, ListSuccessMessage)
+	}
+}
+
+func getResourceQuotaDetail(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleDetailWithK8s(c, logger, getK8sClient, service.GetResourceQuotaDetail, DetailSuccessMessage)
+	}
+}
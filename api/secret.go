@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/audit"
 	"github.com/nick0323/K8sVision/model"
 
 	"github.com/gin-gonic/gin"
@@ -22,6 +23,7 @@ func RegisterSecret(
 ) {
 	r.GET("/secrets", getSecretList(logger, getK8sClient, listSecrets))
 	r.GET("/secrets/:namespace/:name", getSecretDetail(logger, getK8sClient))
+	r.GET("/secrets/:namespace/:name"+middleware.RevealPathSuffix, revealHandler(logger, getK8sClient, "secrets", audit.ActionSecretReveal, fetchSecretData))
 }
 
 // getSecretList 获取Secret列表的处理函数
@@ -32,7 +34,7 @@ func getSecretList(
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.SecretStatus, error) {
-			clientset, _, err := getK8sClient()
+			clientset, _, err := getK8sClient(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -41,36 +43,38 @@ func getSecretList(
 	}
 }
 
-// getSecretDetail 获取Secret详情的处理函数
+// getSecretDetail 获取Secret详情的处理函数，仅返回键名和长度
 func getSecretDetail(
 	logger *zap.Logger,
 	getK8sClient K8sClientProvider,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientset, _, err := getK8sClient()
+		ctx := GetRequestContext(c)
+		clientset, _, err := getK8sClient(ctx)
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
 			return
 		}
-		ctx := GetRequestContext(c)
 		ns := c.Param("namespace")
 		name := c.Param("name")
 		secret, err := clientset.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
+		resource := audit.Resource{Kind: "secrets", Namespace: ns, Name: name}
 		if err != nil {
+			middleware.RecordAudit(c, &audit.Event{
+				Action:   audit.ActionSecretRead,
+				Resource: resource,
+				Outcome:  audit.OutcomeFailure,
+				Reason:   err.Error(),
+			})
 			middleware.ResponseError(c, logger, err, http.StatusNotFound)
 			return
 		}
 
-		keys := make([]string, 0, len(secret.Data))
-		for key := range secret.Data {
-			keys = append(keys, key)
-		}
-
-		// 将base64编码的数据转换为字符串
-		data := make(map[string]string)
+		sizes := make(map[string]int, len(secret.Data))
 		for key, value := range secret.Data {
-			data[key] = string(value)
+			sizes[key] = len(value)
 		}
+		keys, entries := buildDataEntries(sizes, func(string) bool { return true })
 
 		secretDetail := model.SecretDetail{
 			CommonResourceFields: model.CommonResourceFields{
@@ -85,8 +89,27 @@ func getSecretDetail(
 			Type:      string(secret.Type),
 			DataCount: len(secret.Data),
 			Keys:      keys,
-			Data:      data,
+			Entries:   entries,
 		}
+		middleware.RecordAudit(c, &audit.Event{
+			Action:   audit.ActionSecretRead,
+			Resource: resource,
+			Outcome:  audit.OutcomeSuccess,
+			Details:  map[string]interface{}{"keys": keys},
+		})
 		middleware.ResponseSuccess(c, secretDetail, "success", nil)
 	}
 }
+
+// fetchSecretData 读取Secret的解码数据
+func fetchSecretData(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (map[string]string, error) {
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]string, len(secret.Data))
+	for key, value := range secret.Data {
+		data[key] = string(value)
+	}
+	return data, nil
+}
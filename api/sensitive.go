@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/audit"
+	"github.com/nick0323/K8sVision/model"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// dataFetcher 读取资源的键值数据
+type dataFetcher func(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (map[string]string, error)
+
+// canRevealSensitiveData 当前用户角色是否允许查看敏感数据明文
+func canRevealSensitiveData(c *gin.Context) bool {
+	if configManager == nil {
+		return false
+	}
+	role := c.GetString("role")
+	for _, r := range configManager.GetSensitiveDataConfig().RevealRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// isSensitiveConfigMapKey 判断ConfigMap键名是否匹配敏感规则
+func isSensitiveConfigMapKey(key string) bool {
+	if configManager == nil {
+		return false
+	}
+	lower := strings.ToLower(key)
+	for _, pattern := range configManager.GetSensitiveDataConfig().ConfigMapKeyPatterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), lower); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// buildDataEntries 生成按键名排序的数据条目，isMasked 决定条目是否脱敏
+func buildDataEntries(sizes map[string]int, isMasked func(key string) bool) ([]string, []model.DataEntry) {
+	keys := make([]string, 0, len(sizes))
+	for key := range sizes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]model.DataEntry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, model.DataEntry{
+			Key:    key,
+			Size:   sizes[key],
+			Masked: isMasked(key),
+		})
+	}
+	return keys, entries
+}
+
+// revealHandler 返回单个键的明文值，要求 reveal 权限，结果不缓存并记录审计
+func revealHandler(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	kind string,
+	action string,
+	fetch dataFetcher,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+
+		ns := c.Param("namespace")
+		name := c.Param("name")
+		key := c.Query("key")
+		event := &audit.Event{
+			Action:   action,
+			Resource: audit.Resource{Kind: kind, Namespace: ns, Name: name},
+			Details:  map[string]interface{}{"key": key},
+		}
+
+		if !canRevealSensitiveData(c) {
+			event.Outcome = audit.OutcomeDenied
+			event.Reason = "missing_reveal_permission"
+			middleware.RecordAudit(c, event)
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodePermissionDenied,
+				Message: model.GetErrorMessage(model.CodePermissionDenied),
+				Details: "当前角色没有查看敏感数据明文的权限",
+			}, http.StatusForbidden)
+			return
+		}
+
+		if key == "" {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeBadRequest,
+				Message: "缺少参数 key",
+			}, http.StatusBadRequest)
+			return
+		}
+
+		ctx := GetRequestContext(c)
+		clientset, _, err := getK8sClient(ctx)
+		if err != nil {
+			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
+			return
+		}
+
+		data, err := fetch(ctx, clientset, ns, name)
+		if err != nil {
+			event.Outcome = audit.OutcomeFailure
+			event.Reason = err.Error()
+			middleware.RecordAudit(c, event)
+			middleware.ResponseError(c, logger, err, http.StatusNotFound)
+			return
+		}
+
+		value, exists := data[key]
+		if !exists {
+			event.Outcome = audit.OutcomeFailure
+			event.Reason = "key_not_found"
+			middleware.RecordAudit(c, event)
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeResourceNotFound,
+				Message: model.GetErrorMessage(model.CodeResourceNotFound),
+				Details: "键不存在: " + key,
+			}, http.StatusNotFound)
+			return
+		}
+
+		event.Outcome = audit.OutcomeSuccess
+		middleware.RecordAudit(c, event)
+		middleware.ResponseSuccess(c, model.RevealedValue{
+			Namespace: ns,
+			Name:      name,
+			Key:       key,
+			Value:     value,
+		}, SuccessMessage, nil)
+	}
+}
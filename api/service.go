@@ -2,13 +2,12 @@ package api
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -19,6 +18,7 @@ func RegisterService(
 	listServices func(context.Context, *kubernetes.Clientset, string) ([]model.ServiceStatus, error),
 ) {
 	r.GET("/services", getServiceList(logger, getK8sClient, listServices))
+	r.GET("/services/without-endpoints", getServicesWithoutEndpoints(logger, getK8sClient))
 	r.GET("/services/:namespace/:name", getServiceDetail(logger, getK8sClient))
 }
 
@@ -29,7 +29,7 @@ func getServiceList(
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.ServiceStatus, error) {
-			clientset, _, err := getK8sClient()
+			clientset, _, err := getK8sClient(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -38,38 +38,27 @@ func getServiceList(
 	}
 }
 
-func getServiceDetail(
+// getServicesWithoutEndpoints 列出没有就绪端点的Service及诊断原因，namespace 为空时覆盖整个集群
+func getServicesWithoutEndpoints(
 	logger *zap.Logger,
 	getK8sClient K8sClientProvider,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		HandleDetailWithK8s(c, logger, getK8sClient,
-			func(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.ServiceDetail, error) {
-				svc, err := clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
-				if err != nil {
-					return model.ServiceDetail{}, err
-				}
-
-				ports := make([]string, 0, len(svc.Spec.Ports))
-				for _, p := range svc.Spec.Ports {
-					ports = append(ports, fmt.Sprintf("%d/%s", p.Port, p.Protocol))
-				}
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.ServiceEndpointIssue, error) {
+			clientset, _, err := getK8sClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return service.ListServicesWithoutEndpoints(ctx, clientset, params.Namespace)
+		}, ListSuccessMessage)
+	}
+}
 
-				return model.ServiceDetail{
-					CommonResourceFields: model.CommonResourceFields{
-						Namespace: svc.Namespace,
-						Name:      svc.Name,
-						Status:    "Active",
-						BaseMetadata: model.BaseMetadata{
-							Labels:      svc.Labels,
-							Annotations: svc.Annotations,
-						},
-					},
-					Type:      string(svc.Spec.Type),
-					ClusterIP: svc.Spec.ClusterIP,
-					Ports:     ports,
-					Selector:  svc.Spec.Selector,
-				}, nil
-			}, DetailSuccessMessage)
+func getServiceDetail(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleDetailWithK8s(c, logger, getK8sClient, service.GetServiceDetail, DetailSuccessMessage)
 	}
 }
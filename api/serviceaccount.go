@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RegisterServiceAccount 注册 ServiceAccount 相关路由
+func RegisterServiceAccount(
+	r *gin.RouterGroup,
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listServiceAccounts func(context.Context, *kubernetes.Clientset, string) ([]model.ServiceAccountStatus, error),
+) {
+	r.GET("/serviceaccounts", getServiceAccountList(logger, getK8sClient, listServiceAccounts))
+	r.GET("/serviceaccounts/:namespace/:name", getServiceAccountDetail(logger, getK8sClient))
+}
+
+func getServiceAccountList(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+	listServiceAccounts func(context.Context, *kubernetes.Clientset, string) ([]model.ServiceAccountStatus, error),
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.ServiceAccountStatus, error) {
+			clientset, _, err := getK8sClient(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return listServiceAccounts(ctx, clientset, params.Namespace)
+		}, ListSuccessMessage)
+	}
+}
+
+func getServiceAccountDetail(
+	logger *zap.Logger,
+	getK8sClient K8sClientProvider,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleDetailWithK8s(c, logger, getK8sClient, service.GetServiceAccountDetail, DetailSuccessMessage)
+	}
+}
@@ -23,6 +23,7 @@ func RegisterStatefulSet(
 ) {
 	r.GET("/statefulsets", getStatefulSetList(logger, getK8sClient, listStatefulSets))
 	r.GET("/statefulsets/:namespace/:name", getStatefulSetDetail(logger, getK8sClient))
+	r.GET("/statefulsets/:namespace/:name/timeline", getWorkloadTimeline(logger, getK8sClient, service.WorkloadStatefulSet))
 }
 
 // getStatefulSetList 获取StatefulSet列表的处理函数
@@ -33,7 +34,7 @@ func getStatefulSetList(
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.StatefulSetStatus, error) {
-			clientset, _, err := getK8sClient()
+			clientset, _, err := getK8sClient(ctx)
 			if err != nil {
 				return nil, err
 			}
@@ -48,12 +49,12 @@ func getStatefulSetDetail(
 	getK8sClient K8sClientProvider,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientset, _, err := getK8sClient()
+		ctx := GetRequestContext(c)
+		clientset, _, err := getK8sClient(ctx)
 		if err != nil {
 			middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
 			return
 		}
-		ctx := GetRequestContext(c)
 		ns := c.Param("namespace")
 		name := c.Param("name")
 		sts, err := clientset.AppsV1().StatefulSets(ns).Get(ctx, name, metav1.GetOptions{})
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/service"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// getWorkloadTimeline 返回工作负载的关联时间线，支持 from/to 时间范围（RFC3339或Unix秒）
+func getWorkloadTimeline(logger *zap.Logger, getK8sClient K8sClientProvider, kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, err := parseTimeParam(c.Query("from"))
+		if err != nil {
+			middleware.ResponseError(c, logger, invalidQueryParam("from", err.Error()), http.StatusBadRequest)
+			return
+		}
+		to, err := parseTimeParam(c.Query("to"))
+		if err != nil {
+			middleware.ResponseError(c, logger, invalidQueryParam("to", err.Error()), http.StatusBadRequest)
+			return
+		}
+
+		HandleDetailWithK8s(c, logger, getK8sClient,
+			func(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (*model.WorkloadTimeline, error) {
+				return service.GetWorkloadTimeline(ctx, clientset, kind, namespace, name, from, to)
+			}, DetailSuccessMessage)
+	}
+}
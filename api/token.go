@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/audit"
+	"github.com/nick0323/K8sVision/model"
+	"go.uber.org/zap"
+)
+
+// RegisterToken 注册API令牌管理路由
+func RegisterToken(r *gin.RouterGroup, logger *zap.Logger) {
+	r.GET("/tokens", listTokens(logger))
+	r.POST("/tokens", createToken(logger))
+	r.DELETE("/tokens/:id", revokeToken(logger))
+}
+
+// listTokens 列出当前用户的令牌，管理员可通过 all=true 查看全部令牌
+func listTokens(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		HandleListWithPagination(c, logger, func(ctx context.Context, params PaginationParams) ([]model.APITokenInfo, error) {
+			if tokenManager == nil {
+				return nil, tokenManagerUnavailable()
+			}
+			owner := c.GetString("username")
+			if c.Query("all") == "true" && c.GetString("role") == model.RoleAdmin {
+				owner = ""
+			}
+			return tokenManager.List(owner), nil
+		}, ListSuccessMessage)
+	}
+}
+
+// createToken 创建令牌，仅允许通过登录会话创建，避免令牌自我扩散
+func createToken(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tokenManager == nil {
+			middleware.ResponseError(c, logger, tokenManagerUnavailable(), http.StatusServiceUnavailable)
+			return
+		}
+
+		if c.GetString("authMethod") != model.AuthMethodJWT {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodePermissionDenied,
+				Message: model.GetErrorMessage(model.CodePermissionDenied),
+				Details: "API令牌不能用于创建新令牌，请使用登录会话",
+			}, http.StatusForbidden)
+			return
+		}
+
+		var req model.APITokenCreateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeBadRequest,
+				Message: "请求参数格式错误",
+				Details: err.Error(),
+			}, http.StatusBadRequest)
+			return
+		}
+
+		resp, err := tokenManager.Create(c.GetString("username"), c.GetString("role"), req)
+		if err != nil {
+			middleware.RecordAudit(c, &audit.Event{
+				Action:   audit.ActionTokenCreate,
+				Resource: audit.Resource{Kind: "token", Name: req.Name},
+				Outcome:  audit.OutcomeFailure,
+				Reason:   err.Error(),
+			})
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeValidationFailed,
+				Message: "创建令牌失败",
+				Details: err.Error(),
+			}, http.StatusBadRequest)
+			return
+		}
+
+		middleware.RecordAudit(c, &audit.Event{
+			Action:   audit.ActionTokenCreate,
+			Resource: audit.Resource{Kind: "token", Name: resp.Name},
+			Outcome:  audit.OutcomeSuccess,
+			Details: map[string]interface{}{
+				"tokenId":   resp.ID,
+				"role":      resp.Role,
+				"expiresAt": resp.ExpiresAt,
+			},
+		})
+
+		middleware.ResponseSuccess(c, resp, CreateSuccessMessage, nil)
+	}
+}
+
+// revokeToken 撤销令牌，管理员可撤销任意用户的令牌
+func revokeToken(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tokenManager == nil {
+			middleware.ResponseError(c, logger, tokenManagerUnavailable(), http.StatusServiceUnavailable)
+			return
+		}
+
+		owner := c.GetString("username")
+		if c.GetString("role") == model.RoleAdmin {
+			owner = ""
+		}
+
+		if err := tokenManager.Revoke(c.Param("id"), owner); err != nil {
+			middleware.RecordAudit(c, &audit.Event{
+				Action:   audit.ActionTokenRevoke,
+				Resource: audit.Resource{Kind: "token", Name: c.Param("id")},
+				Outcome:  audit.OutcomeFailure,
+				Reason:   err.Error(),
+			})
+			httpCode := http.StatusInternalServerError
+			code := model.CodeInternalServerError
+			switch {
+			case errors.Is(err, ErrTokenNotFound):
+				httpCode, code = http.StatusNotFound, model.CodeResourceNotFound
+			case errors.Is(err, ErrTokenRevoked):
+				httpCode, code = http.StatusConflict, model.CodeConflict
+			}
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    code,
+				Message: model.GetErrorMessage(code),
+				Details: err.Error(),
+			}, httpCode)
+			return
+		}
+
+		middleware.RecordAudit(c, &audit.Event{
+			Action:   audit.ActionTokenRevoke,
+			Resource: audit.Resource{Kind: "token", Name: c.Param("id")},
+			Outcome:  audit.OutcomeSuccess,
+		})
+
+		middleware.ResponseSuccess(c, gin.H{"id": c.Param("id")}, DeleteSuccessMessage, nil)
+	}
+}
+
+func tokenManagerUnavailable() *model.APIError {
+	return &model.APIError{
+		Code:    model.CodeServiceUnavailable,
+		Message: model.GetErrorMessage(model.CodeServiceUnavailable),
+		Details: "API令牌管理器未初始化",
+	}
+}
@@ -0,0 +1,381 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/config"
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/store"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTokenNotFound = errors.New("令牌不存在")
+	ErrTokenExpired  = errors.New("令牌已过期")
+	ErrTokenRevoked  = errors.New("令牌已撤销")
+	ErrTokenInvalid  = errors.New("令牌格式错误")
+)
+
+const (
+	TokenStatusActive  = "active"
+	TokenStatusExpired = "expired"
+	TokenStatusRevoked = "revoked"
+)
+
+var tokenManager *TokenManager
+
+// TokenManager 长期API令牌管理器，令牌以SHA-256哈希形式持久化到本地文件
+type TokenManager struct {
+	tokens map[string]*model.APIToken // key: 令牌ID
+	byHash map[string]string          // key: 令牌哈希, value: 令牌ID
+	mutex  sync.RWMutex
+	logger *zap.Logger
+	config *config.Manager
+	file   string
+	dirty  bool
+	stopCh chan struct{}
+}
+
+func NewTokenManager(logger *zap.Logger, configMgr *config.Manager) (*TokenManager, error) {
+	tm := &TokenManager{
+		tokens: make(map[string]*model.APIToken),
+		byHash: make(map[string]string),
+		logger: logger,
+		config: configMgr,
+		file:   configMgr.GetAuthConfig().TokenFile,
+		stopCh: make(chan struct{}),
+	}
+
+	if err := tm.load(); err != nil {
+		return nil, err
+	}
+
+	go tm.startFlush()
+	return tm, nil
+}
+
+// InitTokenManager 初始化全局API令牌管理器
+func InitTokenManager(logger *zap.Logger) {
+	if configManager == nil {
+		logger.Fatal("配置管理器未初始化")
+		return
+	}
+	tm, err := NewTokenManager(logger, configManager)
+	if err != nil {
+		logger.Error("API令牌管理器初始化失败，令牌认证不可用", zap.Error(err))
+		return
+	}
+	tokenManager = tm
+}
+
+// GetTokenValidator 返回令牌校验器，未初始化时返回nil
+func GetTokenValidator() middleware.TokenValidator {
+	if tokenManager == nil {
+		return nil
+	}
+	return tokenManager
+}
+
+// CloseTokenManager 关闭全局API令牌管理器
+func CloseTokenManager() {
+	if tokenManager != nil {
+		tokenManager.Close()
+	}
+}
+
+// Create 创建新令牌，明文令牌仅在返回值中出现一次
+func (tm *TokenManager) Create(owner, ownerRole string, req model.APITokenCreateRequest) (*model.APITokenCreateResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 64 {
+		return nil, fmt.Errorf("令牌名称长度必须在1-64个字符之间")
+	}
+
+	role := req.Role
+	if role == "" {
+		role = model.RoleViewer
+	}
+	if role != model.RoleAdmin && role != model.RoleViewer {
+		return nil, fmt.Errorf("无效的角色: %s", role)
+	}
+	if role == model.RoleAdmin && ownerRole != model.RoleAdmin {
+		return nil, fmt.Errorf("无权创建管理员令牌")
+	}
+
+	now := time.Now()
+	var expiresAt *time.Time
+	maxTTL := tm.config.GetAuthConfig().MaxTokenTTL
+	if req.ExpiresIn != "" {
+		ttl, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("无效的有效期: %s", req.ExpiresIn)
+		}
+		if maxTTL > 0 && ttl > maxTTL {
+			return nil, fmt.Errorf("有效期不能超过%s", maxTTL.String())
+		}
+		t := now.Add(ttl)
+		expiresAt = &t
+	} else if maxTTL > 0 {
+		return nil, fmt.Errorf("必须设置有效期，且不超过%s", maxTTL.String())
+	}
+
+	secret, err := randomString(32)
+	if err != nil {
+		return nil, fmt.Errorf("生成令牌失败: %w", err)
+	}
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, fmt.Errorf("生成令牌ID失败: %w", err)
+	}
+
+	plain := model.APITokenPrefix + secret
+	token := &model.APIToken{
+		ID:        id,
+		Name:      name,
+		Owner:     owner,
+		Role:      role,
+		Hash:      hashToken(plain),
+		Prefix:    plain[:len(model.APITokenPrefix)+6],
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+
+	tm.mutex.Lock()
+	for _, t := range tm.tokens {
+		if t.Owner == owner && t.Name == name && t.RevokedAt == nil {
+			tm.mutex.Unlock()
+			return nil, fmt.Errorf("令牌名称已存在: %s", name)
+		}
+	}
+	tm.tokens[id] = token
+	tm.byHash[token.Hash] = id
+	err = tm.saveLocked()
+	if err != nil {
+		delete(tm.tokens, id)
+		delete(tm.byHash, token.Hash)
+	}
+	tm.mutex.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	tm.logger.Info("API令牌已创建",
+		zap.String("owner", owner),
+		zap.String("tokenId", id),
+		zap.String("name", name),
+		zap.String("role", role),
+	)
+
+	return &model.APITokenCreateResponse{
+		APITokenInfo: tokenInfo(token, now),
+		Token:        plain,
+	}, nil
+}
+
+// List 列出令牌；owner为空时返回全部令牌
+func (tm *TokenManager) List(owner string) []model.APITokenInfo {
+	tm.mutex.RLock()
+	defer tm.mutex.RUnlock()
+
+	now := time.Now()
+	result := make([]model.APITokenInfo, 0, len(tm.tokens))
+	for _, t := range tm.tokens {
+		if owner != "" && t.Owner != owner {
+			continue
+		}
+		result = append(result, tokenInfo(t, now))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt > result[j].CreatedAt
+	})
+	return result
+}
+
+// Revoke 撤销令牌；owner为空时允许撤销任意令牌
+func (tm *TokenManager) Revoke(id, owner string) error {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	token, exists := tm.tokens[id]
+	if !exists || (owner != "" && token.Owner != owner) {
+		return ErrTokenNotFound
+	}
+	if token.RevokedAt != nil {
+		return ErrTokenRevoked
+	}
+
+	now := time.Now()
+	token.RevokedAt = &now
+	delete(tm.byHash, token.Hash)
+	if err := tm.saveLocked(); err != nil {
+		token.RevokedAt = nil
+		tm.byHash[token.Hash] = id
+		return err
+	}
+
+	tm.logger.Info("API令牌已撤销",
+		zap.String("owner", token.Owner),
+		zap.String("tokenId", id),
+	)
+	return nil
+}
+
+// ValidateToken 校验明文令牌并记录最近使用时间和来源IP
+func (tm *TokenManager) ValidateToken(plain, clientIP string) (*model.AuthIdentity, error) {
+	if !strings.HasPrefix(plain, model.APITokenPrefix) || len(plain) <= len(model.APITokenPrefix) {
+		return nil, ErrTokenInvalid
+	}
+
+	hash := hashToken(plain)
+
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	id, exists := tm.byHash[hash]
+	if !exists {
+		return nil, ErrTokenNotFound
+	}
+	token := tm.tokens[id]
+	if token.RevokedAt != nil {
+		return nil, ErrTokenRevoked
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && now.After(*token.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	token.LastUsedAt = &now
+	token.LastUsedIP = clientIP
+	tm.dirty = true
+
+	return &model.AuthIdentity{
+		Username: token.Owner,
+		Role:     token.Role,
+		Method:   model.AuthMethodAPIToken,
+		TokenID:  token.ID,
+	}, nil
+}
+
+func (tm *TokenManager) Close() {
+	close(tm.stopCh)
+
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	if tm.dirty {
+		if err := tm.saveLocked(); err != nil {
+			tm.logger.Error("保存API令牌失败", zap.Error(err))
+		}
+	}
+	tm.logger.Info("API令牌管理器已关闭")
+}
+
+func (tm *TokenManager) load() error {
+	var tokens []*model.APIToken
+	if err := store.LoadJSON(tm.file, &tokens); err != nil {
+		return fmt.Errorf("加载API令牌失败: %w", err)
+	}
+	for _, t := range tokens {
+		tm.tokens[t.ID] = t
+		if t.RevokedAt == nil {
+			tm.byHash[t.Hash] = t.ID
+		}
+	}
+	tm.logger.Info("API令牌加载完成", zap.Int("count", len(tokens)), zap.String("file", tm.file))
+	return nil
+}
+
+// saveLocked 持久化令牌，调用方需持有写锁
+func (tm *TokenManager) saveLocked() error {
+	tokens := make([]*model.APIToken, 0, len(tm.tokens))
+	for _, t := range tm.tokens {
+		tokens = append(tokens, t)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+	})
+	if err := store.SaveJSON(tm.file, tokens); err != nil {
+		return fmt.Errorf("保存API令牌失败: %w", err)
+	}
+	tm.dirty = false
+	return nil
+}
+
+// startFlush 定期将最近使用信息落盘，避免每次请求都写文件
+func (tm *TokenManager) startFlush() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			tm.mutex.Lock()
+			if tm.dirty {
+				if err := tm.saveLocked(); err != nil {
+					tm.logger.Warn("保存API令牌使用记录失败", zap.Error(err))
+				}
+			}
+			tm.mutex.Unlock()
+		case <-tm.stopCh:
+			return
+		}
+	}
+}
+
+func tokenInfo(t *model.APIToken, now time.Time) model.APITokenInfo {
+	status := TokenStatusActive
+	if t.RevokedAt != nil {
+		status = TokenStatusRevoked
+	} else if t.ExpiresAt != nil && now.After(*t.ExpiresAt) {
+		status = TokenStatusExpired
+	}
+
+	info := model.APITokenInfo{
+		ID:         t.ID,
+		Name:       t.Name,
+		Owner:      t.Owner,
+		Role:       t.Role,
+		Prefix:     t.Prefix,
+		CreatedAt:  model.FormatTimeValue(t.CreatedAt),
+		LastUsedIP: t.LastUsedIP,
+		Status:     status,
+	}
+	if t.ExpiresAt != nil {
+		info.ExpiresAt = model.FormatTimeValue(*t.ExpiresAt)
+	}
+	if t.LastUsedAt != nil {
+		info.LastUsedAt = model.FormatTimeValue(*t.LastUsedAt)
+	}
+	return info
+}
+
+func hashToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
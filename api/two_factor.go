@@ -0,0 +1,286 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/audit"
+	"github.com/nick0323/K8sVision/model"
+	"go.uber.org/zap"
+)
+
+type TwoFactorEnforceRequest struct {
+	Enforce bool `json:"enforce"`
+}
+
+// RegisterTwoFactor 注册当前用户的双因素认证管理路由
+func RegisterTwoFactor(r *gin.RouterGroup, logger *zap.Logger) {
+	r.GET("/auth/2fa/status", getTwoFactorStatus(logger))
+	r.POST("/auth/2fa/setup", setupTwoFactor(logger))
+	r.POST("/auth/2fa/enable", enableTwoFactor(logger))
+	r.POST("/auth/2fa/disable", disableTwoFactor(logger))
+	r.POST("/auth/2fa/recovery-codes", regenerateRecoveryCodes(logger))
+}
+
+// RegisterTwoFactorAdmin 注册管理员的双因素认证策略路由
+func RegisterTwoFactorAdmin(r *gin.RouterGroup, logger *zap.Logger) {
+	r.POST("/admin/2fa/enforce", enforceTwoFactor(logger))
+	r.DELETE("/admin/2fa/:username", resetTwoFactor(logger))
+}
+
+func getTwoFactorStatus(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requireTwoFactorManager(c, logger) {
+			return
+		}
+		middleware.ResponseSuccess(c, twoFactorManager.Status(c.GetString("username")), SuccessMessage, nil)
+	}
+}
+
+// setupTwoFactor 生成新的TOTP密钥和二维码URI，需调用 enable 确认后才生效
+func setupTwoFactor(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requireTwoFactorManager(c, logger) || !requireSessionAuth(c, logger) {
+			return
+		}
+
+		resp, err := twoFactorManager.BeginSetup(c.GetString("username"))
+		if err != nil {
+			respondTwoFactorError(c, logger, err)
+			return
+		}
+		middleware.ResponseSuccess(c, resp, SuccessMessage, nil)
+	}
+}
+
+// enableTwoFactor 使用验证码确认绑定，返回恢复码（仅展示一次）
+// 若当前为强制绑定的受限会话，同时返回完整会话令牌
+func enableTwoFactor(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requireTwoFactorManager(c, logger) || !requireSessionAuth(c, logger) {
+			return
+		}
+
+		var req model.TwoFactorCodeRequest
+		if !bindTwoFactorCode(c, logger, &req) {
+			return
+		}
+
+		username := c.GetString("username")
+		codes, err := twoFactorManager.Enable(username, req.Code)
+		if err != nil {
+			recordTwoFactorAudit(c, audit.ActionTwoFactorEnable, username, err)
+			respondTwoFactorError(c, logger, err)
+			return
+		}
+		recordTwoFactorAudit(c, audit.ActionTwoFactorEnable, username, nil)
+
+		data := gin.H{"recoveryCodes": codes}
+		if c.GetString("tokenScope") == model.TokenScopeTwoFactorSetup {
+			token, err := issueSessionToken(username, "")
+			if err != nil {
+				middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
+				return
+			}
+			data["token"] = token
+		}
+
+		middleware.ResponseSuccess(c, data, "双因素认证已启用", nil)
+	}
+}
+
+func disableTwoFactor(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requireTwoFactorManager(c, logger) || !requireSessionAuth(c, logger) {
+			return
+		}
+
+		var req model.TwoFactorCodeRequest
+		if !bindTwoFactorCode(c, logger, &req) {
+			return
+		}
+
+		err := twoFactorManager.Disable(c.GetString("username"), req.Code)
+		recordTwoFactorAudit(c, audit.ActionTwoFactorDisable, c.GetString("username"), err)
+		if err != nil {
+			respondTwoFactorError(c, logger, err)
+			return
+		}
+		middleware.ResponseSuccess(c, nil, "双因素认证已关闭", nil)
+	}
+}
+
+func regenerateRecoveryCodes(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requireTwoFactorManager(c, logger) || !requireSessionAuth(c, logger) {
+			return
+		}
+
+		var req model.TwoFactorCodeRequest
+		if !bindTwoFactorCode(c, logger, &req) {
+			return
+		}
+
+		codes, err := twoFactorManager.RegenerateRecoveryCodes(c.GetString("username"), req.Code)
+		if err != nil {
+			respondTwoFactorError(c, logger, err)
+			return
+		}
+		middleware.ResponseSuccess(c, gin.H{"recoveryCodes": codes}, "恢复码已重新生成", nil)
+	}
+}
+
+// enforceTwoFactor 开启或关闭全局强制双因素认证，并写回配置文件
+func enforceTwoFactor(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req TwoFactorEnforceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.ResponseError(c, logger, &model.APIError{
+				Code:    model.CodeBadRequest,
+				Message: "请求参数格式错误",
+				Details: err.Error(),
+			}, http.StatusBadRequest)
+			return
+		}
+
+		err := configManager.UpdateAndWrite("auth.twoFactor.enforce", req.Enforce, func(cfg *model.Config) {
+			cfg.Auth.TwoFactor.Enforce = req.Enforce
+		})
+
+		middleware.RecordAudit(c, &audit.Event{
+			Action:   audit.ActionTwoFactorEnforce,
+			Resource: audit.Resource{Kind: "2fa"},
+			Outcome:  audit.OutcomeSuccess,
+			Details: map[string]interface{}{
+				"enforce":   req.Enforce,
+				"persisted": err == nil,
+			},
+		})
+
+		logger.Info("双因素认证强制策略已更新",
+			zap.String("username", c.GetString("username")),
+			zap.Bool("enforce", req.Enforce),
+		)
+
+		if err != nil {
+			logger.Error("写入配置文件失败", zap.Error(err))
+			middleware.ResponseSuccess(c, gin.H{
+				"enforce":   req.Enforce,
+				"persisted": false,
+			}, "策略已生效，但未能写入配置文件", nil)
+			return
+		}
+
+		middleware.ResponseSuccess(c, gin.H{
+			"enforce":   req.Enforce,
+			"persisted": true,
+		}, UpdateSuccessMessage, nil)
+	}
+}
+
+// resetTwoFactor 管理员清除指定用户的双因素认证（如丢失设备和恢复码）
+func resetTwoFactor(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requireTwoFactorManager(c, logger) {
+			return
+		}
+
+		username := c.Param("username")
+		err := twoFactorManager.Reset(username)
+		recordTwoFactorAudit(c, audit.ActionTwoFactorReset, username, err)
+		if err != nil {
+			respondTwoFactorError(c, logger, err)
+			return
+		}
+
+		logger.Info("管理员重置双因素认证",
+			zap.String("operator", c.GetString("username")),
+			zap.String("username", username),
+		)
+		middleware.ResponseSuccess(c, gin.H{"username": username}, "双因素认证已重置", nil)
+	}
+}
+
+// recordTwoFactorAudit 记录双因素认证设置类操作的审计事件
+func recordTwoFactorAudit(c *gin.Context, action, username string, err error) {
+	e := &audit.Event{
+		Action:   action,
+		Resource: audit.Resource{Kind: "2fa", Name: username},
+		Outcome:  audit.OutcomeSuccess,
+	}
+	if err != nil {
+		e.Outcome = audit.OutcomeFailure
+		e.Reason = err.Error()
+	}
+	middleware.RecordAudit(c, e)
+}
+
+func requireTwoFactorManager(c *gin.Context, logger *zap.Logger) bool {
+	if twoFactorManager != nil {
+		return true
+	}
+	middleware.ResponseError(c, logger, &model.APIError{
+		Code:    model.CodeServiceUnavailable,
+		Message: model.GetErrorMessage(model.CodeServiceUnavailable),
+		Details: "双因素认证管理器未初始化",
+	}, http.StatusServiceUnavailable)
+	return false
+}
+
+// requireSessionAuth 2FA绑定操作只能通过登录会话进行，不接受API令牌
+func requireSessionAuth(c *gin.Context, logger *zap.Logger) bool {
+	if c.GetString("authMethod") == model.AuthMethodJWT {
+		return true
+	}
+	middleware.ResponseError(c, logger, &model.APIError{
+		Code:    model.CodePermissionDenied,
+		Message: model.GetErrorMessage(model.CodePermissionDenied),
+		Details: "请使用登录会话进行双因素认证设置",
+	}, http.StatusForbidden)
+	return false
+}
+
+func bindTwoFactorCode(c *gin.Context, logger *zap.Logger, req *model.TwoFactorCodeRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		middleware.ResponseError(c, logger, &model.APIError{
+			Code:    model.CodeBadRequest,
+			Message: "请求参数格式错误",
+			Details: err.Error(),
+		}, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// respondTwoFactorError 将双因素认证错误转换为API错误；验证码错误计入登录失败次数
+func respondTwoFactorError(c *gin.Context, logger *zap.Logger, err error) {
+	switch {
+	case errors.Is(err, ErrTwoFactorInvalidCode):
+		if authManager != nil {
+			authManager.RecordFailure(c.GetString("username"), c.ClientIP())
+		}
+		middleware.ResponseError(c, logger, &model.APIError{
+			Code:    model.CodeValidationFailed,
+			Message: err.Error(),
+		}, http.StatusBadRequest)
+	case errors.Is(err, ErrTwoFactorNotEnabled):
+		middleware.ResponseError(c, logger, &model.APIError{
+			Code:    model.CodeResourceNotFound,
+			Message: err.Error(),
+		}, http.StatusNotFound)
+	case errors.Is(err, ErrTwoFactorAlreadyEnabled), errors.Is(err, ErrTwoFactorNoPending):
+		middleware.ResponseError(c, logger, &model.APIError{
+			Code:    model.CodeConflict,
+			Message: err.Error(),
+		}, http.StatusConflict)
+	case errors.Is(err, ErrTwoFactorEnforced):
+		middleware.ResponseError(c, logger, &model.APIError{
+			Code:    model.CodePermissionDenied,
+			Message: err.Error(),
+		}, http.StatusForbidden)
+	default:
+		middleware.ResponseError(c, logger, err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,294 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nick0323/K8sVision/config"
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/store"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTwoFactorNotEnabled     = errors.New("未启用双因素认证")
+	ErrTwoFactorAlreadyEnabled = errors.New("已启用双因素认证")
+	ErrTwoFactorNoPending      = errors.New("请先开始绑定流程")
+	ErrTwoFactorInvalidCode    = errors.New("验证码错误")
+	ErrTwoFactorEnforced       = errors.New("管理员已强制启用双因素认证，不能关闭")
+)
+
+const recoveryCodeCount = 10
+
+var twoFactorManager *TwoFactorManager
+
+// TwoFactorManager 管理用户的TOTP绑定、验证码校验和恢复码
+type TwoFactorManager struct {
+	records map[string]*model.TwoFactorRecord
+	mutex   sync.Mutex
+	logger  *zap.Logger
+	config  *config.Manager
+	file    string
+}
+
+func NewTwoFactorManager(logger *zap.Logger, configMgr *config.Manager) (*TwoFactorManager, error) {
+	tfm := &TwoFactorManager{
+		records: make(map[string]*model.TwoFactorRecord),
+		logger:  logger,
+		config:  configMgr,
+		file:    configMgr.GetAuthConfig().TwoFactor.File,
+	}
+
+	var records []*model.TwoFactorRecord
+	if err := store.LoadJSON(tfm.file, &records); err != nil {
+		return nil, fmt.Errorf("加载双因素认证数据失败: %w", err)
+	}
+	for _, r := range records {
+		tfm.records[r.Username] = r
+	}
+	return tfm, nil
+}
+
+// InitTwoFactorManager 初始化全局双因素认证管理器
+func InitTwoFactorManager(logger *zap.Logger) {
+	if configManager == nil {
+		logger.Fatal("配置管理器未初始化")
+		return
+	}
+	tfm, err := NewTwoFactorManager(logger, configManager)
+	if err != nil {
+		// 无法确认用户是否启用了2FA时拒绝启动，避免降级为仅密码登录
+		logger.Fatal("双因素认证管理器初始化失败", zap.Error(err))
+		return
+	}
+	twoFactorManager = tfm
+}
+
+// IsEnforced 是否强制所有用户启用双因素认证
+func (tfm *TwoFactorManager) IsEnforced() bool {
+	return tfm.config.GetAuthConfig().TwoFactor.Enforce
+}
+
+// IsEnabled 用户是否已启用双因素认证
+func (tfm *TwoFactorManager) IsEnabled(username string) bool {
+	tfm.mutex.Lock()
+	defer tfm.mutex.Unlock()
+
+	r, exists := tfm.records[username]
+	return exists && r.Enabled
+}
+
+// Status 获取用户的双因素认证状态
+func (tfm *TwoFactorManager) Status(username string) model.TwoFactorStatus {
+	tfm.mutex.Lock()
+	defer tfm.mutex.Unlock()
+
+	status := model.TwoFactorStatus{
+		Username: username,
+		Enforced: tfm.IsEnforced(),
+	}
+	if r, exists := tfm.records[username]; exists {
+		status.Enabled = r.Enabled
+		status.Pending = r.PendingSecret != ""
+		status.RecoveryCodesLeft = len(r.RecoveryCodes)
+		if r.EnabledAt != nil {
+			status.EnabledAt = model.FormatTimeValue(*r.EnabledAt)
+		}
+	}
+	return status
+}
+
+// BeginSetup 生成待确认的TOTP密钥
+func (tfm *TwoFactorManager) BeginSetup(username string) (*model.TwoFactorSetupResponse, error) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	tfm.mutex.Lock()
+	defer tfm.mutex.Unlock()
+
+	r := tfm.recordLocked(username)
+	if r.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	r.PendingSecret = secret
+	if err := tfm.saveLocked(); err != nil {
+		return nil, err
+	}
+
+	issuer := tfm.config.GetAuthConfig().TwoFactor.Issuer
+	return &model.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: totpProvisioningURI(issuer, username, secret),
+	}, nil
+}
+
+// Enable 使用验证码确认绑定并启用，返回一次性展示的恢复码
+func (tfm *TwoFactorManager) Enable(username, code string) ([]string, error) {
+	tfm.mutex.Lock()
+	defer tfm.mutex.Unlock()
+
+	r := tfm.recordLocked(username)
+	if r.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if r.PendingSecret == "" {
+		return nil, ErrTwoFactorNoPending
+	}
+
+	step, ok := verifyTOTP(r.PendingSecret, code, time.Now(), 0)
+	if !ok {
+		return nil, ErrTwoFactorInvalidCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	r.Secret = r.PendingSecret
+	r.PendingSecret = ""
+	r.Enabled = true
+	r.EnabledAt = &now
+	r.LastStep = step
+	r.RecoveryCodes = hashes
+	if err := tfm.saveLocked(); err != nil {
+		return nil, err
+	}
+
+	tfm.logger.Info("双因素认证已启用", zap.String("username", username))
+	return codes, nil
+}
+
+// Verify 校验TOTP验证码或恢复码，恢复码使用后立即失效
+func (tfm *TwoFactorManager) Verify(username, code string) error {
+	tfm.mutex.Lock()
+	defer tfm.mutex.Unlock()
+	return tfm.verifyLocked(username, code)
+}
+
+// verifyLocked 同 Verify，调用方需持有锁
+func (tfm *TwoFactorManager) verifyLocked(username, code string) error {
+	r, exists := tfm.records[username]
+	if !exists || !r.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if step, ok := verifyTOTP(r.Secret, code, time.Now(), r.LastStep); ok {
+		r.LastStep = step
+		return tfm.saveLocked()
+	}
+
+	hash := hashToken(normalizeRecoveryCode(code))
+	for i, h := range r.RecoveryCodes {
+		if h == hash {
+			r.RecoveryCodes = append(r.RecoveryCodes[:i], r.RecoveryCodes[i+1:]...)
+			tfm.logger.Warn("使用恢复码登录",
+				zap.String("username", username),
+				zap.Int("recoveryCodesLeft", len(r.RecoveryCodes)),
+			)
+			return tfm.saveLocked()
+		}
+	}
+
+	return ErrTwoFactorInvalidCode
+}
+
+// RegenerateRecoveryCodes 校验验证码后重新生成恢复码
+// 校验与替换在同一次持锁内完成，避免期间被并发关闭
+func (tfm *TwoFactorManager) RegenerateRecoveryCodes(username, code string) ([]string, error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	tfm.mutex.Lock()
+	defer tfm.mutex.Unlock()
+	if err := tfm.verifyLocked(username, code); err != nil {
+		return nil, err
+	}
+	tfm.records[username].RecoveryCodes = hashes
+	if err := tfm.saveLocked(); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable 校验验证码后关闭双因素认证；强制模式下不允许关闭
+func (tfm *TwoFactorManager) Disable(username, code string) error {
+	if tfm.IsEnforced() {
+		return ErrTwoFactorEnforced
+	}
+	if err := tfm.Verify(username, code); err != nil {
+		return err
+	}
+	return tfm.Reset(username)
+}
+
+// Reset 清除用户的双因素认证数据（管理员操作或用户关闭）
+func (tfm *TwoFactorManager) Reset(username string) error {
+	tfm.mutex.Lock()
+	defer tfm.mutex.Unlock()
+
+	if _, exists := tfm.records[username]; !exists {
+		return ErrTwoFactorNotEnabled
+	}
+	delete(tfm.records, username)
+	if err := tfm.saveLocked(); err != nil {
+		return err
+	}
+
+	tfm.logger.Info("双因素认证已关闭", zap.String("username", username))
+	return nil
+}
+
+func (tfm *TwoFactorManager) recordLocked(username string) *model.TwoFactorRecord {
+	r, exists := tfm.records[username]
+	if !exists {
+		r = &model.TwoFactorRecord{Username: username}
+		tfm.records[username] = r
+	}
+	return r
+}
+
+// saveLocked 持久化双因素认证数据，调用方需持有锁
+func (tfm *TwoFactorManager) saveLocked() error {
+	records := make([]*model.TwoFactorRecord, 0, len(tfm.records))
+	for _, r := range tfm.records {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Username < records[j].Username
+	})
+	if err := store.SaveJSON(tfm.file, records); err != nil {
+		return fmt.Errorf("保存双因素认证数据失败: %w", err)
+	}
+	return nil
+}
+
+// generateRecoveryCodes 生成恢复码明文及其哈希
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := randomHex(5)
+		if err != nil {
+			return nil, nil, fmt.Errorf("生成恢复码失败: %w", err)
+		}
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashToken(normalizeRecoveryCode(code)))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(strings.ReplaceAll(code, "-", ""), " ", "")
+}
@@ -0,0 +1,301 @@
+// Package archive 提供Kubernetes事件的后台监听与内嵌归档存储
+package archive
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/store"
+)
+
+// Record 一条归档事件，同一事件（按UID）的多次更新合并为一条记录
+type Record struct {
+	UID        string
+	Namespace  string
+	Name       string
+	ObjectKind string
+	ObjectName string
+	Reason     string
+	Message    string
+	Type       string
+	Source     string
+	Count      int32
+	FirstSeen  time.Time
+	LastSeen   time.Time
+}
+
+// Object 返回 Kind/Name 形式的关联对象描述
+func (r *Record) Object() string {
+	if r.ObjectKind == "" {
+		return r.ObjectName
+	}
+	return r.ObjectKind + "/" + r.ObjectName
+}
+
+// Status 转换为事件列表使用的结构
+func (r *Record) Status() model.EventStatus {
+	return model.EventStatus{
+		Namespace: r.Namespace,
+		Name:      r.Name,
+		Reason:    r.Reason,
+		Message:   r.Message,
+		Type:      r.Type,
+		Count:     r.Count,
+		FirstSeen: r.FirstSeen.Local().Format(model.TimeFormat),
+		LastSeen:  r.LastSeen.Local().Format(model.TimeFormat),
+		Duration:  r.LastSeen.Sub(r.FirstSeen).String(),
+	}
+}
+
+// Query 归档查询条件，零值字段不参与过滤；时间范围与事件的 [FirstSeen, LastSeen] 有交集即匹配
+type Query struct {
+	Namespace string
+	Type      string
+	From      time.Time
+	To        time.Time
+}
+
+// Match 判断记录是否满足查询条件
+func (q *Query) Match(r *Record) bool {
+	if q.Namespace != "" && r.Namespace != q.Namespace {
+		return false
+	}
+	if q.Type != "" && r.Type != q.Type {
+		return false
+	}
+	if !q.From.IsZero() && r.LastSeen.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && r.FirstSeen.After(q.To) {
+		return false
+	}
+	return true
+}
+
+// Store 内存中的事件归档，定期以gob格式持久化
+type Store struct {
+	mutex     sync.RWMutex
+	records   map[string]*Record
+	retention time.Duration
+	maxEvents int
+}
+
+// NewStore 创建事件归档存储
+func NewStore(retention time.Duration, maxEvents int) *Store {
+	return &Store{
+		records:   make(map[string]*Record),
+		retention: retention,
+		maxEvents: maxEvents,
+	}
+}
+
+// Upsert 写入或更新事件，已存在时保留较早的 FirstSeen；超出上限时淘汰最旧的事件
+func (s *Store) Upsert(r Record) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if old, exists := s.records[r.UID]; exists {
+		if !old.FirstSeen.IsZero() && old.FirstSeen.Before(r.FirstSeen) {
+			r.FirstSeen = old.FirstSeen
+		}
+		if r.Count < old.Count {
+			r.Count = old.Count
+		}
+		*old = r
+		return
+	}
+	if len(s.records) >= s.maxEvents {
+		s.evictOldestLocked(len(s.records) - s.maxEvents + 1)
+	}
+	s.records[r.UID] = &r
+}
+
+// evictOldestLocked 按 LastSeen 淘汰最旧的 n 条记录
+func (s *Store) evictOldestLocked(n int) {
+	all := make([]*Record, 0, len(s.records))
+	for _, r := range s.records {
+		all = append(all, r)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].LastSeen.Before(all[j].LastSeen) })
+	// 一次多淘汰1%，避免达到上限后每次写入都排序
+	n += s.maxEvents / 100
+	for i := 0; i < n && i < len(all); i++ {
+		delete(s.records, all[i].UID)
+	}
+}
+
+// Prune 删除超过保留时长的事件，返回删除数量
+func (s *Store) Prune(now time.Time) int {
+	cutoff := now.Add(-s.retention)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	removed := 0
+	for uid, r := range s.records {
+		if r.LastSeen.Before(cutoff) {
+			delete(s.records, uid)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len 返回归档事件数量
+func (s *Store) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.records)
+}
+
+// Oldest 返回归档中最早的事件时间，为空时返回零值
+func (s *Store) Oldest() time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var oldest time.Time
+	for _, r := range s.records {
+		if oldest.IsZero() || r.FirstSeen.Before(oldest) {
+			oldest = r.FirstSeen
+		}
+	}
+	return oldest
+}
+
+// Query 返回匹配的事件，按 LastSeen 从新到旧排列
+func (s *Store) Query(q Query) []Record {
+	s.mutex.RLock()
+	result := make([]Record, 0)
+	for _, r := range s.records {
+		if q.Match(r) {
+			result = append(result, *r)
+		}
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool { return result[i].LastSeen.After(result[j].LastSeen) })
+	return result
+}
+
+// Aggregate 按原因、关联对象或命名空间汇总匹配的事件，按累计发生次数降序排列
+func (s *Store) Aggregate(q Query, by string) ([]model.EventAggregate, error) {
+	var keyOf func(r *Record) string
+	switch by {
+	case model.EventGroupByReason:
+		keyOf = func(r *Record) string { return r.Reason }
+	case model.EventGroupByObject:
+		keyOf = func(r *Record) string { return r.Namespace + "/" + r.Object() }
+	case model.EventGroupByNamespace:
+		keyOf = func(r *Record) string { return r.Namespace }
+	default:
+		return nil, fmt.Errorf("不支持的聚合维度: %s", by)
+	}
+
+	type bucket struct {
+		agg         model.EventAggregate
+		first, last time.Time
+	}
+	buckets := make(map[string]*bucket)
+	s.mutex.RLock()
+	for _, r := range s.records {
+		if !q.Match(r) {
+			continue
+		}
+		key := keyOf(r)
+		b, exists := buckets[key]
+		if !exists {
+			b = &bucket{agg: model.EventAggregate{Key: key}, first: r.FirstSeen, last: r.LastSeen}
+			buckets[key] = b
+		}
+		b.agg.Events++
+		b.agg.Occurrences += int64(r.Count)
+		if r.Type == "Warning" {
+			b.agg.Warnings++
+		}
+		if r.FirstSeen.Before(b.first) {
+			b.first = r.FirstSeen
+		}
+		if r.LastSeen.After(b.last) {
+			b.last = r.LastSeen
+		}
+	}
+	s.mutex.RUnlock()
+
+	result := make([]model.EventAggregate, 0, len(buckets))
+	for _, b := range buckets {
+		b.agg.FirstSeen = b.first.Local().Format(model.TimeFormat)
+		b.agg.LastSeen = b.last.Local().Format(model.TimeFormat)
+		result = append(result, b.agg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Occurrences != result[j].Occurrences {
+			return result[i].Occurrences > result[j].Occurrences
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result, nil
+}
+
+// Save 以原子方式将归档写入文件
+func (s *Store) Save(path string) error {
+	s.mutex.RLock()
+	records := make([]Record, 0, len(s.records))
+	for _, r := range s.records {
+		records = append(records, *r)
+	}
+	s.mutex.RUnlock()
+
+	if err := store.EnsureDir(path); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if err := gob.NewEncoder(tmp).Encode(records); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入事件归档失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("关闭临时文件失败: %w", err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("替换文件失败: %w", err)
+	}
+	return nil
+}
+
+// Load 从文件恢复归档，文件不存在时忽略
+func (s *Store) Load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("读取事件归档失败: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	if err := gob.NewDecoder(f).Decode(&records); err != nil {
+		return fmt.Errorf("解析事件归档失败: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i := range records {
+		if len(s.records) >= s.maxEvents {
+			break
+		}
+		s.records[records[i].UID] = &records[i]
+	}
+	return nil
+}
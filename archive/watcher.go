@@ -0,0 +1,202 @@
+package archive
+
+import (
+	"context"
+	"time"
+
+	"github.com/nick0323/K8sVision/model"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// 过期事件清理周期
+const pruneInterval = 10 * time.Minute
+
+// InformerFunc 创建监听全集群事件的Informer
+type InformerFunc func() (cache.SharedIndexInformer, error)
+
+// Watcher 后台监听事件变化并写入归档
+type Watcher struct {
+	store        *Store
+	informer     cache.SharedIndexInformer
+	file         string
+	saveInterval time.Duration
+	logger       *zap.Logger
+	cancel       context.CancelFunc
+	done         chan struct{}
+}
+
+var globalWatcher *Watcher
+
+// Init 初始化并启动全局事件归档
+func Init(cfg *model.EventArchiveConfig, logger *zap.Logger, newInformer InformerFunc) {
+	if !cfg.Enabled {
+		logger.Info("事件归档未启用")
+		return
+	}
+
+	informer, err := newInformer()
+	if err != nil {
+		logger.Error("事件归档初始化失败", zap.Error(err))
+		return
+	}
+
+	st := NewStore(cfg.Retention, cfg.MaxEvents)
+	if cfg.File != "" {
+		if err := st.Load(cfg.File); err != nil {
+			logger.Warn("加载事件归档失败", zap.String("file", cfg.File), zap.Error(err))
+		}
+		st.Prune(time.Now())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Watcher{
+		store:        st,
+		informer:     informer,
+		file:         cfg.File,
+		saveInterval: cfg.SaveInterval,
+		logger:       logger,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+
+	// 重新List时会收到全部现存事件，按UID合并即可去重
+	_, err = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    w.onEvent,
+		UpdateFunc: func(_, obj interface{}) { w.onEvent(obj) },
+	})
+	if err != nil {
+		cancel()
+		logger.Error("事件归档初始化失败", zap.Error(err))
+		return
+	}
+	go informer.Run(ctx.Done())
+	go w.run(ctx)
+
+	globalWatcher = w
+	logger.Info("事件归档已启动",
+		zap.Duration("retention", cfg.Retention),
+		zap.Int("events", st.Len()),
+	)
+}
+
+// Default 返回全局归档存储，未启用时为 nil
+func Default() *Store {
+	if globalWatcher == nil {
+		return nil
+	}
+	return globalWatcher.store
+}
+
+// Informers 返回事件Informer的运行状态，未启用时为空
+func Informers() []model.InformerStatus {
+	if globalWatcher == nil {
+		return nil
+	}
+	inf := globalWatcher.informer
+	return []model.InformerStatus{{
+		Resource:        "events",
+		Synced:          inf.HasSynced(),
+		Items:           len(inf.GetStore().ListKeys()),
+		ResourceVersion: inf.LastSyncResourceVersion(),
+	}}
+}
+
+// Close 停止监听并持久化
+func Close() {
+	if globalWatcher != nil {
+		globalWatcher.Close()
+	}
+}
+
+func (w *Watcher) onEvent(obj interface{}) {
+	e, ok := obj.(*corev1.Event)
+	if !ok {
+		return
+	}
+	w.store.Upsert(RecordFromEvent(e))
+}
+
+// run 定期清理过期事件并保存到文件
+func (w *Watcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	pruneTicker := time.NewTicker(pruneInterval)
+	defer pruneTicker.Stop()
+	var saveC <-chan time.Time
+	if w.file != "" {
+		saveTicker := time.NewTicker(w.saveInterval)
+		defer saveTicker.Stop()
+		saveC = saveTicker.C
+	}
+
+	for {
+		select {
+		case <-pruneTicker.C:
+			if removed := w.store.Prune(time.Now()); removed > 0 {
+				w.logger.Debug("清理过期归档事件", zap.Int("removed", removed))
+			}
+		case <-saveC:
+			w.save()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *Watcher) save() {
+	if err := w.store.Save(w.file); err != nil {
+		w.logger.Error("保存事件归档失败", zap.String("file", w.file), zap.Error(err))
+	}
+}
+
+// Close 停止监听并持久化
+func (w *Watcher) Close() {
+	w.cancel()
+	<-w.done
+	if w.file != "" {
+		w.save()
+	}
+	w.logger.Info("事件归档已关闭", zap.Int("events", w.store.Len()))
+}
+
+// RecordFromEvent 转换事件，兼容只填写 EventTime/Series 的新版事件上报方式
+func RecordFromEvent(e *corev1.Event) Record {
+	first, last := e.FirstTimestamp.Time, e.LastTimestamp.Time
+	count := e.Count
+	if e.Series != nil {
+		count = e.Series.Count
+		last = e.Series.LastObservedTime.Time
+	}
+	for _, t := range []time.Time{e.EventTime.Time, e.CreationTimestamp.Time} {
+		if first.IsZero() {
+			first = t
+		}
+		if last.IsZero() {
+			last = t
+		}
+	}
+	if count == 0 {
+		count = 1
+	}
+
+	source := e.Source.Component
+	if source == "" {
+		source = e.ReportingController
+	}
+	return Record{
+		UID:        string(e.UID),
+		Namespace:  e.Namespace,
+		Name:       e.Name,
+		ObjectKind: e.InvolvedObject.Kind,
+		ObjectName: e.InvolvedObject.Name,
+		Reason:     e.Reason,
+		Message:    e.Message,
+		Type:       e.Type,
+		Source:     source,
+		Count:      count,
+		FirstSeen:  first,
+		LastSeen:   last,
+	}
+}
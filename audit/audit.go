@@ -0,0 +1,176 @@
+// Package audit 提供结构化、可查询的用户操作审计记录
+package audit
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nick0323/K8sVision/model"
+	"go.uber.org/zap"
+)
+
+// Recorder 审计记录器，写入追加式存储并同步输出结构化日志
+type Recorder struct {
+	store  *FileStore
+	logger *zap.Logger
+}
+
+var globalRecorder *Recorder
+
+// NewRecorder 创建审计记录器
+func NewRecorder(cfg *model.AuditConfig, logger *zap.Logger) (*Recorder, error) {
+	fs, err := NewFileStore(cfg.File, cfg.MaxSize, cfg.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{store: fs, logger: logger}, nil
+}
+
+// Init 初始化全局审计记录器；未启用时审计事件仅输出到日志
+func Init(cfg *model.AuditConfig, logger *zap.Logger) {
+	if !cfg.Enabled {
+		logger.Info("审计存储未启用，审计事件仅输出到日志")
+		return
+	}
+	recorder, err := NewRecorder(cfg, logger)
+	if err != nil {
+		logger.Error("审计记录器初始化失败", zap.Error(err))
+		return
+	}
+	globalRecorder = recorder
+	logger.Info("审计记录器已启动", zap.String("file", cfg.File))
+}
+
+// Default 返回全局审计记录器，未初始化时为 nil
+func Default() *Recorder {
+	return globalRecorder
+}
+
+// Record 记录审计事件到全局记录器
+func Record(e *Event) {
+	if globalRecorder != nil {
+		globalRecorder.Record(e)
+		return
+	}
+	fillDefaults(e)
+	zap.L().Info("audit", eventFields(e)...)
+}
+
+// Close 关闭全局审计记录器
+func Close() {
+	if globalRecorder != nil {
+		globalRecorder.Close()
+	}
+}
+
+// Record 记录一条审计事件，存储失败时只输出错误日志，不影响业务请求
+func (r *Recorder) Record(e *Event) {
+	fillDefaults(e)
+	r.logger.Info("audit", eventFields(e)...)
+	if err := r.store.Append(e); err != nil {
+		r.logger.Error("写入审计存储失败", zap.String("auditId", e.ID), zap.Error(err))
+	}
+}
+
+// Query 按条件分页查询，结果按时间从新到旧排列。从最新的记录开始流式读取，
+// match 为附加条件（如关键字搜索，可为空），凑满 offset+limit 条或早于 q.From 时停止读取。
+// 读取完整时 total 为匹配总数；提前停止时为 offset+limit+1，表示至少还有一条
+func (r *Recorder) Query(q Query, match func(e *Event) bool, offset, limit int) ([]Event, int, error) {
+	page := make([]Event, 0, limit)
+	matched := 0
+	more := false
+	err := r.store.ScanReverse(func(e *Event) bool {
+		// 事件按写入顺序追加，早于起始时间后不会再有匹配的记录
+		if !q.From.IsZero() && e.Timestamp.Before(q.From) {
+			return false
+		}
+		if !q.Match(e) || (match != nil && !match(e)) {
+			return true
+		}
+		if matched >= offset+limit {
+			more = true
+			return false
+		}
+		if matched >= offset {
+			page = append(page, *e)
+		}
+		matched++
+		return true
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if more {
+		matched++
+	}
+	return page, matched, nil
+}
+
+// Export 以JSON Lines格式按时间顺序导出匹配的事件，返回导出条数
+func (r *Recorder) Export(w io.Writer, q Query) (int, error) {
+	encoder := json.NewEncoder(w)
+	count := 0
+	var writeErr error
+	err := r.store.Scan(func(e *Event) bool {
+		if !q.Match(e) {
+			return true
+		}
+		if writeErr = encoder.Encode(e); writeErr != nil {
+			return false
+		}
+		count++
+		return true
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, writeErr
+}
+
+// Files 返回审计日志文件列表（从新到旧）
+func (r *Recorder) Files() []string {
+	return r.store.Files()
+}
+
+// Close 关闭记录器
+func (r *Recorder) Close() {
+	if err := r.store.Close(); err != nil {
+		r.logger.Warn("关闭审计存储失败", zap.Error(err))
+	}
+}
+
+func fillDefaults(e *Event) {
+	if e.ID == "" {
+		e.ID = newEventID()
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	if e.Outcome == "" {
+		e.Outcome = OutcomeSuccess
+	}
+}
+
+func eventFields(e *Event) []zap.Field {
+	return []zap.Field{
+		zap.String("auditId", e.ID),
+		zap.String("actor", e.Actor),
+		zap.String("action", e.Action),
+		zap.String("resource", e.Resource.String()),
+		zap.String("outcome", e.Outcome),
+		zap.String("clientIP", e.ClientIP),
+		zap.String("traceId", e.TraceID),
+	}
+}
+
+func newEventID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
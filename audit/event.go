@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"strings"
+	"time"
+)
+
+// 审计动作
+const (
+	ActionLogin            = "auth.login"
+	ActionLoginTwoFactor   = "auth.login.2fa"
+	ActionPasswordChange   = "auth.password.change"
+	ActionTokenCreate      = "auth.token.create"
+	ActionTokenRevoke      = "auth.token.revoke"
+	ActionTwoFactorEnable  = "auth.2fa.enable"
+	ActionTwoFactorDisable = "auth.2fa.disable"
+	ActionTwoFactorReset   = "auth.2fa.reset"
+	ActionTwoFactorEnforce = "auth.2fa.enforce"
+	ActionSecretRead       = "secret.read"
+	ActionSecretReveal     = "secret.reveal"
+	ActionConfigMapReveal  = "configmap.reveal"
+	ActionSilenceCreate    = "alert.silence.create"
+	ActionSilenceDelete    = "alert.silence.delete"
+	ActionLogLevelChange   = "runtime.loglevel.change"
+	ActionNodeCordon       = "node.cordon"
+	ActionNodeUncordon     = "node.uncordon"
+	ActionNodeDrain        = "node.drain"
+	ActionNodeDrainCancel  = "node.drain.cancel"
+	ActionMutation         = "api.mutation"
+)
+
+// 审计结果
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeDenied  = "denied"
+)
+
+// Resource 审计事件涉及的资源
+type Resource struct {
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// String 返回 kind/namespace/name 形式的资源描述
+func (r Resource) String() string {
+	parts := make([]string, 0, 3)
+	for _, p := range []string{r.Kind, r.Namespace, r.Name} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// Event 一条审计记录：谁、在何时、从哪里、对什么资源、做了什么、结果如何
+type Event struct {
+	ID         string                 `json:"id"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Actor      string                 `json:"actor"`
+	Role       string                 `json:"role,omitempty"`
+	AuthMethod string                 `json:"authMethod,omitempty"`
+	TokenID    string                 `json:"tokenId,omitempty"`
+	Action     string                 `json:"action"`
+	Resource   Resource               `json:"resource"`
+	Method     string                 `json:"method,omitempty"`
+	Path       string                 `json:"path,omitempty"`
+	StatusCode int                    `json:"statusCode,omitempty"`
+	Outcome    string                 `json:"outcome"`
+	Reason     string                 `json:"reason,omitempty"`
+	ClientIP   string                 `json:"clientIP"`
+	UserAgent  string                 `json:"userAgent,omitempty"`
+	TraceID    string                 `json:"traceId,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (e Event) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Actor":    e.Actor,
+		"Action":   e.Action,
+		"Resource": e.Resource.String(),
+		"Path":     e.Path,
+		"Outcome":  e.Outcome,
+		"Reason":   e.Reason,
+		"ClientIP": e.ClientIP,
+		"TraceID":  e.TraceID,
+	}
+}
+
+// Query 审计日志查询条件，零值字段不参与过滤
+type Query struct {
+	Actor     string
+	Action    string // 支持前缀匹配，如 "auth." 匹配所有认证相关动作
+	Outcome   string
+	Kind      string
+	Namespace string
+	Name      string
+	ClientIP  string
+	TraceID   string
+	From      time.Time
+	To        time.Time
+}
+
+// Match 判断事件是否满足查询条件
+func (q Query) Match(e *Event) bool {
+	if q.Actor != "" && e.Actor != q.Actor {
+		return false
+	}
+	if q.Action != "" && e.Action != q.Action && !(strings.HasSuffix(q.Action, ".") && strings.HasPrefix(e.Action, q.Action)) {
+		return false
+	}
+	if q.Outcome != "" && e.Outcome != q.Outcome {
+		return false
+	}
+	if q.Kind != "" && !strings.EqualFold(e.Resource.Kind, q.Kind) {
+		return false
+	}
+	if q.Namespace != "" && e.Resource.Namespace != q.Namespace {
+		return false
+	}
+	if q.Name != "" && e.Resource.Name != q.Name {
+		return false
+	}
+	if q.ClientIP != "" && e.ClientIP != q.ClientIP {
+		return false
+	}
+	if q.TraceID != "" && e.TraceID != q.TraceID {
+		return false
+	}
+	if !q.From.IsZero() && e.Timestamp.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && e.Timestamp.After(q.To) {
+		return false
+	}
+	return true
+}
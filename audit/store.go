@@ -0,0 +1,288 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/nick0323/K8sVision/store"
+)
+
+// FileStore 基于JSON Lines的追加写审计存储，按文件大小轮转
+// 当前文件为 path，历史文件依次为 path.1（最新）... path.N（最旧）
+type FileStore struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	mutex      sync.Mutex
+}
+
+// NewFileStore 创建文件存储，maxSizeMB 为单个文件的最大大小
+func NewFileStore(path string, maxSizeMB, maxBackups int) (*FileStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("审计日志文件路径不能为空")
+	}
+	if maxSizeMB <= 0 {
+		maxSizeMB = 100
+	}
+	if maxBackups < 0 {
+		maxBackups = 0
+	}
+
+	fs := &FileStore{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+	}
+	if err := fs.open(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// Append 追加一条事件
+func (fs *FileStore) Append(e *Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("序列化审计事件失败: %w", err)
+	}
+	line = append(line, '\n')
+
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	if fs.file == nil {
+		return fmt.Errorf("审计存储已关闭")
+	}
+
+	if fs.size+int64(len(line)) > fs.maxSize && fs.size > 0 {
+		if err := fs.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := fs.file.Write(line)
+	fs.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("写入审计日志失败: %w", err)
+	}
+	return nil
+}
+
+// Scan 按时间从旧到新遍历所有事件（含轮转文件），fn 返回 false 时停止
+func (fs *FileStore) Scan(fn func(e *Event) bool) error {
+	fs.mutex.Lock()
+	files := fs.files()
+	fs.mutex.Unlock()
+
+	for i := len(files) - 1; i >= 0; i-- {
+		cont, err := scanFile(files[i], fn)
+		if err != nil {
+			return err
+		}
+		if !cont {
+			return nil
+		}
+	}
+	return nil
+}
+
+// ScanReverse 按时间从新到旧遍历所有事件（含轮转文件），逐块从文件末尾读取，fn 返回 false 时停止
+func (fs *FileStore) ScanReverse(fn func(e *Event) bool) error {
+	fs.mutex.Lock()
+	files := fs.files()
+	fs.mutex.Unlock()
+
+	for _, file := range files {
+		cont, err := scanFileReverse(file, fn)
+		if err != nil {
+			return err
+		}
+		if !cont {
+			return nil
+		}
+	}
+	return nil
+}
+
+// Files 返回当前及历史文件路径（从新到旧）
+func (fs *FileStore) Files() []string {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	return fs.files()
+}
+
+// Close 关闭存储
+func (fs *FileStore) Close() error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	if fs.file == nil {
+		return nil
+	}
+	err := fs.file.Close()
+	fs.file = nil
+	return err
+}
+
+func (fs *FileStore) open() error {
+	if err := store.EnsureDir(fs.path); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(fs.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("读取审计日志信息失败: %w", err)
+	}
+	fs.file = f
+	fs.size = info.Size()
+	return nil
+}
+
+// rotate 轮转文件，调用方需持有锁
+func (fs *FileStore) rotate() error {
+	if err := fs.file.Close(); err != nil {
+		return fmt.Errorf("关闭审计日志失败: %w", err)
+	}
+	fs.file = nil
+
+	if fs.maxBackups == 0 {
+		if err := os.Remove(fs.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("删除审计日志失败: %w", err)
+		}
+		return fs.open()
+	}
+
+	oldest := backupName(fs.path, fs.maxBackups)
+	if err := os.Remove(oldest); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("删除过期审计日志失败: %w", err)
+	}
+	for i := fs.maxBackups - 1; i >= 1; i-- {
+		src := backupName(fs.path, i)
+		if err := os.Rename(src, backupName(fs.path, i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("轮转审计日志失败: %w", err)
+		}
+	}
+	if err := os.Rename(fs.path, backupName(fs.path, 1)); err != nil {
+		return fmt.Errorf("轮转审计日志失败: %w", err)
+	}
+	return fs.open()
+}
+
+// files 返回存在的日志文件，调用方需持有锁
+func (fs *FileStore) files() []string {
+	files := []string{fs.path}
+	for i := 1; i <= fs.maxBackups; i++ {
+		name := backupName(fs.path, i)
+		if _, err := os.Stat(name); err != nil {
+			break
+		}
+		files = append(files, name)
+	}
+	return files
+}
+
+func backupName(path string, index int) string {
+	return fmt.Sprintf("%s.%d", path, index)
+}
+
+func scanFile(path string, fn func(e *Event) bool) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return true, nil
+		}
+		return false, fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 1 {
+			var e Event
+			// 跳过损坏的行（如写入中断），不影响其余记录
+			if jsonErr := json.Unmarshal(line, &e); jsonErr == nil {
+				if !fn(&e) {
+					return false, nil
+				}
+			}
+		}
+		if err == io.EOF {
+			return true, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("读取审计日志失败: %w", err)
+		}
+	}
+}
+
+// reverseChunkSize 倒序读取时每次读取的块大小
+const reverseChunkSize = 64 * 1024
+
+// scanFileReverse 从文件末尾向前逐行读取，内存占用与单行长度相关而与文件大小无关
+func scanFileReverse(path string, fn func(e *Event) bool) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return true, nil
+		}
+		return false, fmt.Errorf("打开审计日志失败: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false, fmt.Errorf("读取审计日志信息失败: %w", err)
+	}
+
+	emit := func(line []byte) bool {
+		if len(line) == 0 {
+			return true
+		}
+		var e Event
+		// 跳过损坏的行（如写入中断），不影响其余记录
+		if json.Unmarshal(line, &e) != nil {
+			return true
+		}
+		return fn(&e)
+	}
+
+	buf := make([]byte, reverseChunkSize)
+	var carry []byte // 上一块开头不完整的行，属于当前块之后
+	for pos := info.Size(); pos > 0; {
+		n := int64(len(buf))
+		if pos < n {
+			n = pos
+		}
+		pos -= n
+		if _, err := f.ReadAt(buf[:n], pos); err != nil && err != io.EOF {
+			return false, fmt.Errorf("读取审计日志失败: %w", err)
+		}
+		chunk := append(append(make([]byte, 0, int(n)+len(carry)), buf[:n]...), carry...)
+		end := len(chunk)
+		for {
+			i := bytes.LastIndexByte(chunk[:end], '\n')
+			if i < 0 {
+				break
+			}
+			if !emit(chunk[i+1 : end]) {
+				return false, nil
+			}
+			end = i
+		}
+		carry = chunk[:end]
+	}
+	return emit(carry), nil
+}
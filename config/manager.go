@@ -204,6 +204,11 @@ func (m *Manager) applyEnvironmentOverrides() {
 		m.config.JWT.Secret = secret
 	}
 
+	// 指标导出配置
+	if token := os.Getenv("K8SVISION_METRICS_TOKEN"); token != "" {
+		m.config.Metrics.Token = token
+	}
+
 	// 日志配置
 	if level := os.Getenv("K8SVISION_LOG_LEVEL"); level != "" {
 		m.config.Log.Level = level
@@ -301,6 +306,16 @@ func (m *Manager) SetAndWrite(key string, value interface{}) error {
 	return m.WriteConfigWithBackup()
 }
 
+// UpdateAndWrite 同时更新内存配置与viper键值，并写入配置文件（带备份）
+// 写入失败时内存中的配置仍然生效，调用方可据此提示未持久化
+func (m *Manager) UpdateAndWrite(key string, value interface{}, apply func(cfg *model.Config)) error {
+	m.mutex.Lock()
+	m.viper.Set(key, value)
+	apply(m.config)
+	m.mutex.Unlock()
+	return m.WriteConfigWithBackup()
+}
+
 // GetConfigFile 返回当前配置文件路径
 func (m *Manager) GetConfigFile() string {
 	m.mutex.RLock()
@@ -325,6 +340,13 @@ func (m *Manager) GetAuthConfig() *model.AuthConfig {
 	return &m.config.Auth
 }
 
+// GetSensitiveDataConfig 获取敏感数据脱敏配置
+func (m *Manager) GetSensitiveDataConfig() *model.SensitiveDataConfig {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return &m.config.SensitiveData
+}
+
 // UpdateLogger 更新logger实例（避免重复创建配置管理器）
 func (m *Manager) UpdateLogger(newLogger *zap.Logger) {
 	m.mutex.Lock()
@@ -0,0 +1,139 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/nick0323/K8sVision/model"
+	"go.uber.org/zap"
+)
+
+// 维护周期
+const (
+	pruneInterval = time.Hour
+	saveInterval  = 10 * time.Minute
+)
+
+// Collector 采集一次当前资源用量
+type Collector func(ctx context.Context) ([]model.UsageSample, error)
+
+// Sampler 后台定期采样并写入存储
+type Sampler struct {
+	store    *Store
+	collect  Collector
+	interval time.Duration
+	file     string
+	logger   *zap.Logger
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+var globalSampler *Sampler
+
+// Init 初始化并启动全局采样器
+func Init(cfg *model.MetricsHistoryConfig, logger *zap.Logger, collect Collector) {
+	if !cfg.Enabled {
+		logger.Info("资源用量历史未启用")
+		return
+	}
+
+	st := NewStore(cfg.Tiers, cfg.MaxSeries)
+	if cfg.File != "" {
+		if err := st.Load(cfg.File); err != nil {
+			logger.Warn("加载资源用量历史失败", zap.String("file", cfg.File), zap.Error(err))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Sampler{
+		store:    st,
+		collect:  collect,
+		interval: cfg.Interval,
+		file:     cfg.File,
+		logger:   logger,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go s.run(ctx)
+
+	globalSampler = s
+	logger.Info("资源用量采样器已启动",
+		zap.Duration("interval", cfg.Interval),
+		zap.Int("series", st.Len()),
+	)
+}
+
+// Default 返回全局存储，未启用时为 nil
+func Default() *Store {
+	if globalSampler == nil {
+		return nil
+	}
+	return globalSampler.store
+}
+
+// Close 停止采样并持久化
+func Close() {
+	if globalSampler != nil {
+		globalSampler.Close()
+	}
+}
+
+func (s *Sampler) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	lastPrune, lastSave := time.Now(), time.Now()
+
+	s.sample(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			s.sample(ctx)
+
+			now := time.Now()
+			if now.Sub(lastPrune) >= pruneInterval {
+				if removed := s.store.Prune(now); removed > 0 {
+					s.logger.Debug("清理过期用量序列", zap.Int("removed", removed))
+				}
+				lastPrune = now
+			}
+			if s.file != "" && now.Sub(lastSave) >= saveInterval {
+				s.save()
+				lastSave = now
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Sampler) sample(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, s.interval)
+	defer cancel()
+
+	samples, err := s.collect(ctx)
+	if err != nil {
+		s.logger.Warn("采集资源用量失败", zap.Error(err))
+		return
+	}
+	if dropped := s.store.Append(time.Now(), samples); dropped > 0 {
+		s.logger.Warn("时间序列数量已达上限，部分采样被丢弃", zap.Int("dropped", dropped))
+	}
+}
+
+func (s *Sampler) save() {
+	if err := s.store.Save(s.file); err != nil {
+		s.logger.Error("保存资源用量历史失败", zap.String("file", s.file), zap.Error(err))
+	}
+}
+
+// Close 停止采样并持久化
+func (s *Sampler) Close() {
+	s.cancel()
+	<-s.done
+	if s.file != "" {
+		s.save()
+	}
+	s.logger.Info("资源用量采样器已关闭")
+}
@@ -0,0 +1,356 @@
+// Package history 提供节点、Pod、命名空间资源用量的内嵌时间序列存储与后台采样
+package history
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/store"
+)
+
+// MaxQueryPoints 单次查询返回的最大点数，超过时自动放大步长
+const MaxQueryPoints = 1500
+
+// SeriesKey 时间序列标识
+type SeriesKey struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Point 一个时间桶内的平均用量，T 为桶起点（Unix秒），用 float32 压缩内存占用
+type Point struct {
+	T      int64
+	CPU    float32
+	Memory float32
+}
+
+// ring 固定容量的环形缓冲区，按需增长，写满后覆盖最旧的点
+type ring struct {
+	buf      []Point
+	start    int
+	capacity int
+	// openCount 最新一个桶已合并的采样数，用于计算平均值
+	openCount int
+}
+
+func (r *ring) last() *Point {
+	if len(r.buf) == 0 {
+		return nil
+	}
+	return &r.buf[(r.start+len(r.buf)-1)%len(r.buf)]
+}
+
+func (r *ring) push(p Point) {
+	if len(r.buf) < r.capacity {
+		r.buf = append(r.buf, p)
+		return
+	}
+	r.buf[r.start] = p
+	r.start = (r.start + 1) % r.capacity
+}
+
+// points 按时间升序返回所有点
+func (r *ring) points() []Point {
+	result := make([]Point, 0, len(r.buf))
+	result = append(result, r.buf[r.start:]...)
+	return append(result, r.buf[:r.start]...)
+}
+
+// add 将采样合并进所属时间桶
+func (r *ring) add(bucket int64, cpu, memory float64) {
+	if p := r.last(); p != nil && p.T == bucket {
+		n := float64(r.openCount)
+		p.CPU = float32((float64(p.CPU)*n + cpu) / (n + 1))
+		p.Memory = float32((float64(p.Memory)*n + memory) / (n + 1))
+		r.openCount++
+		return
+	}
+	if p := r.last(); p != nil && bucket < p.T {
+		// 时钟回拨时丢弃，保持单调
+		return
+	}
+	r.push(Point{T: bucket, CPU: float32(cpu), Memory: float32(memory)})
+	r.openCount = 1
+}
+
+type series struct {
+	tiers    []*ring
+	lastSeen int64
+}
+
+// Store 多层降采样的内存时间序列存储
+// 每个采样同时写入所有层级，细粒度层保留时间短，粗粒度层保留时间长
+type Store struct {
+	tiers     []model.HistoryTier
+	maxSeries int
+	series    map[SeriesKey]*series
+	mutex     sync.RWMutex
+}
+
+// NewStore 创建存储，tiers 需按 Step 升序
+func NewStore(tiers []model.HistoryTier, maxSeries int) *Store {
+	return &Store{
+		tiers:     tiers,
+		maxSeries: maxSeries,
+		series:    make(map[SeriesKey]*series),
+	}
+}
+
+func (s *Store) newSeries() *series {
+	rings := make([]*ring, len(s.tiers))
+	for i, tier := range s.tiers {
+		rings[i] = &ring{capacity: int(tier.Retention / tier.Step)}
+	}
+	return &series{tiers: rings}
+}
+
+// Append 写入一批采样，返回因超过序列上限而丢弃的数量
+func (s *Store) Append(t time.Time, samples []model.UsageSample) int {
+	ts := t.Unix()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	dropped := 0
+	for _, sample := range samples {
+		key := SeriesKey{Kind: sample.Kind, Namespace: sample.Namespace, Name: sample.Name}
+		ser, exists := s.series[key]
+		if !exists {
+			if len(s.series) >= s.maxSeries {
+				dropped++
+				continue
+			}
+			ser = s.newSeries()
+			s.series[key] = ser
+		}
+		for i, tier := range s.tiers {
+			step := int64(tier.Step / time.Second)
+			ser.tiers[i].add(ts-ts%step, sample.CPU, sample.Memory)
+		}
+		ser.lastSeen = ts
+	}
+	return dropped
+}
+
+// Prune 删除超过最长保留期未更新的序列（如已删除的Pod）
+func (s *Store) Prune(now time.Time) int {
+	if len(s.tiers) == 0 {
+		return 0
+	}
+	cutoff := now.Add(-s.MaxRetention()).Unix()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	removed := 0
+	for key, ser := range s.series {
+		if ser.lastSeen < cutoff {
+			delete(s.series, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len 返回序列数量
+func (s *Store) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.series)
+}
+
+// MaxRetention 返回最长保留时长
+func (s *Store) MaxRetention() time.Duration {
+	if len(s.tiers) == 0 {
+		return 0
+	}
+	return s.tiers[len(s.tiers)-1].Retention
+}
+
+// ErrSeriesNotFound 序列不存在
+var ErrSeriesNotFound = errors.New("时间序列不存在")
+
+// Query 查询 [from, to] 内的用量
+// 选择能覆盖 from 的最细层级；step 小于层级步长时按层级步长返回，点数过多时自动放大步长
+func (s *Store) Query(key SeriesKey, from, to time.Time, step time.Duration, now time.Time) ([]model.UsagePoint, time.Duration, error) {
+	if len(s.tiers) == 0 {
+		return nil, 0, ErrSeriesNotFound
+	}
+
+	tierIndex := len(s.tiers) - 1
+	for i, tier := range s.tiers {
+		if now.Sub(from) <= tier.Retention {
+			tierIndex = i
+			break
+		}
+	}
+	tier := s.tiers[tierIndex]
+
+	if step < tier.Step {
+		step = tier.Step
+	}
+	if minStep := to.Sub(from) / MaxQueryPoints; step < minStep {
+		step = minStep
+	}
+	// 步长对齐为层级步长的整数倍
+	step = (step + tier.Step - 1) / tier.Step * tier.Step
+
+	s.mutex.RLock()
+	ser, exists := s.series[key]
+	var raw []Point
+	if exists {
+		raw = ser.tiers[tierIndex].points()
+	}
+	s.mutex.RUnlock()
+
+	if !exists {
+		return nil, step, ErrSeriesNotFound
+	}
+	return downsample(raw, from.Unix(), to.Unix(), int64(step/time.Second)), step, nil
+}
+
+// downsample 将层级内的点按 step 求平均
+func downsample(raw []Point, from, to, step int64) []model.UsagePoint {
+	type acc struct {
+		cpu, memory float64
+		n           int
+	}
+	buckets := make(map[int64]*acc)
+	for _, p := range raw {
+		if p.T < from-from%step || p.T > to {
+			continue
+		}
+		b := p.T - p.T%step
+		a, exists := buckets[b]
+		if !exists {
+			a = &acc{}
+			buckets[b] = a
+		}
+		a.cpu += float64(p.CPU)
+		a.memory += float64(p.Memory)
+		a.n++
+	}
+
+	result := make([]model.UsagePoint, 0, len(buckets))
+	for t, a := range buckets {
+		result = append(result, model.UsagePoint{
+			Timestamp: t,
+			CPU:       a.cpu / float64(a.n),
+			Memory:    a.memory / float64(a.n),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Timestamp < result[j].Timestamp })
+	return result
+}
+
+// snapshot 持久化格式
+type snapshot struct {
+	Tiers  []model.HistoryTier
+	Series []seriesSnapshot
+}
+
+type seriesSnapshot struct {
+	Key        SeriesKey
+	LastSeen   int64
+	Points     [][]Point
+	OpenCounts []int
+}
+
+// Save 以gob格式原子写入文件
+func (s *Store) Save(path string) error {
+	s.mutex.RLock()
+	snap := snapshot{Tiers: s.tiers, Series: make([]seriesSnapshot, 0, len(s.series))}
+	for key, ser := range s.series {
+		ss := seriesSnapshot{Key: key, LastSeen: ser.lastSeen}
+		for _, r := range ser.tiers {
+			ss.Points = append(ss.Points, r.points())
+			ss.OpenCounts = append(ss.OpenCounts, r.openCount)
+		}
+		snap.Series = append(snap.Series, ss)
+	}
+	s.mutex.RUnlock()
+
+	if err := store.EnsureDir(path); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if err := gob.NewEncoder(tmp).Encode(&snap); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入用量历史失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("关闭临时文件失败: %w", err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("替换文件失败: %w", err)
+	}
+	return nil
+}
+
+// Load 从文件恢复数据，文件不存在时忽略；层级配置变化时丢弃旧数据
+func (s *Store) Load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("读取用量历史失败: %w", err)
+	}
+	defer f.Close()
+
+	var snap snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return fmt.Errorf("解析用量历史失败: %w", err)
+	}
+	if !sameTiers(snap.Tiers, s.tiers) {
+		return fmt.Errorf("降采样层级配置已变化，丢弃历史数据")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, ss := range snap.Series {
+		if len(s.series) >= s.maxSeries {
+			break
+		}
+		ser := s.newSeries()
+		ser.lastSeen = ss.LastSeen
+		for i, r := range ser.tiers {
+			if i >= len(ss.Points) {
+				break
+			}
+			for _, p := range ss.Points[i] {
+				r.push(p)
+			}
+			r.openCount = ss.OpenCounts[i]
+		}
+		s.series[ss.Key] = ser
+	}
+	return nil
+}
+
+func sameTiers(a, b []model.HistoryTier) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -3,15 +3,28 @@
 package main
 
 import (
+	"context"
+	"crypto/subtle"
 	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/nick0323/K8sVision/alert"
 	"github.com/nick0323/K8sVision/api"
 	"github.com/nick0323/K8sVision/api/middleware"
+	"github.com/nick0323/K8sVision/archive"
+	"github.com/nick0323/K8sVision/audit"
 	"github.com/nick0323/K8sVision/cache"
 	"github.com/nick0323/K8sVision/config"
+	"github.com/nick0323/K8sVision/history"
 	"github.com/nick0323/K8sVision/monitor"
 	"github.com/nick0323/K8sVision/service"
+	"github.com/nick0323/K8sVision/version"
 
 	"github.com/nick0323/K8sVision/model"
 
@@ -21,13 +34,14 @@ import (
 )
 
 const (
-	DefaultConfigFile = ""
-	HealthCheckPath   = "/health"
-	CacheStatsPath    = "/cache/stats"
-	APIPrefix         = "/api"
-	LoginPath         = "/api/login"
-	ConsoleFormat     = "console"
-	JSONFormat        = "json"
+	DefaultConfigFile  = ""
+	HealthCheckPath    = "/health"
+	CacheStatsPath     = "/cache/stats"
+	APIPrefix          = "/api"
+	LoginPath          = "/api/login"
+	LoginTwoFactorPath = "/api/login/2fa"
+	ConsoleFormat      = "console"
+	JSONFormat         = "json"
 )
 
 type Application struct {
@@ -36,6 +50,7 @@ type Application struct {
 	configMgr  *config.Manager
 	cacheMgr   *cache.Manager
 	monitorMgr *monitor.Monitor
+	logLevel   zap.AtomicLevel
 }
 
 var (
@@ -48,7 +63,8 @@ var (
 	}
 )
 
-func initLogger(cfg *model.Config) (*zap.Logger, error) {
+// initLogger 创建日志器，同时返回其使用的 AtomicLevel 以便运行时调整级别
+func initLogger(cfg *model.Config) (*zap.Logger, zap.AtomicLevel, error) {
 	var zapConfig zap.Config
 
 	if cfg.IsDevelopment() {
@@ -57,8 +73,9 @@ func initLogger(cfg *model.Config) (*zap.Logger, error) {
 		zapConfig = zap.NewProductionConfig()
 	}
 
+	// 复制一份级别，避免运行时修改影响 logLevelMap 中的共享值
 	if level, exists := logLevelMap[cfg.Log.Level]; exists {
-		zapConfig.Level = level
+		zapConfig.Level = zap.NewAtomicLevelAt(level.Level())
 	} else {
 		zapConfig.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
 	}
@@ -73,7 +90,8 @@ func initLogger(cfg *model.Config) (*zap.Logger, error) {
 	zapConfig.EncoderConfig.TimeKey = "timestamp"
 	zapConfig.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05.000")
 
-	return zapConfig.Build()
+	logger, err := zapConfig.Build()
+	return logger, zapConfig.Level, err
 }
 
 func NewApplication(configFile string) *Application {
@@ -93,12 +111,13 @@ func (app *Application) Initialize() error {
 	cfg := app.configMgr.GetConfig()
 
 	var err error
-	app.logger, err = initLogger(cfg)
+	app.logger, app.logLevel, err = initLogger(cfg)
 	if err != nil {
 		tempLogger.Fatal("初始化日志失败", zap.Error(err))
 	}
 
 	app.configMgr.UpdateLogger(app.logger)
+	zap.ReplaceGlobals(app.logger)
 	app.cacheMgr = cache.NewManager(&cfg.Cache, app.logger)
 	app.monitorMgr = monitor.NewMonitor(app.logger)
 
@@ -106,9 +125,17 @@ func (app *Application) Initialize() error {
 	service.SetCacheManager(app.cacheMgr)
 	api.SetConfigManager(app.configMgr)
 	api.InitAuthManager(app.logger)
+	api.InitTokenManager(app.logger)
+	api.InitTwoFactorManager(app.logger)
+	audit.Init(&cfg.Audit, app.logger)
 
-	monitor.InitTracing(app.logger)
-	monitor.InitBusinessMetrics(app.logger)
+	if err := monitor.InitTracing(&cfg.Tracing, app.logger); err != nil {
+		return fmt.Errorf("初始化链路追踪失败: %w", err)
+	}
+	monitor.InitBusinessMetrics(app.logger, service.CountResources)
+	history.Init(&cfg.MetricsHistory, app.logger, service.CollectUsage)
+	archive.Init(&cfg.EventArchive, app.logger, service.NewEventInformer)
+	alert.Init(&cfg.Alerting, app.logger, service.CollectClusterSnapshot)
 
 	if err := app.configMgr.Watch(); err != nil {
 		app.logger.Warn("启动配置监听失败", zap.Error(err))
@@ -139,18 +166,25 @@ func (app *Application) registerMiddlewares(r *gin.Engine, cfg *model.Config) {
 	r.Use(middleware.MetricsMiddleware(app.monitorMgr.GetMetrics()))
 
 	if cfg.Auth.EnableRateLimit {
-		r.Use(middleware.ConcurrencyMiddleware(cfg.Auth.RateLimit))
+		r.Use(middleware.ConcurrencyMiddleware(cfg.Auth.RateLimit, api.LivezPath, api.ReadyzPath, HealthCheckPath))
 	}
 }
 
 func (app *Application) registerRoutes(r *gin.Engine, cfg *model.Config) {
 	r.POST(LoginPath, api.LoginHandler(app.logger))
+	r.POST(LoginTwoFactorPath, api.TwoFactorLoginHandler(app.logger))
 
 	r.GET(CacheStatsPath, app.handleCacheStats)
 	r.GET(HealthCheckPath, app.handleHealthCheck)
+	api.RegisterHealth(&r.RouterGroup, app.logger, app.readinessChecks(cfg))
+	if cfg.Metrics.Enabled {
+		r.GET(cfg.Metrics.Path, app.handlePrometheusMetrics)
+	}
 
 	apiGroup := r.Group(APIPrefix)
-	apiGroup.Use(middleware.JWTAuthMiddleware(app.logger, app.configMgr))
+	// 审计中间件置于认证之前，以便记录被拒绝的变更请求
+	apiGroup.Use(middleware.AuditMiddleware())
+	apiGroup.Use(middleware.JWTAuthMiddleware(app.logger, app.configMgr, api.GetTokenValidator()))
 
 	if cfg.Cache.Enabled {
 		apiGroup.Use(middleware.CacheMiddleware(app.cacheMgr, cfg.Cache.TTL))
@@ -167,14 +201,21 @@ func (app *Application) registerAPIRoutes(apiGroup *gin.RouterGroup) {
 	api.RegisterDeployment(apiGroup, app.logger, service.GetK8sClient, service.ListDeployments)
 	api.RegisterStatefulSet(apiGroup, app.logger, service.GetK8sClient, service.ListStatefulSets)
 	api.RegisterDaemonSet(apiGroup, app.logger, service.GetK8sClient, service.ListDaemonSets)
+	api.RegisterReplicaSet(apiGroup, app.logger, service.GetK8sClient, service.ListReplicaSets)
+	api.RegisterHPA(apiGroup, app.logger, service.GetK8sClient, service.ListHPAs)
+	api.RegisterPDB(apiGroup, app.logger, service.GetK8sClient, service.ListPDBs)
+	api.RegisterPriorityClass(apiGroup, app.logger, service.GetK8sClient, service.ListPriorityClasses)
 
 	api.RegisterService(apiGroup, app.logger, service.GetK8sClient, service.ListServices)
 	api.RegisterIngress(apiGroup, app.logger, service.GetK8sClient, service.ListIngresses)
+	api.RegisterNetworkPolicy(apiGroup, app.logger, service.GetK8sClient, service.ListNetworkPolicies)
 
 	api.RegisterCronJob(apiGroup, app.logger, service.GetK8sClient, service.ListCronJobs)
 	api.RegisterJob(apiGroup, app.logger, service.GetK8sClient, service.ListJobs)
 
 	api.RegisterNamespace(apiGroup, app.logger, service.GetK8sClient, service.ListNamespaces)
+	api.RegisterResourceQuota(apiGroup, app.logger, service.GetK8sClient, service.ListResourceQuotas)
+	api.RegisterLimitRange(apiGroup, app.logger, service.GetK8sClient, service.ListLimitRanges)
 	api.RegisterEvent(apiGroup, app.logger, service.GetK8sClient, service.ListEvents)
 
 	api.RegisterPVC(apiGroup, app.logger, service.GetK8sClient, service.ListPVCs)
@@ -184,14 +225,26 @@ func (app *Application) registerAPIRoutes(apiGroup *gin.RouterGroup) {
 	api.RegisterConfigMap(apiGroup, app.logger, service.GetK8sClient, service.ListConfigMaps)
 	api.RegisterSecret(apiGroup, app.logger, service.GetK8sClient, service.ListSecrets)
 
-	api.RegisterPasswordAdmin(apiGroup, app.logger)
-	api.RegisterMetrics(apiGroup, app.logger)
-
+	api.RegisterServiceAccount(apiGroup, app.logger, service.GetK8sClient, service.ListServiceAccounts)
+	api.RegisterRBAC(apiGroup, app.logger, service.GetK8sClient)
+
+	api.RegisterToken(apiGroup, app.logger)
+	api.RegisterTwoFactor(apiGroup, app.logger)
+	api.RegisterMetrics(apiGroup, app.logger, service.GetK8sClient)
+	api.RegisterAlerts(apiGroup, app.logger, service.GetK8sClient)
+
+	adminGroup := apiGroup.Group("")
+	adminGroup.Use(middleware.RequireRole(app.logger, model.RoleAdmin))
+	api.RegisterPasswordAdmin(adminGroup, app.logger)
+	api.RegisterTwoFactorAdmin(adminGroup, app.logger)
+	api.RegisterAudit(adminGroup, app.logger)
+	api.RegisterAlertsAdmin(adminGroup, app.logger)
+	api.RegisterDiagnosticsAdmin(adminGroup, app.logger, app.logLevel, app.collectCacheStats)
 }
 
-func (app *Application) getOverviewHandler() func(limit, offset int) (*model.OverviewStatus, string, error) {
-	return func(limit, offset int) (*model.OverviewStatus, string, error) {
-		clientset, _, err := service.GetK8sClient()
+func (app *Application) getOverviewHandler() func(ctx context.Context, limit, offset int) (*model.OverviewStatus, string, error) {
+	return func(ctx context.Context, limit, offset int) (*model.OverviewStatus, string, error) {
+		clientset, _, err := service.GetK8sClient(ctx)
 		if err != nil {
 			return nil, "k8s client error", err
 		}
@@ -203,32 +256,100 @@ func (app *Application) getOverviewHandler() func(limit, offset int) (*model.Ove
 	}
 }
 
+// collectCacheStats 汇总响应缓存与Kubernetes客户端缓存的统计
+func (app *Application) collectCacheStats() map[string]interface{} {
+	stats := make(map[string]interface{})
+	if app.cacheMgr != nil {
+		stats = app.cacheMgr.GetAllStats()
+	}
+	stats["k8s_clients"] = service.ClientCacheStats()
+	return stats
+}
+
 func (app *Application) handleCacheStats(c *gin.Context) {
 	stats := app.cacheMgr.GetAllStats()
 	c.JSON(200, stats)
 }
 
+// handlePrometheusMetrics 以Prometheus文本格式导出指标，不经过用户认证，可单独配置抓取令牌
+func (app *Application) handlePrometheusMetrics(c *gin.Context) {
+	if token := app.configMgr.GetConfig().Metrics.Token; token != "" {
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="metrics"`)
+			c.String(http.StatusUnauthorized, "unauthorized\n")
+			return
+		}
+	}
+
+	c.Header("Content-Type", monitor.PrometheusContentType)
+	c.Status(http.StatusOK)
+	if err := app.monitorMgr.Registry().WritePrometheus(c.Writer); err != nil {
+		app.logger.Warn("导出Prometheus指标失败", zap.Error(err))
+	}
+}
+
+// handleHealthCheck 兼容旧的 /health，仅表示进程存活；探针请使用 /livez 与 /readyz
 func (app *Application) handleHealthCheck(c *gin.Context) {
+	info := version.Get()
 	c.JSON(200, gin.H{
 		"status":    "healthy",
 		"timestamp": time.Now().Unix(),
-		"version":   "1.0.0",
+		"version":   info.Version,
+		"commit":    info.Commit,
+		"buildDate": info.BuildDate,
 	})
 }
 
+// readinessChecks /readyz 的检查项
+func (app *Application) readinessChecks(cfg *model.Config) []api.ReadinessCheck {
+	return []api.ReadinessCheck{
+		api.ConfigCheck(func() error {
+			return app.configMgr.GetConfig().Validate()
+		}),
+		api.CacheCheck(app.cacheMgr, cfg.Cache.Enabled),
+		api.APIServerCheck(service.GetK8sClient),
+		api.MetricsServerCheck(service.GetK8sClient),
+	}
+}
+
 func (app *Application) Run() error {
 	cfg := app.configMgr.GetConfig()
 	serverAddr := cfg.GetServerAddress()
 
+	info := version.Get()
 	app.logger.Info("服务器启动",
 		zap.String("address", serverAddr),
+		zap.String("version", info.Version),
+		zap.String("commit", info.Commit),
+		zap.String("buildDate", info.BuildDate),
 		zap.Bool("cacheEnabled", cfg.Cache.Enabled),
 		zap.Bool("rateLimitEnabled", cfg.Auth.EnableRateLimit),
 	)
 
 	app.monitorMgr.StartPeriodicLogging(5 * time.Minute)
 	router := app.SetupRouter()
-	return router.Run(serverAddr)
+	server := &http.Server{Addr: serverAddr, Handler: router}
+
+	// 收到退出信号后优雅关闭，确保 Close 中的审计、追踪数据得以刷盘
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	app.logger.Info("收到退出信号，正在关闭服务器")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
 }
 
 func (app *Application) Close() {
@@ -238,6 +359,16 @@ func (app *Application) Close() {
 	if app.cacheMgr != nil {
 		app.cacheMgr.Close()
 	}
+	api.CloseTokenManager()
+	audit.Close()
+	history.Close()
+	archive.Close()
+	alert.Close()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := monitor.ShutdownTracing(shutdownCtx); err != nil && app.logger != nil {
+		app.logger.Warn("关闭链路追踪失败", zap.Error(err))
+	}
 	if app.monitorMgr != nil {
 		app.monitorMgr.Close()
 	}
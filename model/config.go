@@ -2,17 +2,27 @@ package model
 
 import (
 	"fmt"
+	"path"
+	"strings"
 	"time"
 )
 
 // Config 应用配置结构体
 type Config struct {
-	Server     ServerConfig     `mapstructure:"server" json:"server"`
-	Kubernetes KubernetesConfig `mapstructure:"kubernetes" json:"kubernetes"`
-	JWT        JWTConfig        `mapstructure:"jwt" json:"jwt"`
-	Log        LogConfig        `mapstructure:"log" json:"log"`
-	Auth       AuthConfig       `mapstructure:"auth" json:"auth"`
-	Cache      CacheConfig      `mapstructure:"cache" json:"cache"`
+	Server        ServerConfig        `mapstructure:"server" json:"server"`
+	Kubernetes    KubernetesConfig    `mapstructure:"kubernetes" json:"kubernetes"`
+	JWT           JWTConfig           `mapstructure:"jwt" json:"jwt"`
+	Log           LogConfig           `mapstructure:"log" json:"log"`
+	Auth          AuthConfig          `mapstructure:"auth" json:"auth"`
+	Cache         CacheConfig         `mapstructure:"cache" json:"cache"`
+	Audit         AuditConfig         `mapstructure:"audit" json:"audit"`
+	SensitiveData SensitiveDataConfig `mapstructure:"sensitiveData" json:"sensitiveData"`
+	Metrics       MetricsConfig       `mapstructure:"metrics" json:"metrics"`
+	Tracing       TracingConfig       `mapstructure:"tracing" json:"tracing"`
+
+	MetricsHistory MetricsHistoryConfig `mapstructure:"metricsHistory" json:"metricsHistory"`
+	Alerting       AlertingConfig       `mapstructure:"alerting" json:"alerting"`
+	EventArchive   EventArchiveConfig   `mapstructure:"eventArchive" json:"eventArchive"`
 }
 
 // ServerConfig 服务器配置
@@ -38,6 +48,21 @@ type KubernetesConfig struct {
 	KeyFile    string        `mapstructure:"keyFile" json:"keyFile"`
 	Token      string        `mapstructure:"token" json:"token"`
 	APIServer  string        `mapstructure:"apiServer" json:"apiServer"`
+
+	Impersonation ImpersonationConfig `mapstructure:"impersonation" json:"impersonation"`
+
+	// SlowRequestThreshold 单次apiserver调用超过该耗时时记录告警日志，0 表示不记录
+	SlowRequestThreshold time.Duration `mapstructure:"slowRequestThreshold" json:"slowRequestThreshold"`
+}
+
+// ImpersonationConfig 模拟用户身份配置
+// 启用后每个请求以登录用户身份访问apiserver，由集群RBAC决定权限
+// UserPrefix: 模拟用户名前缀；Groups: 所有用户附加的组；RoleGroups: 按K8sVision角色附加的组
+type ImpersonationConfig struct {
+	Enabled    bool                `mapstructure:"enabled" json:"enabled"`
+	UserPrefix string              `mapstructure:"userPrefix" json:"userPrefix"`
+	Groups     []string            `mapstructure:"groups" json:"groups"`
+	RoleGroups map[string][]string `mapstructure:"roleGroups" json:"roleGroups"`
 }
 
 // JWTConfig JWT配置
@@ -61,13 +86,24 @@ type LogConfig struct {
 
 // AuthConfig 认证配置
 type AuthConfig struct {
-	Username        string        `mapstructure:"username" json:"username"`
-	Password        string        `mapstructure:"password" json:"password"`
-	MaxLoginFail    int           `mapstructure:"maxLoginFail" json:"maxLoginFail"`
-	LockDuration    time.Duration `mapstructure:"lockDuration" json:"lockDuration"`
-	SessionTimeout  time.Duration `mapstructure:"sessionTimeout" json:"sessionTimeout"`
-	EnableRateLimit bool          `mapstructure:"enableRateLimit" json:"enableRateLimit"`
-	RateLimit       int           `mapstructure:"rateLimit" json:"rateLimit"`
+	Username        string          `mapstructure:"username" json:"username"`
+	Password        string          `mapstructure:"password" json:"password"`
+	MaxLoginFail    int             `mapstructure:"maxLoginFail" json:"maxLoginFail"`
+	LockDuration    time.Duration   `mapstructure:"lockDuration" json:"lockDuration"`
+	SessionTimeout  time.Duration   `mapstructure:"sessionTimeout" json:"sessionTimeout"`
+	EnableRateLimit bool            `mapstructure:"enableRateLimit" json:"enableRateLimit"`
+	RateLimit       int             `mapstructure:"rateLimit" json:"rateLimit"`
+	TokenFile       string          `mapstructure:"tokenFile" json:"tokenFile"`
+	MaxTokenTTL     time.Duration   `mapstructure:"maxTokenTTL" json:"maxTokenTTL"`
+	TwoFactor       TwoFactorConfig `mapstructure:"twoFactor" json:"twoFactor"`
+}
+
+// TwoFactorConfig 双因素认证配置
+type TwoFactorConfig struct {
+	Enforce      bool          `mapstructure:"enforce" json:"enforce"`
+	Issuer       string        `mapstructure:"issuer" json:"issuer"`
+	File         string        `mapstructure:"file" json:"file"`
+	ChallengeTTL time.Duration `mapstructure:"challengeTTL" json:"challengeTTL"`
 }
 
 // CacheConfig 缓存配置
@@ -79,6 +115,121 @@ type CacheConfig struct {
 	CleanupInterval time.Duration `mapstructure:"cleanupInterval" json:"cleanupInterval"`
 }
 
+// AuditConfig 审计日志配置
+// MaxSize: 单个文件最大大小（MB），超过后轮转
+// MaxBackups: 保留的历史文件数量
+type AuditConfig struct {
+	Enabled    bool   `mapstructure:"enabled" json:"enabled"`
+	File       string `mapstructure:"file" json:"file"`
+	MaxSize    int    `mapstructure:"maxSize" json:"maxSize"`
+	MaxBackups int    `mapstructure:"maxBackups" json:"maxBackups"`
+}
+
+// SensitiveDataConfig 敏感数据脱敏配置
+// RevealRoles: 允许查看明文的角色
+// ConfigMapKeyPatterns: ConfigMap中视为敏感的键名通配符（不区分大小写）
+type SensitiveDataConfig struct {
+	RevealRoles          []string `mapstructure:"revealRoles" json:"revealRoles"`
+	ConfigMapKeyPatterns []string `mapstructure:"configMapKeyPatterns" json:"configMapKeyPatterns"`
+}
+
+// MetricsConfig Prometheus指标导出配置
+// Token 非空时抓取需携带 Authorization: Bearer <token>，与用户登录认证相互独立
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled" json:"enabled"`
+	Path    string `mapstructure:"path" json:"path"`
+	Token   string `mapstructure:"token" json:"-"`
+}
+
+// TracingConfig 链路追踪配置（OpenTelemetry）
+// Exporter: otlp（OTLP/HTTP）、stdout、file 或 none
+// SampleRatio: 新建trace的采样比例，上游已采样的请求始终记录
+type TracingConfig struct {
+	Enabled     bool       `mapstructure:"enabled" json:"enabled"`
+	ServiceName string     `mapstructure:"serviceName" json:"serviceName"`
+	Exporter    string     `mapstructure:"exporter" json:"exporter"`
+	SampleRatio float64    `mapstructure:"sampleRatio" json:"sampleRatio"`
+	File        string     `mapstructure:"file" json:"file"`
+	OTLP        OTLPConfig `mapstructure:"otlp" json:"otlp"`
+}
+
+// OTLPConfig OTLP/HTTP 导出配置，留空的项使用 OTEL_EXPORTER_OTLP_* 环境变量
+// Endpoint: host:port，不含协议和路径；Headers 常用于携带认证信息，不对外输出
+type OTLPConfig struct {
+	Endpoint string            `mapstructure:"endpoint" json:"endpoint"`
+	URLPath  string            `mapstructure:"urlPath" json:"urlPath"`
+	Insecure bool              `mapstructure:"insecure" json:"insecure"`
+	Headers  map[string]string `mapstructure:"headers" json:"-"`
+	Timeout  time.Duration     `mapstructure:"timeout" json:"timeout"`
+}
+
+// MetricsHistoryConfig 节点、Pod、命名空间资源用量历史采样配置
+// Tiers: 降采样层级，按 Step 升序，每层保留 Retention 时长；File 为空时仅保存在内存
+// MaxSeries: 时间序列数量上限，防止Pod频繁重建导致内存无限增长
+type MetricsHistoryConfig struct {
+	Enabled   bool          `mapstructure:"enabled" json:"enabled"`
+	Interval  time.Duration `mapstructure:"interval" json:"interval"`
+	File      string        `mapstructure:"file" json:"file"`
+	MaxSeries int           `mapstructure:"maxSeries" json:"maxSeries"`
+	Tiers     []HistoryTier `mapstructure:"tiers" json:"tiers"`
+}
+
+// HistoryTier 一个降采样层级
+type HistoryTier struct {
+	Step      time.Duration `mapstructure:"step" json:"step"`
+	Retention time.Duration `mapstructure:"retention" json:"retention"`
+}
+
+// EventArchiveConfig 事件归档配置，后台监听事件并保留超过apiserver默认1小时的历史
+// Retention: 按最后发生时间保留的时长；MaxEvents: 归档事件数量上限，超出时淘汰最旧的事件
+type EventArchiveConfig struct {
+	Enabled      bool          `mapstructure:"enabled" json:"enabled"`
+	File         string        `mapstructure:"file" json:"file"`
+	Retention    time.Duration `mapstructure:"retention" json:"retention"`
+	MaxEvents    int           `mapstructure:"maxEvents" json:"maxEvents"`
+	SaveInterval time.Duration `mapstructure:"saveInterval" json:"saveInterval"`
+}
+
+// AlertingConfig 告警规则引擎配置
+// RulesFile: YAML规则文件；RepeatInterval: 持续触发的告警重复通知间隔，0 表示只通知一次
+type AlertingConfig struct {
+	Enabled        bool          `mapstructure:"enabled" json:"enabled"`
+	Interval       time.Duration `mapstructure:"interval" json:"interval"`
+	RulesFile      string        `mapstructure:"rulesFile" json:"rulesFile"`
+	SilenceFile    string        `mapstructure:"silenceFile" json:"silenceFile"`
+	RepeatInterval time.Duration `mapstructure:"repeatInterval" json:"repeatInterval"`
+	SendTimeout    time.Duration `mapstructure:"sendTimeout" json:"sendTimeout"`
+
+	Webhooks []WebhookSinkConfig `mapstructure:"webhooks" json:"webhooks"`
+	Slack    []SlackSinkConfig   `mapstructure:"slack" json:"slack"`
+	Email    []EmailSinkConfig   `mapstructure:"email" json:"email"`
+}
+
+// WebhookSinkConfig 通用Webhook通知，以JSON POST告警
+type WebhookSinkConfig struct {
+	Name    string            `mapstructure:"name" json:"name"`
+	URL     string            `mapstructure:"url" json:"-"`
+	Headers map[string]string `mapstructure:"headers" json:"-"`
+}
+
+// SlackSinkConfig Slack兼容的Incoming Webhook通知（Mattermost、Rocket.Chat等同样适用）
+type SlackSinkConfig struct {
+	Name       string `mapstructure:"name" json:"name"`
+	WebhookURL string `mapstructure:"webhookURL" json:"-"`
+	Channel    string `mapstructure:"channel" json:"channel"`
+}
+
+// EmailSinkConfig SMTP邮件通知，服务器支持时自动使用STARTTLS
+type EmailSinkConfig struct {
+	Name     string   `mapstructure:"name" json:"name"`
+	Host     string   `mapstructure:"host" json:"host"`
+	Port     int      `mapstructure:"port" json:"port"`
+	Username string   `mapstructure:"username" json:"username"`
+	Password string   `mapstructure:"password" json:"-"`
+	From     string   `mapstructure:"from" json:"from"`
+	To       []string `mapstructure:"to" json:"to"`
+}
+
 // DefaultConfig 返回系统默认配置
 // 包含服务器、Kubernetes、JWT、日志、认证和缓存的默认设置
 func DefaultConfig() *Config {
@@ -92,12 +243,21 @@ func DefaultConfig() *Config {
 			MaxHeaderBytes: 1 << 20, // 1MB
 		},
 		Kubernetes: KubernetesConfig{
-			Kubeconfig: "",
-			Context:    "",
-			Timeout:    30 * time.Second,
-			QPS:        100,
-			Burst:      200,
-			Insecure:   true,
+			Kubeconfig:           "",
+			Context:              "",
+			Timeout:              30 * time.Second,
+			QPS:                  100,
+			Burst:                200,
+			Insecure:             true,
+			SlowRequestThreshold: time.Second,
+			Impersonation: ImpersonationConfig{
+				Enabled:    false,
+				UserPrefix: "k8svision:",
+				RoleGroups: map[string][]string{
+					RoleAdmin:  {"k8svision:admins"},
+					RoleViewer: {"k8svision:viewers"},
+				},
+			},
 		},
 		JWT: JWTConfig{
 			Secret:     "k8svision-default-jwt-secret-key-32-chars", // 默认密钥，生产环境请设置环境变量 K8SVISION_JWT_SECRET
@@ -122,6 +282,14 @@ func DefaultConfig() *Config {
 			SessionTimeout:  24 * time.Hour,
 			EnableRateLimit: true,
 			RateLimit:       100,
+			TokenFile:       "data/tokens.json",
+			MaxTokenTTL:     0, // 0 表示不限制令牌有效期上限
+			TwoFactor: TwoFactorConfig{
+				Enforce:      false,
+				Issuer:       "K8sVision",
+				File:         "data/2fa.json",
+				ChallengeTTL: 5 * time.Minute,
+			},
 		},
 		Cache: CacheConfig{
 			Enabled:         true,
@@ -130,6 +298,58 @@ func DefaultConfig() *Config {
 			MaxSize:         1000,
 			CleanupInterval: 10 * time.Minute,
 		},
+		Audit: AuditConfig{
+			Enabled:    true,
+			File:       "data/audit.log",
+			MaxSize:    50,
+			MaxBackups: 5,
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+			Path:    "/metrics",
+		},
+		Tracing: TracingConfig{
+			Enabled:     false,
+			ServiceName: "k8svision",
+			Exporter:    "otlp",
+			SampleRatio: 1.0,
+			File:        "data/traces.jsonl",
+			OTLP: OTLPConfig{
+				Timeout: 10 * time.Second,
+			},
+		},
+		MetricsHistory: MetricsHistoryConfig{
+			Enabled:   true,
+			Interval:  time.Minute,
+			File:      "data/metrics-history.gob",
+			MaxSeries: 5000,
+			Tiers: []HistoryTier{
+				{Step: time.Minute, Retention: 24 * time.Hour},
+				{Step: 15 * time.Minute, Retention: 30 * 24 * time.Hour},
+			},
+		},
+		EventArchive: EventArchiveConfig{
+			Enabled:      true,
+			File:         "data/events.gob",
+			Retention:    7 * 24 * time.Hour,
+			MaxEvents:    100000,
+			SaveInterval: 5 * time.Minute,
+		},
+		Alerting: AlertingConfig{
+			Enabled:        false,
+			Interval:       30 * time.Second,
+			RulesFile:      "alert-rules.yaml",
+			SilenceFile:    "data/silences.json",
+			RepeatInterval: 4 * time.Hour,
+			SendTimeout:    10 * time.Second,
+		},
+		SensitiveData: SensitiveDataConfig{
+			RevealRoles: []string{RoleAdmin},
+			ConfigMapKeyPatterns: []string{
+				"*password*", "*passwd*", "*secret*", "*token*", "*credential*",
+				"*apikey*", "*api_key*", "*api-key*", "*private*key*", "*.pem", "*.key",
+			},
+		},
 	}
 }
 
@@ -173,6 +393,12 @@ func (c *Config) Validate() error {
 	if c.Auth.LockDuration <= 0 {
 		return fmt.Errorf("锁定时间必须大于0")
 	}
+	if c.Auth.MaxTokenTTL < 0 {
+		return fmt.Errorf("API令牌最大有效期不能为负数")
+	}
+	if c.Auth.TwoFactor.ChallengeTTL <= 0 {
+		return fmt.Errorf("双因素认证挑战有效期必须大于0")
+	}
 
 	// 验证日志配置
 	validLogLevels := map[string]bool{
@@ -199,6 +425,124 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// 验证审计配置
+	if c.Audit.Enabled {
+		if c.Audit.File == "" {
+			return fmt.Errorf("审计日志文件路径不能为空")
+		}
+		if c.Audit.MaxSize <= 0 {
+			return fmt.Errorf("审计日志文件大小必须大于0")
+		}
+		if c.Audit.MaxBackups < 0 {
+			return fmt.Errorf("审计日志保留数量不能为负数")
+		}
+	}
+
+	// 验证敏感数据配置
+	for _, pattern := range c.SensitiveData.ConfigMapKeyPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("无效的敏感键名匹配规则: %s", pattern)
+		}
+	}
+
+	// 验证指标导出配置
+	if c.Metrics.Enabled {
+		if !strings.HasPrefix(c.Metrics.Path, "/") {
+			return fmt.Errorf("指标导出路径必须以 / 开头: %s", c.Metrics.Path)
+		}
+		if strings.HasPrefix(c.Metrics.Path, "/api/") {
+			return fmt.Errorf("指标导出路径不能位于 /api 下: %s", c.Metrics.Path)
+		}
+	}
+
+	// 验证链路追踪配置
+	if c.Tracing.Enabled {
+		switch c.Tracing.Exporter {
+		case "otlp", "stdout", "none":
+		case "file":
+			if c.Tracing.File == "" {
+				return fmt.Errorf("追踪文件路径不能为空")
+			}
+		default:
+			return fmt.Errorf("无效的追踪导出器: %s", c.Tracing.Exporter)
+		}
+		if c.Tracing.ServiceName == "" {
+			return fmt.Errorf("追踪服务名不能为空")
+		}
+		if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+			return fmt.Errorf("追踪采样比例必须在0到1之间")
+		}
+	}
+
+	// 验证资源用量历史配置
+	if c.MetricsHistory.Enabled {
+		if c.MetricsHistory.Interval <= 0 {
+			return fmt.Errorf("资源用量采样间隔必须大于0")
+		}
+		if c.MetricsHistory.MaxSeries <= 0 {
+			return fmt.Errorf("资源用量时间序列上限必须大于0")
+		}
+		if len(c.MetricsHistory.Tiers) == 0 {
+			return fmt.Errorf("资源用量历史至少需要一个降采样层级")
+		}
+		for i, tier := range c.MetricsHistory.Tiers {
+			// 存储按秒对齐时间戳，step 必须为整数秒，否则对齐时会除以0
+			if tier.Step < time.Second || tier.Step%time.Second != 0 {
+				return fmt.Errorf("降采样层级的 step 必须为不小于1s的整数秒: %s", tier.Step)
+			}
+			if tier.Retention < tier.Step {
+				return fmt.Errorf("无效的降采样层级: step=%s retention=%s", tier.Step, tier.Retention)
+			}
+			if i > 0 && tier.Step <= c.MetricsHistory.Tiers[i-1].Step {
+				return fmt.Errorf("降采样层级必须按 step 升序排列")
+			}
+		}
+	}
+
+	// 验证事件归档配置
+	if c.EventArchive.Enabled {
+		if c.EventArchive.Retention <= 0 {
+			return fmt.Errorf("事件归档保留时长必须大于0")
+		}
+		if c.EventArchive.MaxEvents <= 0 {
+			return fmt.Errorf("事件归档数量上限必须大于0")
+		}
+		if c.EventArchive.File != "" && c.EventArchive.SaveInterval <= 0 {
+			return fmt.Errorf("事件归档保存间隔必须大于0")
+		}
+	}
+
+	// 验证告警配置
+	if c.Alerting.Enabled {
+		if c.Alerting.Interval <= 0 {
+			return fmt.Errorf("告警评估间隔必须大于0")
+		}
+		if c.Alerting.RulesFile == "" {
+			return fmt.Errorf("告警规则文件路径不能为空")
+		}
+		if c.Alerting.RepeatInterval < 0 {
+			return fmt.Errorf("告警重复通知间隔不能为负数")
+		}
+		if c.Alerting.SendTimeout <= 0 {
+			return fmt.Errorf("告警发送超时必须大于0")
+		}
+		for _, w := range c.Alerting.Webhooks {
+			if w.Name == "" || w.URL == "" {
+				return fmt.Errorf("Webhook通知需要配置 name 和 url")
+			}
+		}
+		for _, sl := range c.Alerting.Slack {
+			if sl.Name == "" || sl.WebhookURL == "" {
+				return fmt.Errorf("Slack通知需要配置 name 和 webhookURL")
+			}
+		}
+		for _, e := range c.Alerting.Email {
+			if e.Name == "" || e.Host == "" || e.Port <= 0 || e.From == "" || len(e.To) == 0 {
+				return fmt.Errorf("邮件通知需要配置 name、host、port、from 和 to")
+			}
+		}
+	}
+
 	// 验证Kubernetes配置
 	if c.Kubernetes.QPS <= 0 {
 		return fmt.Errorf("kubernetes QPS必须大于0")
@@ -209,6 +553,9 @@ func (c *Config) Validate() error {
 	if c.Kubernetes.Timeout <= 0 {
 		return fmt.Errorf("Kubernetes超时时间必须大于0")
 	}
+	if c.Kubernetes.SlowRequestThreshold < 0 {
+		return fmt.Errorf("Kubernetes慢调用阈值不能为负数")
+	}
 
 	return nil
 }
@@ -20,8 +20,13 @@ const (
 	StatusReady        = "Ready"
 	StatusNotReady     = "Not Ready"
 	StatusScaledToZero = "Scaled to zero"
+	StatusNearLimit    = "NearLimit"
+	StatusExhausted    = "Exhausted"
 )
 
+// QuotaNearLimitPercent 配额使用率达到该值时视为即将耗尽
+const QuotaNearLimitPercent = 80.0
+
 const (
 	TimeFormat      = "2006-01-02 15:04:05"
 	TimeFormatShort = "2006-01-02"
@@ -56,6 +61,20 @@ const (
 	CacheKeyPrefixMetrics   = "metrics_"
 )
 
+const (
+	RoleAdmin  = "admin"
+	RoleViewer = "viewer"
+)
+
+const (
+	APITokenPrefix     = "kv_"
+	AuthMethodJWT      = "jwt"
+	AuthMethodAPIToken = "token"
+
+	// TokenScopeTwoFactorSetup 强制2FA时未绑定用户的受限会话，仅可访问绑定接口
+	TokenScopeTwoFactorSetup = "2fa-setup"
+)
+
 const (
 	LogLevelDebug = "debug"
 	LogLevelInfo  = "info"
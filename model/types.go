@@ -1,5 +1,11 @@
 package model
 
+import (
+	"context"
+	"strings"
+	"time"
+)
+
 // 基础结构体 - 用于减少重复字段
 type BaseMetadata struct {
 	Labels      map[string]string `json:"labels"`
@@ -52,10 +58,14 @@ type NodeStatus struct {
 	PodsCapacity int      `json:"podsCapacity"`
 }
 
+// PodStatus Pod 列表项，Ready 为 就绪容器数/容器总数，Restarts 为各容器重启次数之和
 type PodStatus struct {
 	Namespace   string `json:"namespace"`
 	Name        string `json:"name"`
 	Status      string `json:"status"`
+	Ready       string `json:"ready"`
+	Restarts    int32  `json:"restarts"`
+	Age         string `json:"age"`
 	CPUUsage    string `json:"cpuUsage"`
 	MemoryUsage string `json:"memoryUsage"`
 	PodIP       string `json:"podIP"`
@@ -86,6 +96,55 @@ type DaemonSetStatus struct {
 	Status    string `json:"status"`
 }
 
+// ReplicaSetStatus ReplicaSet 列表项，Owner 为 Kind/Name 形式，Revision 来自 deployment.kubernetes.io/revision 注解
+type ReplicaSetStatus struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Owner     string `json:"owner"`
+	Revision  string `json:"revision"`
+	Desired   int32  `json:"desiredReplicas"`
+	Current   int32  `json:"currentReplicas"`
+	Ready     int32  `json:"readyReplicas"`
+	Image     string `json:"image"`
+	Status    string `json:"status"`
+	Age       string `json:"age"`
+}
+
+// HPAStatus HPA 列表项，Target 为 Kind/Name 形式，Metrics 为 "名称: 当前/目标" 形式的摘要
+type HPAStatus struct {
+	Namespace       string   `json:"namespace"`
+	Name            string   `json:"name"`
+	Target          string   `json:"target"`
+	MinReplicas     int32    `json:"minReplicas"`
+	MaxReplicas     int32    `json:"maxReplicas"`
+	CurrentReplicas int32    `json:"currentReplicas"`
+	DesiredReplicas int32    `json:"desiredReplicas"`
+	Metrics         []string `json:"metrics"`
+	Status          string   `json:"status"`
+}
+
+// PDBStatus PodDisruptionBudget 列表项，MinAvailable/MaxUnavailable 未设置时为空
+type PDBStatus struct {
+	Namespace          string `json:"namespace"`
+	Name               string `json:"name"`
+	MinAvailable       string `json:"minAvailable"`
+	MaxUnavailable     string `json:"maxUnavailable"`
+	Selector           string `json:"selector"`
+	CurrentHealthy     int32  `json:"currentHealthy"`
+	DesiredHealthy     int32  `json:"desiredHealthy"`
+	ExpectedPods       int32  `json:"expectedPods"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+	Status             string `json:"status"`
+}
+
+type PriorityClassStatus struct {
+	Name             string `json:"name"`
+	Value            int32  `json:"value"`
+	GlobalDefault    bool   `json:"globalDefault"`
+	PreemptionPolicy string `json:"preemptionPolicy"`
+	Description      string `json:"description"`
+}
+
 type ServiceStatus struct {
 	Namespace string   `json:"namespace"`
 	Name      string   `json:"name"`
@@ -139,6 +198,54 @@ type IngressStatus struct {
 	TargetService []string `json:"targetService"`
 }
 
+// NetworkPolicyStatus NetworkPolicy 列表项，PodSelector 为标签选择器的字符串形式，空表示选中命名空间内全部Pod
+type NetworkPolicyStatus struct {
+	Namespace    string   `json:"namespace"`
+	Name         string   `json:"name"`
+	PodSelector  string   `json:"podSelector"`
+	PolicyTypes  []string `json:"policyTypes"`
+	IngressRules int      `json:"ingressRules"`
+	EgressRules  int      `json:"egressRules"`
+}
+
+// ResourceQuotaStatus ResourceQuota 列表项，MaxUsedPercent 为各资源中最高的配额使用率
+type ResourceQuotaStatus struct {
+	Namespace      string          `json:"namespace"`
+	Name           string          `json:"name"`
+	Resources      []QuotaResource `json:"resources"`
+	MaxUsedPercent float64         `json:"maxUsedPercent"`
+	Status         string          `json:"status"`
+}
+
+// QuotaResource 单项资源的配额上限与已用量，Quota 仅在命名空间汇总中填写，表示来源配额
+type QuotaResource struct {
+	Quota       string  `json:"quota,omitempty"`
+	Resource    string  `json:"resource"`
+	Hard        string  `json:"hard"`
+	Used        string  `json:"used"`
+	UsedPercent float64 `json:"usedPercent"`
+}
+
+// LimitRangeStatus LimitRange 列表项，Types 为其约束的对象类型（Container、Pod、PersistentVolumeClaim）
+type LimitRangeStatus struct {
+	Namespace string           `json:"namespace"`
+	Name      string           `json:"name"`
+	Types     []string         `json:"types"`
+	Limits    []LimitRangeItem `json:"limits"`
+}
+
+// LimitRangeItem 单项资源的限制范围与默认值，LimitRange 仅在命名空间汇总中填写
+type LimitRangeItem struct {
+	LimitRange           string `json:"limitRange,omitempty"`
+	Type                 string `json:"type"`
+	Resource             string `json:"resource"`
+	Min                  string `json:"min"`
+	Max                  string `json:"max"`
+	Default              string `json:"default"`
+	DefaultRequest       string `json:"defaultRequest"`
+	MaxLimitRequestRatio string `json:"maxLimitRequestRatio"`
+}
+
 // 存储资源状态结构体
 
 type PVCStatus struct {
@@ -169,6 +276,37 @@ type StorageClassStatus struct {
 	IsDefault         bool   `json:"isDefault"`
 }
 
+// 权限资源状态结构体
+
+type ServiceAccountStatus struct {
+	Namespace        string `json:"namespace"`
+	Name             string `json:"name"`
+	Secrets          int    `json:"secrets"`
+	ImagePullSecrets int    `json:"imagePullSecrets"`
+	Age              string `json:"age"`
+}
+
+// RoleStatus Role 与 ClusterRole 共用的列表项，ClusterRole 的 Namespace 为空
+type RoleStatus struct {
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Rules      int    `json:"rules"`
+	Aggregated bool   `json:"aggregated"`
+	Age        string `json:"age"`
+}
+
+// RoleBindingStatus RoleBinding 与 ClusterRoleBinding 共用的列表项
+// RoleRef 为 Kind/Name 形式，Subjects 为 Kind:名称 形式，ServiceAccount 为 ServiceAccount:namespace/name
+type RoleBindingStatus struct {
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`
+	RoleRef   string   `json:"roleRef"`
+	Subjects  []string `json:"subjects"`
+	Age       string   `json:"age"`
+}
+
 // 配置资源状态结构体
 
 type ConfigMapStatus struct {
@@ -246,25 +384,251 @@ type NodeMetrics struct {
 }
 type NodeMetricsMap map[string]NodeMetrics
 
+// 资源用量历史的对象类型
+const (
+	UsageKindNode      = "node"
+	UsageKindPod       = "pod"
+	UsageKindNamespace = "namespace"
+)
+
+// UsageSample 一次资源用量采样
+// CPU: 毫核；Memory: 字节；Namespace 仅 Pod 有值
+type UsageSample struct {
+	Kind      string
+	Namespace string
+	Name      string
+	CPU       float64
+	Memory    float64
+}
+
+// UsagePoint 资源用量时间序列中的一个点，Timestamp 为时间桶起点（Unix秒）
+type UsagePoint struct {
+	Timestamp int64   `json:"timestamp"`
+	CPU       float64 `json:"cpu"`
+	Memory    float64 `json:"memory"`
+}
+
+// InformerStatus 后台Informer的运行状态
+type InformerStatus struct {
+	Resource        string `json:"resource"`
+	Synced          bool   `json:"synced"`
+	Items           int    `json:"items"`
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// 时间线条目类型
+const (
+	TimelineEvent    = "event"
+	TimelineRestart  = "restart"
+	TimelineRevision = "revision"
+	TimelineStatus   = "status"
+)
+
+// TimelineEntry 工作负载时间线中的一条记录，Object 为 Kind/Name 形式
+type TimelineEntry struct {
+	Time      string `json:"time"`
+	Timestamp int64  `json:"timestamp"`
+	Type      string `json:"type"`
+	Severity  string `json:"severity"`
+	Object    string `json:"object"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Count     int32  `json:"count,omitempty"`
+}
+
+// WorkloadTimeline 工作负载及其下属ReplicaSet、Job、Pod的事件、重启、版本和状态变化，按时间升序排列
+type WorkloadTimeline struct {
+	Kind      string          `json:"kind"`
+	Namespace string          `json:"namespace"`
+	Name      string          `json:"name"`
+	Objects   []string        `json:"objects"`
+	Entries   []TimelineEntry `json:"entries"`
+}
+
+// 事件聚合维度
+const (
+	EventGroupByReason    = "reason"
+	EventGroupByObject    = "object"
+	EventGroupByNamespace = "namespace"
+)
+
+// EventAggregate 按原因、对象或命名空间汇总的归档事件统计
+// Events 为去重后的事件条数，Occurrences 为各事件 Count 之和
+type EventAggregate struct {
+	Key         string `json:"key"`
+	Events      int    `json:"events"`
+	Occurrences int64  `json:"occurrences"`
+	Warnings    int    `json:"warnings"`
+	FirstSeen   string `json:"firstSeen"`
+	LastSeen    string `json:"lastSeen"`
+}
+
+// ClusterSnapshot 告警规则评估使用的集群状态快照
+type ClusterSnapshot struct {
+	Time        time.Time
+	Deployments []DeploymentStatus
+	Nodes       []NodeStatus
+	Pods        []PodRestartStatus
+	PVCs        []PVCStatus
+	Events      []SnapshotEvent
+}
+
+// SnapshotEvent 告警评估使用的事件，LastSeen 兼容只设置 EventTime/Series 的事件
+type SnapshotEvent struct {
+	Namespace string
+	Name      string
+	Reason    string
+	Message   string
+	Type      string
+	LastSeen  time.Time
+}
+
+// PodRestartStatus Pod 所有容器的重启次数之和
+type PodRestartStatus struct {
+	Namespace string
+	Name      string
+	Restarts  int32
+}
+
+// UsageHistory 资源用量历史查询结果，CPU 单位为毫核，Memory 单位为字节
+type UsageHistory struct {
+	Kind      string       `json:"kind"`
+	Namespace string       `json:"namespace,omitempty"`
+	Name      string       `json:"name"`
+	From      int64        `json:"from"`
+	To        int64        `json:"to"`
+	Step      string       `json:"step"`
+	Points    []UsagePoint `json:"points"`
+}
+
 // PodDetail 提供给前端的 Pod 详情结构体
 // 可根据实际需求补充字段
+// PodDetail Pod 详情，Owners 为从直接控制器到顶层工作负载的 Kind/Name 链
 type PodDetail struct {
 	CommonResourceFields
-	PodIP      string   `json:"podIP"`
-	NodeName   string   `json:"nodeName"`
-	StartTime  string   `json:"startTime"`
-	Containers []string `json:"containers"`
+	PodIP               string              `json:"podIP"`
+	HostIP              string              `json:"hostIP"`
+	NodeName            string              `json:"nodeName"`
+	StartTime           string              `json:"startTime"`
+	Age                 string              `json:"age"`
+	Ready               string              `json:"ready"`
+	Restarts            int32               `json:"restarts"`
+	QOSClass            string              `json:"qosClass"`
+	ServiceAccount      string              `json:"serviceAccount"`
+	PriorityClass       string              `json:"priorityClass"`
+	Owners              []string            `json:"owners"`
+	Conditions          []ResourceCondition `json:"conditions"`
+	Tolerations         []string            `json:"tolerations"`
+	InitContainers      []ContainerDetail   `json:"initContainers"`
+	Containers          []ContainerDetail   `json:"containers"`
+	EphemeralContainers []ContainerDetail   `json:"ephemeralContainers"`
+}
+
+// 容器状态
+const (
+	ContainerWaiting    = "Waiting"
+	ContainerRunning    = "Running"
+	ContainerTerminated = "Terminated"
+)
+
+// ContainerDetail 容器规格与运行状态，探针为 kubectl describe 风格的描述，未配置时为空
+type ContainerDetail struct {
+	Name            string                `json:"name"`
+	Image           string                `json:"image"`
+	ImageID         string                `json:"imageID"`
+	State           string                `json:"state"`
+	Reason          string                `json:"reason,omitempty"`
+	Message         string                `json:"message,omitempty"`
+	ExitCode        *int32                `json:"exitCode,omitempty"`
+	StartedAt       string                `json:"startedAt,omitempty"`
+	Ready           bool                  `json:"ready"`
+	RestartCount    int32                 `json:"restartCount"`
+	LastTermination *ContainerTermination `json:"lastTermination,omitempty"`
+	Requests        map[string]string     `json:"requests"`
+	Limits          map[string]string     `json:"limits"`
+	Ports           []string              `json:"ports"`
+	LivenessProbe   string                `json:"livenessProbe,omitempty"`
+	ReadinessProbe  string                `json:"readinessProbe,omitempty"`
+	StartupProbe    string                `json:"startupProbe,omitempty"`
+	Mounts          []ContainerMount      `json:"mounts"`
+}
+
+// ContainerTermination 容器上一次终止的信息
+type ContainerTermination struct {
+	Reason     string `json:"reason"`
+	Message    string `json:"message,omitempty"`
+	ExitCode   int32  `json:"exitCode"`
+	StartedAt  string `json:"startedAt"`
+	FinishedAt string `json:"finishedAt"`
+}
+
+type ContainerMount struct {
+	Volume    string `json:"volume"`
+	MountPath string `json:"mountPath"`
+	SubPath   string `json:"subPath,omitempty"`
+	ReadOnly  bool   `json:"readOnly"`
 }
 
 // NodeDetail 提供给前端的 Node 详情结构体
+// Pods 为节点上未结束的Pod，Allocated 为其请求与限制之和及占可分配资源的比例
 type NodeDetail struct {
-	CommonResourceFields          // 统一使用通用字段，避免重复
-	IP                   string   `json:"ip"`
-	CPUUsage             float64  `json:"cpuUsage"`
-	MemoryUsage          float64  `json:"memoryUsage"`
-	Role                 []string `json:"role"`
-	PodsUsed             int      `json:"podsUsed"`
-	PodsCapacity         int      `json:"podsCapacity"`
+	CommonResourceFields                     // 统一使用通用字段，避免重复
+	IP                   string              `json:"ip"`
+	CPUUsage             float64             `json:"cpuUsage"`
+	MemoryUsage          float64             `json:"memoryUsage"`
+	Role                 []string            `json:"role"`
+	PodsUsed             int                 `json:"podsUsed"`
+	PodsCapacity         int                 `json:"podsCapacity"`
+	CreatedAt            string              `json:"createdAt"`
+	Age                  string              `json:"age"`
+	Unschedulable        bool                `json:"unschedulable"`
+	Addresses            []NodeAddress       `json:"addresses"`
+	Conditions           []ResourceCondition `json:"conditions"`
+	Taints               []string            `json:"taints"`
+	Capacity             map[string]string   `json:"capacity"`
+	Allocatable          map[string]string   `json:"allocatable"`
+	SystemInfo           NodeSystemInfo      `json:"systemInfo"`
+	Allocated            NodeAllocation      `json:"allocated"`
+	Pods                 []NodePod           `json:"pods"`
+}
+
+type NodeAddress struct {
+	Type    string `json:"type"`
+	Address string `json:"address"`
+}
+
+type NodeSystemInfo struct {
+	KubeletVersion          string `json:"kubeletVersion"`
+	KubeProxyVersion        string `json:"kubeProxyVersion,omitempty"`
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion"`
+	OSImage                 string `json:"osImage"`
+	KernelVersion           string `json:"kernelVersion"`
+	OperatingSystem         string `json:"operatingSystem"`
+	Architecture            string `json:"architecture"`
+}
+
+// NodeAllocation 节点上Pod的资源承诺，CPU 单位为毫核，内存单位为字节，百分比相对于可分配资源
+type NodeAllocation struct {
+	CPURequests           int64   `json:"cpuRequests"`
+	CPULimits             int64   `json:"cpuLimits"`
+	MemoryRequests        int64   `json:"memoryRequests"`
+	MemoryLimits          int64   `json:"memoryLimits"`
+	CPURequestsPercent    float64 `json:"cpuRequestsPercent"`
+	CPULimitsPercent      float64 `json:"cpuLimitsPercent"`
+	MemoryRequestsPercent float64 `json:"memoryRequestsPercent"`
+	MemoryLimitsPercent   float64 `json:"memoryLimitsPercent"`
+}
+
+// NodePod 节点上的Pod及其有效请求与限制（含初始化容器与开销），单位同 NodeAllocation
+type NodePod struct {
+	Namespace      string `json:"namespace"`
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	Age            string `json:"age"`
+	CPURequests    int64  `json:"cpuRequests"`
+	CPULimits      int64  `json:"cpuLimits"`
+	MemoryRequests int64  `json:"memoryRequests"`
+	MemoryLimits   int64  `json:"memoryLimits"`
 }
 
 // ServiceDetail 提供给前端的 Service 详情结构体
@@ -274,13 +638,72 @@ type ServiceDetail struct {
 	ClusterIP string            `json:"clusterIP"`
 	Ports     []string          `json:"ports"`
 	Selector  map[string]string `json:"selector"`
+	// 以下为后端诊断信息
+	PortDetails       []ServicePort       `json:"portDetails"`
+	EndpointSlices    []EndpointSliceInfo `json:"endpointSlices"`
+	ReadyEndpoints    int                 `json:"readyEndpoints"`
+	NotReadyEndpoints int                 `json:"notReadyEndpoints"`
+	MatchedPods       int                 `json:"matchedPods"`
+	ReadyPods         int                 `json:"readyPods"`
+	Diagnostics       []ServiceDiagnostic `json:"diagnostics"`
+}
+
+// ServicePort Service 端口，TargetPort 为端口号或容器的命名端口
+type ServicePort struct {
+	Name       string `json:"name"`
+	Protocol   string `json:"protocol"`
+	Port       int32  `json:"port"`
+	TargetPort string `json:"targetPort"`
+	NodePort   int32  `json:"nodePort,omitempty"`
+}
+
+// EndpointSliceInfo 属于 Service 的 EndpointSlice，Ports 为 name:port/protocol 形式
+type EndpointSliceInfo struct {
+	Name        string            `json:"name"`
+	AddressType string            `json:"addressType"`
+	Ports       []string          `json:"ports"`
+	Endpoints   []EndpointAddress `json:"endpoints"`
+	Ready       int               `json:"ready"`
+	NotReady    int               `json:"notReady"`
+}
+
+// EndpointAddress EndpointSlice 中的单个端点，TargetPod 为其对应的Pod名称
+type EndpointAddress struct {
+	Addresses   []string `json:"addresses"`
+	Ready       bool     `json:"ready"`
+	Serving     bool     `json:"serving"`
+	Terminating bool     `json:"terminating"`
+	TargetPod   string   `json:"targetPod,omitempty"`
+	NodeName    string   `json:"nodeName,omitempty"`
+	Zone        string   `json:"zone,omitempty"`
+}
+
+// ServiceDiagnostic Service/Ingress 后端诊断项，Level 为 Error 或 Warning
+type ServiceDiagnostic struct {
+	Level   string `json:"level"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ServiceEndpointIssue 没有就绪端点的 Service 及其诊断结果
+type ServiceEndpointIssue struct {
+	Namespace         string              `json:"namespace"`
+	Name              string              `json:"name"`
+	Type              string              `json:"type"`
+	Selector          string              `json:"selector"`
+	MatchedPods       int                 `json:"matchedPods"`
+	ReadyPods         int                 `json:"readyPods"`
+	NotReadyEndpoints int                 `json:"notReadyEndpoints"`
+	Diagnostics       []ServiceDiagnostic `json:"diagnostics"`
 }
 
 // 工作负载资源详情结构体
+// DeploymentDetail Deployment 详情，HPA 为以其为伸缩目标的 HPA，不存在时为 nil
 type DeploymentDetail struct {
 	WorkloadCommonFields
-	Replicas int32  `json:"replicas"`
-	Strategy string `json:"strategy"`
+	Replicas int32      `json:"replicas"`
+	Strategy string     `json:"strategy"`
+	HPA      *HPAStatus `json:"hpa"`
 }
 
 type StatefulSetDetail struct {
@@ -293,6 +716,180 @@ type DaemonSetDetail struct {
 	WorkloadCommonFields
 }
 
+// ResourceCondition 资源的一条状态条件
+type ResourceCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason"`
+	Message            string `json:"message"`
+	LastTransitionTime string `json:"lastTransitionTime"`
+}
+
+// ReplicaSetDetail ReplicaSet 详情，Pods 为当前由其管理的 Pod 名称
+type ReplicaSetDetail struct {
+	WorkloadCommonFields
+	Owner      string              `json:"owner"`
+	Revision   string              `json:"revision"`
+	Current    int32               `json:"current"`
+	Ready      int32               `json:"ready"`
+	CreatedAt  string              `json:"createdAt"`
+	Pods       []string            `json:"pods"`
+	Conditions []ResourceCondition `json:"conditions"`
+}
+
+// HPAMetric HPA 的一项伸缩指标，Current 为空表示尚未采集到
+// Type: Resource、ContainerResource、Pods、Object 或 External
+type HPAMetric struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Target  string `json:"target"`
+	Current string `json:"current"`
+}
+
+// HPAScaleTarget HPA 伸缩目标的当前状态，Found 为 false 表示目标不存在或类型暂不支持查询
+type HPAScaleTarget struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	APIVersion string `json:"apiVersion"`
+	Found      bool   `json:"found"`
+	NotFound   bool   `json:"notFound,omitempty"` // 目标对象不存在（区别于无权限等其他查询失败）
+	Error      string `json:"error,omitempty"`    // 查询失败原因
+	Replicas   int32  `json:"replicas"`
+	Ready      int32  `json:"ready"`
+	Status     string `json:"status"`
+}
+
+// HPADetail HPA 详情
+type HPADetail struct {
+	CommonResourceFields
+	MinReplicas     int32               `json:"minReplicas"`
+	MaxReplicas     int32               `json:"maxReplicas"`
+	CurrentReplicas int32               `json:"currentReplicas"`
+	DesiredReplicas int32               `json:"desiredReplicas"`
+	LastScaleTime   string              `json:"lastScaleTime"`
+	Metrics         []HPAMetric         `json:"metrics"`
+	Conditions      []ResourceCondition `json:"conditions"`
+	ScaleTarget     HPAScaleTarget      `json:"scaleTarget"`
+}
+
+// PDBDetail PodDisruptionBudget 详情，Pods 为当前选中的Pod
+type PDBDetail struct {
+	CommonResourceFields
+	MinAvailable               string              `json:"minAvailable"`
+	MaxUnavailable             string              `json:"maxUnavailable"`
+	Selector                   string              `json:"selector"`
+	UnhealthyPodEvictionPolicy string              `json:"unhealthyPodEvictionPolicy"`
+	CurrentHealthy             int32               `json:"currentHealthy"`
+	DesiredHealthy             int32               `json:"desiredHealthy"`
+	ExpectedPods               int32               `json:"expectedPods"`
+	DisruptionsAllowed         int32               `json:"disruptionsAllowed"`
+	Pods                       []string            `json:"pods"`
+	Conditions                 []ResourceCondition `json:"conditions"`
+}
+
+// PriorityClassDetail PriorityClass 详情，PodCount 为使用该优先级的Pod数量
+type PriorityClassDetail struct {
+	CommonResourceFields
+	Value            int32  `json:"value"`
+	GlobalDefault    bool   `json:"globalDefault"`
+	PreemptionPolicy string `json:"preemptionPolicy"`
+	Description      string `json:"description"`
+	PodCount         int    `json:"podCount"`
+}
+
+// 驱逐预览中Pod的处理方式
+const (
+	DrainActionEvict   = "Evict"   // 通过 Eviction API 驱逐
+	DrainActionDelete  = "Delete"  // 已结束的Pod，直接删除
+	DrainActionSkip    = "Skip"    // DaemonSet 或静态Pod，drain 时跳过
+	DrainActionBlocked = "Blocked" // PDB 当前不允许中断，驱逐会被拒绝
+)
+
+// DrainPod 驱逐预览中的单个Pod，Owner 为 Kind/Name 形式，Flags 为 kubectl drain 处理该Pod所需的参数
+type DrainPod struct {
+	Namespace     string   `json:"namespace"`
+	Name          string   `json:"name"`
+	Phase         string   `json:"phase"`
+	Owner         string   `json:"owner"`
+	PriorityClass string   `json:"priorityClass"`
+	Priority      int32    `json:"priority"`
+	DaemonSet     bool     `json:"daemonSet"`
+	Mirror        bool     `json:"mirror"`
+	LocalStorage  bool     `json:"localStorage"`
+	Unmanaged     bool     `json:"unmanaged"`
+	PDBs          []string `json:"pdbs"`
+	PDBBlocked    bool     `json:"pdbBlocked"`
+	Action        string   `json:"action"`
+	Flags         []string `json:"flags"`
+	Reasons       []string `json:"reasons"`
+}
+
+// DrainPreview 节点驱逐预览，CanDrain 表示没有被 PDB 阻塞的Pod，RequiredFlags 为汇总的 kubectl drain 参数
+type DrainPreview struct {
+	Node          string     `json:"node"`
+	Unschedulable bool       `json:"unschedulable"`
+	TotalPods     int        `json:"totalPods"`
+	EvictPods     int        `json:"evictPods"`
+	SkipPods      int        `json:"skipPods"`
+	BlockedPods   int        `json:"blockedPods"`
+	CanDrain      bool       `json:"canDrain"`
+	RequiredFlags []string   `json:"requiredFlags"`
+	Pods          []DrainPod `json:"pods"`
+}
+
+// DrainOptions 节点驱逐参数，GracePeriodSeconds 为空时使用Pod自身的优雅终止时间
+type DrainOptions struct {
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+	TimeoutSeconds     int    `json:"timeoutSeconds"`
+	IgnoreDaemonSets   bool   `json:"ignoreDaemonSets"`
+	DeleteEmptyDirData bool   `json:"deleteEmptyDirData"`
+	Force              bool   `json:"force"`
+}
+
+// 驱逐任务状态
+const (
+	DrainJobRunning   = "Running"
+	DrainJobSucceeded = "Succeeded"
+	DrainJobFailed    = "Failed"
+	DrainJobCancelled = "Cancelled"
+)
+
+// 驱逐任务中单个Pod的状态
+const (
+	DrainPodPending  = "Pending"  // 等待驱逐，PDB 不允许中断时会重试
+	DrainPodEvicting = "Evicting" // 驱逐已被接受，等待Pod删除
+	DrainPodEvicted  = "Evicted"
+	DrainPodFailed   = "Failed"
+	DrainPodSkipped  = "Skipped"
+)
+
+// DrainJobPod 驱逐任务中的Pod进度，Attempts 为发起驱逐的次数
+type DrainJobPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+	Message   string `json:"message,omitempty"`
+}
+
+// DrainJob 后台节点驱逐任务
+type DrainJob struct {
+	ID         string        `json:"id"`
+	Node       string        `json:"node"`
+	Status     string        `json:"status"`
+	Message    string        `json:"message,omitempty"`
+	Options    DrainOptions  `json:"options"`
+	CreatedBy  string        `json:"createdBy"`
+	StartedAt  string        `json:"startedAt"`
+	FinishedAt string        `json:"finishedAt,omitempty"`
+	Total      int           `json:"total"`
+	Evicted    int           `json:"evicted"`
+	Pending    int           `json:"pending"`
+	Failed     int           `json:"failed"`
+	Skipped    int           `json:"skipped"`
+	Pods       []DrainJobPod `json:"pods"`
+}
+
 type JobDetail struct {
 	CommonResourceFields
 	Completions    int32  `json:"completions"`
@@ -313,21 +910,168 @@ type CronJobDetail struct {
 }
 
 // 网络资源详情结构体
+// Rules/DefaultBackend 中的每个后端都会检查 Service、端口及就绪端点，TLS 中解析 secret 的证书，问题汇总在 Diagnostics 中
 type IngressDetail struct {
 	CommonResourceFields
-	Hosts         []string `json:"hosts"`
-	Address       string   `json:"address"`
-	Ports         []string `json:"ports"`
-	Class         string   `json:"class"`
-	Path          []string `json:"path"`
-	TargetService []string `json:"targetService"`
+	Hosts          []string            `json:"hosts"`
+	Address        string              `json:"address"`
+	Ports          []string            `json:"ports"`
+	Class          string              `json:"class"`
+	Path           []string            `json:"path"`
+	TargetService  []string            `json:"targetService"`
+	Age            string              `json:"age"`
+	Rules          []IngressRule       `json:"rules"`
+	DefaultBackend *IngressBackend     `json:"defaultBackend,omitempty"`
+	TLS            []IngressTLS        `json:"tls"`
+	Diagnostics    []ServiceDiagnostic `json:"diagnostics"`
+}
+
+// IngressRule Host 为空表示匹配所有主机
+type IngressRule struct {
+	Host  string        `json:"host"`
+	Paths []IngressPath `json:"paths"`
+}
+
+type IngressPath struct {
+	Path     string         `json:"path"`
+	PathType string         `json:"pathType"`
+	Backend  IngressBackend `json:"backend"`
+}
+
+// IngressBackend Ingress 后端，Service 后端填写 Service/Port，资源后端填写 Resource（Kind/Name）且不做检查
+type IngressBackend struct {
+	Service        string `json:"service,omitempty"`
+	Port           string `json:"port,omitempty"`
+	Resource       string `json:"resource,omitempty"`
+	Status         string `json:"status"`
+	ServiceExists  bool   `json:"serviceExists"`
+	PortExists     bool   `json:"portExists"`
+	ReadyEndpoints int    `json:"readyEndpoints"`
+	Message        string `json:"message,omitempty"`
+}
+
+// IngressTLS Certificate 为 secret 中的叶子证书，无法读取或解析时 Error 说明原因
+type IngressTLS struct {
+	Hosts       []string        `json:"hosts"`
+	SecretName  string          `json:"secretName"`
+	Certificate *TLSCertificate `json:"certificate,omitempty"`
+	ChainLength int             `json:"chainLength"`
+	Error       string          `json:"error,omitempty"`
+}
+
+type TLSCertificate struct {
+	Subject       string   `json:"subject"`
+	Issuer        string   `json:"issuer"`
+	DNSNames      []string `json:"dnsNames"`
+	IPAddresses   []string `json:"ipAddresses"`
+	NotBefore     string   `json:"notBefore"`
+	NotAfter      string   `json:"notAfter"`
+	DaysRemaining int      `json:"daysRemaining"`
+	Expired       bool     `json:"expired"`
+}
+
+// NetworkPolicyPeer 网络策略规则中的一个对端，Description 为便于阅读的汇总
+// PodSelector/NamespaceSelector 为空字符串表示未设置，"<all>" 表示选中全部
+type NetworkPolicyPeer struct {
+	PodSelector       string   `json:"podSelector,omitempty"`
+	NamespaceSelector string   `json:"namespaceSelector,omitempty"`
+	IPBlock           string   `json:"ipBlock,omitempty"`
+	Except            []string `json:"except,omitempty"`
+	Description       string   `json:"description"`
+}
+
+// NetworkPolicyRule 一条入站或出站规则，Peers 为空表示允许任意对端，Ports 为空表示允许全部端口
+// Policy 仅在生效策略分析结果中填写，表示规则来源
+type NetworkPolicyRule struct {
+	Policy string              `json:"policy,omitempty"`
+	Peers  []NetworkPolicyPeer `json:"peers"`
+	Ports  []string            `json:"ports"`
+}
+
+// NetworkPolicyDetail NetworkPolicy 详情，SelectedPods 为当前被选中的Pod
+type NetworkPolicyDetail struct {
+	CommonResourceFields
+	PodSelector  string              `json:"podSelector"`
+	PolicyTypes  []string            `json:"policyTypes"`
+	Ingress      []NetworkPolicyRule `json:"ingress"`
+	Egress       []NetworkPolicyRule `json:"egress"`
+	SelectedPods []string            `json:"selectedPods"`
+}
+
+// PodNetworkPolicies Pod 的生效网络策略
+// 未被任何策略隔离的方向允许全部流量；已隔离的方向只允许 Ingress/Egress 中列出的规则（各规则之间为并集）
+type PodNetworkPolicies struct {
+	Namespace       string              `json:"namespace"`
+	Pod             string              `json:"pod"`
+	Policies        []string            `json:"policies"`
+	IngressIsolated bool                `json:"ingressIsolated"`
+	EgressIsolated  bool                `json:"egressIsolated"`
+	Ingress         []NetworkPolicyRule `json:"ingress"`
+	Egress          []NetworkPolicyRule `json:"egress"`
+}
+
+// ReachabilityVerdict 单个方向的判定结果，Policies 为允许该流量的策略
+type ReachabilityVerdict struct {
+	Allowed  bool     `json:"allowed"`
+	Isolated bool     `json:"isolated"`
+	Policies []string `json:"policies"`
+	Reason   string   `json:"reason"`
+}
+
+// ReachabilityResult Pod 间连通性分析结果，需源Pod出站与目标Pod入站同时允许
+// Port 为请求的端口（数字或目标Pod的命名端口），ResolvedPort 为解析后的端口号
+type ReachabilityResult struct {
+	Source       string              `json:"source"`
+	Destination  string              `json:"destination"`
+	Port         string              `json:"port"`
+	ResolvedPort int32               `json:"resolvedPort"`
+	Protocol     string              `json:"protocol"`
+	Allowed      bool                `json:"allowed"`
+	Egress       ReachabilityVerdict `json:"egress"`
+	Ingress      ReachabilityVerdict `json:"ingress"`
 }
 
 // 其他资源详情结构体
+// Summary 仅在详情接口中返回
 type NamespaceDetail struct {
 	Name   string `json:"name"`
 	Status string `json:"status"`
 	BaseMetadata
+	Summary *NamespaceSummary `json:"summary,omitempty"`
+}
+
+// NamespaceSummary 命名空间资源汇总
+// CPU 单位为毫核，Memory 单位为字节；请求与限制只统计未结束的Pod，Usage 来自 metrics-server
+// Quotas 按使用率降序排列；Warnings 记录因权限或组件缺失而未能获取的部分
+type NamespaceSummary struct {
+	CreatedAt        string           `json:"createdAt"`
+	PodCount         int              `json:"podCount"`
+	RunningPods      int              `json:"runningPods"`
+	ServiceCount     int              `json:"serviceCount"`
+	PVCCount         int              `json:"pvcCount"`
+	CPURequests      int64            `json:"cpuRequests"`
+	CPULimits        int64            `json:"cpuLimits"`
+	CPUUsage         int64            `json:"cpuUsage"`
+	MemoryRequests   int64            `json:"memoryRequests"`
+	MemoryLimits     int64            `json:"memoryLimits"`
+	MemoryUsage      int64            `json:"memoryUsage"`
+	MetricsAvailable bool             `json:"metricsAvailable"`
+	Quotas           []QuotaResource  `json:"quotas"`
+	QuotaMaxPercent  float64          `json:"quotaMaxPercent"`
+	LimitRanges      []LimitRangeItem `json:"limitRanges"`
+	Warnings         []string         `json:"warnings"`
+}
+
+type ResourceQuotaDetail struct {
+	CommonResourceFields
+	Scopes         []string        `json:"scopes"`
+	Resources      []QuotaResource `json:"resources"`
+	MaxUsedPercent float64         `json:"maxUsedPercent"`
+}
+
+type LimitRangeDetail struct {
+	CommonResourceFields
+	Limits []LimitRangeItem `json:"limits"`
 }
 
 type EventDetail struct {
@@ -372,20 +1116,130 @@ type StorageClassDetail struct {
 	BaseMetadata
 }
 
+// 权限资源详情结构体
+
+// PolicyRule RBAC 规则
+type PolicyRule struct {
+	APIGroups       []string `json:"apiGroups"`
+	Resources       []string `json:"resources"`
+	ResourceNames   []string `json:"resourceNames"`
+	Verbs           []string `json:"verbs"`
+	NonResourceURLs []string `json:"nonResourceURLs"`
+}
+
+// RBACSubject 绑定的主体，Kind 为 User、Group 或 ServiceAccount
+type RBACSubject struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ServiceAccountDetail ServiceAccount 详情，Bindings 为引用它的绑定（Kind/namespace/name 形式）
+type ServiceAccountDetail struct {
+	CommonResourceFields
+	Secrets                      []string `json:"secrets"`
+	ImagePullSecrets             []string `json:"imagePullSecrets"`
+	AutomountServiceAccountToken bool     `json:"automountServiceAccountToken"`
+	Bindings                     []string `json:"bindings"`
+}
+
+// RoleDetail Role 与 ClusterRole 详情，AggregationSelectors 仅聚合型 ClusterRole 有值，Bindings 为引用它的绑定
+type RoleDetail struct {
+	CommonResourceFields
+	Kind                 string       `json:"kind"`
+	Rules                []PolicyRule `json:"rules"`
+	AggregationSelectors []string     `json:"aggregationSelectors"`
+	Bindings             []string     `json:"bindings"`
+}
+
+// RoleBindingDetail RoleBinding 与 ClusterRoleBinding 详情，Rules 为所引用角色的规则，角色不存在时 RoleFound 为 false
+type RoleBindingDetail struct {
+	CommonResourceFields
+	Kind      string        `json:"kind"`
+	RoleKind  string        `json:"roleKind"`
+	RoleName  string        `json:"roleName"`
+	RoleFound bool          `json:"roleFound"`
+	Subjects  []RBACSubject `json:"subjects"`
+	Rules     []PolicyRule  `json:"rules"`
+}
+
+// SubjectRule 主体通过某个绑定获得的一条规则，Via 为 绑定 -> 角色 形式的来源说明
+type SubjectRule struct {
+	PolicyRule
+	Binding string `json:"binding"`
+	Role    string `json:"role"`
+	Scope   string `json:"scope"`
+	Via     string `json:"via"`
+}
+
+// AccessReviewResult SubjectAccessReview 的确认结果，Error 非空表示确认请求本身失败（如无创建权限）
+type AccessReviewResult struct {
+	Allowed bool   `json:"allowed"`
+	Denied  bool   `json:"denied"`
+	Reason  string `json:"reason"`
+	Error   string `json:"error,omitempty"`
+}
+
+// SubjectPermissions 主体在命名空间中的权限汇总（what-can），Namespace 为空表示仅统计集群范围的绑定
+// Groups 为参与匹配的组，包括请求中指定的组和系统隐含的组
+type SubjectPermissions struct {
+	Subject   RBACSubject         `json:"subject"`
+	Namespace string              `json:"namespace"`
+	Groups    []string            `json:"groups"`
+	Rules     []SubjectRule       `json:"rules"`
+	Review    *AccessReviewResult `json:"review,omitempty"`
+}
+
+// WhoCanSubject 拥有指定权限的主体，Via 为授予权限的 绑定 -> 角色 列表
+type WhoCanSubject struct {
+	RBACSubject
+	Via    []string            `json:"via"`
+	Review *AccessReviewResult `json:"review,omitempty"`
+}
+
+// WhoCanResult who-can 查询结果，Truncated 表示确认请求数超过上限，部分主体未确认
+type WhoCanResult struct {
+	Verb         string          `json:"verb"`
+	Resource     string          `json:"resource"`
+	APIGroup     string          `json:"apiGroup"`
+	ResourceName string          `json:"resourceName"`
+	Namespace    string          `json:"namespace"`
+	Subjects     []WhoCanSubject `json:"subjects"`
+	Truncated    bool            `json:"truncated"`
+}
+
 // 配置资源详情结构体
+// Data 仅包含非敏感键的值，敏感键只在 Entries 中给出长度
 type ConfigMapDetail struct {
 	CommonResourceFields
 	DataCount int               `json:"dataCount"`
 	Keys      []string          `json:"keys"`
 	Data      map[string]string `json:"data"`
+	Entries   []DataEntry       `json:"entries"`
 }
 
+// SecretDetail Secret详情，所有值默认脱敏，需通过 reveal 接口单独获取
 type SecretDetail struct {
 	CommonResourceFields
-	Type      string            `json:"type"`
-	DataCount int               `json:"dataCount"`
-	Keys      []string          `json:"keys"`
-	Data      map[string]string `json:"data"`
+	Type      string      `json:"type"`
+	DataCount int         `json:"dataCount"`
+	Keys      []string    `json:"keys"`
+	Entries   []DataEntry `json:"entries"`
+}
+
+// DataEntry 配置数据条目，Size 为值的字节长度
+type DataEntry struct {
+	Key    string `json:"key"`
+	Size   int    `json:"size"`
+	Masked bool   `json:"masked"`
+}
+
+// RevealedValue 单个键的明文值
+type RevealedValue struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+	Value     string `json:"value"`
 }
 
 // LoginRequest 登录参数
@@ -394,6 +1248,131 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
+// AuthIdentity 认证通过后的调用方身份
+// Method: 认证方式（jwt 或 token）
+// TokenID: 使用API令牌认证时的令牌ID
+type AuthIdentity struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	Method   string `json:"method"`
+	TokenID  string `json:"tokenId,omitempty"`
+}
+
+type identityContextKey struct{}
+
+// ContextWithIdentity 将认证身份写入请求上下文，供服务层按用户身份访问集群
+func ContextWithIdentity(ctx context.Context, identity *AuthIdentity) context.Context {
+	return context.WithValue(ctx, identityContextKey{}, identity)
+}
+
+type traceIDContextKey struct{}
+
+// ContextWithTraceID 将请求的追踪ID写入上下文，便于服务层日志关联请求
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDContextKey{}, traceID)
+}
+
+// TraceIDFromContext 从上下文获取追踪ID，不存在时返回空字符串
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(traceIDContextKey{}).(string)
+	return traceID
+}
+
+// IdentityFromContext 从上下文获取认证身份，后台任务等无用户上下文时返回 nil
+func IdentityFromContext(ctx context.Context) *AuthIdentity {
+	if ctx == nil {
+		return nil
+	}
+	identity, _ := ctx.Value(identityContextKey{}).(*AuthIdentity)
+	return identity
+}
+
+// APIToken 持久化的API令牌记录，仅保存令牌哈希
+type APIToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Owner      string     `json:"owner"`
+	Role       string     `json:"role"`
+	Hash       string     `json:"hash"`
+	Prefix     string     `json:"prefix"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIP string     `json:"lastUsedIP,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// APITokenInfo 返回给前端的API令牌信息（不含哈希）
+type APITokenInfo struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Owner      string `json:"owner"`
+	Role       string `json:"role"`
+	Prefix     string `json:"prefix"`
+	CreatedAt  string `json:"createdAt"`
+	ExpiresAt  string `json:"expiresAt"`
+	LastUsedAt string `json:"lastUsedAt"`
+	LastUsedIP string `json:"lastUsedIP"`
+	Status     string `json:"status"`
+}
+
+// APITokenCreateRequest 创建API令牌参数
+// ExpiresIn: 有效期（如 "720h"），为空表示永不过期
+type APITokenCreateRequest struct {
+	Name      string `json:"name" binding:"required"`
+	Role      string `json:"role"`
+	ExpiresIn string `json:"expiresIn"`
+}
+
+// APITokenCreateResponse 创建API令牌的响应，明文令牌仅返回一次
+type APITokenCreateResponse struct {
+	APITokenInfo
+	Token string `json:"token"`
+}
+
+// TwoFactorRecord 持久化的用户双因素认证记录
+// RecoveryCodes: 恢复码的SHA-256哈希，使用后移除
+// LastStep: 最近一次成功使用的TOTP时间步，用于防止验证码重放
+type TwoFactorRecord struct {
+	Username      string     `json:"username"`
+	Secret        string     `json:"secret,omitempty"`
+	PendingSecret string     `json:"pendingSecret,omitempty"`
+	Enabled       bool       `json:"enabled"`
+	EnabledAt     *time.Time `json:"enabledAt,omitempty"`
+	RecoveryCodes []string   `json:"recoveryCodes,omitempty"`
+	LastStep      int64      `json:"lastStep"`
+}
+
+// TwoFactorStatus 返回给前端的双因素认证状态
+type TwoFactorStatus struct {
+	Username          string `json:"username"`
+	Enabled           bool   `json:"enabled"`
+	Enforced          bool   `json:"enforced"`
+	Pending           bool   `json:"pending"`
+	EnabledAt         string `json:"enabledAt"`
+	RecoveryCodesLeft int    `json:"recoveryCodesLeft"`
+}
+
+// TwoFactorSetupResponse 开始TOTP绑定时返回的密钥和二维码URI
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// TwoFactorCodeRequest 携带TOTP验证码或恢复码的请求
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TwoFactorLoginRequest 登录第二步参数
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
 // 为各种状态结构体实现SearchableItem接口
 
 // GetSearchableFields 实现SearchableItem接口
@@ -434,6 +1413,108 @@ func (d DaemonSetStatus) GetSearchableFields() map[string]string {
 	}
 }
 
+// GetSearchableFields 实现SearchableItem接口
+func (r ReplicaSetStatus) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Name":      r.Name,
+		"Namespace": r.Namespace,
+		"Owner":     r.Owner,
+		"Image":     r.Image,
+		"Status":    r.Status,
+	}
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (h HPAStatus) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Name":      h.Name,
+		"Namespace": h.Namespace,
+		"Target":    h.Target,
+		"Status":    h.Status,
+	}
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (n NetworkPolicyStatus) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Name":        n.Name,
+		"Namespace":   n.Namespace,
+		"PodSelector": n.PodSelector,
+	}
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (q ResourceQuotaStatus) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Name":      q.Name,
+		"Namespace": q.Namespace,
+		"Status":    q.Status,
+	}
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (l LimitRangeStatus) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Name":      l.Name,
+		"Namespace": l.Namespace,
+	}
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (s ServiceAccountStatus) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Name":      s.Name,
+		"Namespace": s.Namespace,
+	}
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (r RoleStatus) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Name":      r.Name,
+		"Namespace": r.Namespace,
+	}
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (b RoleBindingStatus) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Name":      b.Name,
+		"Namespace": b.Namespace,
+		"RoleRef":   b.RoleRef,
+		"Subjects":  strings.Join(b.Subjects, ","),
+	}
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (p PDBStatus) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Name":      p.Name,
+		"Namespace": p.Namespace,
+		"Selector":  p.Selector,
+		"Status":    p.Status,
+	}
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (p PriorityClassStatus) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Name":             p.Name,
+		"PreemptionPolicy": p.PreemptionPolicy,
+		"Description":      p.Description,
+	}
+}
+
+// GetSearchableFields 实现SearchableItem接口
+func (j DrainJob) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"ID":        j.ID,
+		"Node":      j.Node,
+		"Status":    j.Status,
+		"CreatedBy": j.CreatedBy,
+	}
+}
+
 // GetSearchableFields 实现SearchableItem接口
 func (s ServiceStatus) GetSearchableFields() map[string]string {
 	return map[string]string{
@@ -444,6 +1525,21 @@ func (s ServiceStatus) GetSearchableFields() map[string]string {
 	}
 }
 
+// GetSearchableFields 实现SearchableItem接口
+func (s ServiceEndpointIssue) GetSearchableFields() map[string]string {
+	codes := make([]string, 0, len(s.Diagnostics))
+	for _, d := range s.Diagnostics {
+		codes = append(codes, d.Code)
+	}
+	return map[string]string{
+		"Name":        s.Name,
+		"Namespace":   s.Namespace,
+		"Type":        s.Type,
+		"Selector":    s.Selector,
+		"Diagnostics": strings.Join(codes, ","),
+	}
+}
+
 // GetSearchableFields 实现SearchableItem接口
 func (n NodeStatus) GetSearchableFields() map[string]string {
 	return map[string]string{
@@ -454,6 +1550,12 @@ func (n NodeStatus) GetSearchableFields() map[string]string {
 }
 
 // GetSearchableFields 实现SearchableItem接口
+func (e EventAggregate) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Key": e.Key,
+	}
+}
+
 func (e EventStatus) GetSearchableFields() map[string]string {
 	return map[string]string{
 		"Name":      e.Name,
@@ -551,6 +1653,16 @@ func (s SecretStatus) GetSearchableFields() map[string]string {
 	}
 }
 
+// GetSearchableFields 实现SearchableItem接口
+func (t APITokenInfo) GetSearchableFields() map[string]string {
+	return map[string]string{
+		"Name":   t.Name,
+		"Owner":  t.Owner,
+		"Role":   t.Role,
+		"Status": t.Status,
+	}
+}
+
 // GetSearchableFields 实现SearchableItem接口
 func (n NamespaceDetail) GetSearchableFields() map[string]string {
 	return map[string]string{
@@ -1,6 +1,9 @@
 package monitor
 
 import (
+	"context"
+	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -108,13 +111,28 @@ func (bmc *BusinessMetricsCollector) generateKey(metric BusinessMetric) string {
 
 // 具体的业务指标收集器
 
-// K8sResourceCollector K8s资源指标收集器
+// ResourceCounter 统计集群资源数量，返回 资源类型 -> 命名空间 -> 数量
+type ResourceCounter func(ctx context.Context) (map[string]map[string]int64, error)
+
+const (
+	resourceCollectInterval = time.Minute
+	resourceCollectTimeout  = 15 * time.Second
+)
+
+// K8sResourceCollector K8s资源指标收集器，按固定间隔刷新，避免每次抓取都访问apiserver
+// 刷新在锁外进行，同时到达的抓取直接返回上一次的结果，不会排队等待apiserver
 type K8sResourceCollector struct {
-	name string
+	name        string
+	counter     ResourceCounter
+	logger      *zap.Logger
+	cached      []BusinessMetric
+	lastCollect time.Time
+	refreshing  bool
+	mutex       sync.Mutex
 }
 
-func NewK8sResourceCollector() *K8sResourceCollector {
-	return &K8sResourceCollector{name: "k8s_resources"}
+func NewK8sResourceCollector(counter ResourceCounter, logger *zap.Logger) *K8sResourceCollector {
+	return &K8sResourceCollector{name: "k8s_resources", counter: counter, logger: logger}
 }
 
 func (krc *K8sResourceCollector) GetName() string {
@@ -122,33 +140,70 @@ func (krc *K8sResourceCollector) GetName() string {
 }
 
 func (krc *K8sResourceCollector) Collect() []BusinessMetric {
-	// 这里应该从K8s API收集实际的资源指标
-	// 目前返回模拟数据
-	return []BusinessMetric{
-		{
-			Name:       "k8s_pods_total",
-			Value:      100,
-			Timestamp:  time.Now(),
-			Labels:     map[string]string{"namespace": "default"},
-			MetricType: "gauge",
-		},
-		{
-			Name:       "k8s_deployments_total",
-			Value:      20,
-			Timestamp:  time.Now(),
-			Labels:     map[string]string{"namespace": "default"},
-			MetricType: "gauge",
-		},
+	krc.mutex.Lock()
+	if krc.counter == nil || krc.refreshing || time.Since(krc.lastCollect) < resourceCollectInterval {
+		cached := krc.cached
+		krc.mutex.Unlock()
+		return cached
+	}
+	krc.lastCollect = time.Now()
+	krc.refreshing = true
+	cached := krc.cached
+	krc.mutex.Unlock()
+
+	metrics, err := krc.snapshot()
+
+	krc.mutex.Lock()
+	defer krc.mutex.Unlock()
+	krc.refreshing = false
+	if err != nil {
+		// 保留上一次结果，集群暂时不可达时不影响其他指标
+		krc.logger.Warn("收集K8s资源指标失败", zap.Error(err))
+		return cached
+	}
+	krc.cached = metrics
+	return metrics
+}
+
+// snapshot 访问apiserver统计资源数量，调用时不持有锁
+func (krc *K8sResourceCollector) snapshot() ([]BusinessMetric, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), resourceCollectTimeout)
+	defer cancel()
+
+	counts, err := krc.counter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	metrics := make([]BusinessMetric, 0)
+	for kind, byNamespace := range counts {
+		var total int64
+		for namespace, count := range byNamespace {
+			total += count
+			metrics = append(metrics, BusinessMetric{
+				Name:       "k8s_" + kind + "_total",
+				Value:      float64(count),
+				Timestamp:  now,
+				Labels:     map[string]string{"namespace": namespace},
+				MetricType: "gauge",
+			})
+		}
+		if globalMonitor != nil {
+			globalMonitor.GetMetrics().RecordResourceCount(kind, total)
+		}
 	}
+	return metrics, nil
 }
 
-// APIMetricsCollector API指标收集器
+// APIMetricsCollector API指标收集器，数据来自请求中间件的按路由统计
 type APIMetricsCollector struct {
-	name string
+	name    string
+	metrics *Metrics
 }
 
-func NewAPIMetricsCollector() *APIMetricsCollector {
-	return &APIMetricsCollector{name: "api_metrics"}
+func NewAPIMetricsCollector(metrics *Metrics) *APIMetricsCollector {
+	return &APIMetricsCollector{name: "api_metrics", metrics: metrics}
 }
 
 func (amc *APIMetricsCollector) GetName() string {
@@ -156,27 +211,43 @@ func (amc *APIMetricsCollector) GetName() string {
 }
 
 func (amc *APIMetricsCollector) Collect() []BusinessMetric {
-	// 这里应该从API中间件收集实际的指标
-	// 目前返回模拟数据
-	return []BusinessMetric{
-		{
-			Name:       "api_requests_total",
-			Value:      1000,
-			Timestamp:  time.Now(),
-			Labels:     map[string]string{"method": "GET", "endpoint": "/api/pods"},
-			MetricType: "counter",
-		},
-		{
-			Name:       "api_request_duration_seconds",
-			Value:      0.1,
-			Timestamp:  time.Now(),
-			Labels:     map[string]string{"method": "GET", "endpoint": "/api/pods"},
-			MetricType: "histogram",
-		},
+	if amc.metrics == nil {
+		return nil
 	}
+
+	now := time.Now()
+	stats := amc.metrics.RouteStats()
+	result := make([]BusinessMetric, 0, len(stats)*3)
+	for _, stat := range stats {
+		labels := map[string]string{"method": stat.Method, "endpoint": stat.Route}
+		result = append(result,
+			BusinessMetric{
+				Name:       "api_requests_total",
+				Value:      float64(stat.Requests),
+				Timestamp:  now,
+				Labels:     labels,
+				MetricType: "counter",
+			},
+			BusinessMetric{
+				Name:       "api_request_errors_total",
+				Value:      float64(stat.Errors),
+				Timestamp:  now,
+				Labels:     labels,
+				MetricType: "counter",
+			},
+			BusinessMetric{
+				Name:       "api_request_duration_seconds_avg",
+				Value:      stat.AvgLatency.Seconds(),
+				Timestamp:  now,
+				Labels:     labels,
+				MetricType: "gauge",
+			},
+		)
+	}
+	return result
 }
 
-// SystemMetricsCollector 系统指标收集器
+// SystemMetricsCollector 系统指标收集器，数据来自Go运行时
 type SystemMetricsCollector struct {
 	name string
 }
@@ -190,37 +261,73 @@ func (smc *SystemMetricsCollector) GetName() string {
 }
 
 func (smc *SystemMetricsCollector) Collect() []BusinessMetric {
-	// 这里应该收集实际的系统指标
-	// 目前返回模拟数据
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	now := time.Now()
+
 	return []BusinessMetric{
 		{
 			Name:       "memory_usage_bytes",
-			Value:      1024 * 1024 * 100, // 100MB
-			Timestamp:  time.Now(),
+			Value:      float64(memStats.HeapAlloc),
+			Timestamp:  now,
 			Labels:     map[string]string{"type": "heap"},
 			MetricType: "gauge",
 		},
 		{
-			Name:       "cpu_usage_percent",
-			Value:      25.5,
-			Timestamp:  time.Now(),
-			Labels:     map[string]string{"type": "user"},
+			Name:       "memory_usage_bytes",
+			Value:      float64(memStats.Sys),
+			Timestamp:  now,
+			Labels:     map[string]string{"type": "sys"},
+			MetricType: "gauge",
+		},
+		{
+			Name:       "goroutines",
+			Value:      float64(runtime.NumGoroutine()),
+			Timestamp:  now,
 			MetricType: "gauge",
 		},
+		{
+			Name:       "gc_pause_seconds_total",
+			Value:      float64(memStats.PauseTotalNs) / 1e9,
+			Timestamp:  now,
+			MetricType: "counter",
+		},
 	}
 }
 
 // 全局业务指标收集器实例
 var globalBusinessCollector *BusinessMetricsCollector
 
-// InitBusinessMetrics 初始化业务指标收集器
-func InitBusinessMetrics(logger *zap.Logger) {
+// InitBusinessMetrics 初始化业务指标收集器，counter 为空时不收集集群资源指标
+func InitBusinessMetrics(logger *zap.Logger, counter ResourceCounter) {
 	globalBusinessCollector = NewBusinessMetricsCollector(logger)
 
-	// 注册默认收集器
-	globalBusinessCollector.RegisterCollector(NewK8sResourceCollector())
-	globalBusinessCollector.RegisterCollector(NewAPIMetricsCollector())
+	var metrics *Metrics
+	if globalMonitor != nil {
+		metrics = globalMonitor.GetMetrics()
+	}
+
+	resourceCollector := NewK8sResourceCollector(counter, logger)
+	globalBusinessCollector.RegisterCollector(resourceCollector)
+	globalBusinessCollector.RegisterCollector(NewAPIMetricsCollector(metrics))
 	globalBusinessCollector.RegisterCollector(NewSystemMetricsCollector())
+
+	// 集群资源数量同时以Prometheus格式导出
+	if globalMonitor != nil {
+		globalMonitor.Registry().Register(NewFuncCollector("k8svision_cluster_resources",
+			"集群资源数量（按类型和命名空间）", MetricTypeGauge, func() []Sample {
+				collected := resourceCollector.Collect()
+				samples := make([]Sample, 0, len(collected))
+				for _, metric := range collected {
+					kind := strings.TrimSuffix(strings.TrimPrefix(metric.Name, "k8s_"), "_total")
+					samples = append(samples, Sample{
+						Labels: map[string]string{"kind": kind, "namespace": metric.Labels["namespace"]},
+						Value:  metric.Value,
+					})
+				}
+				return samples
+			}))
+	}
 }
 
 // GetBusinessMetricsCollector 获取全局业务指标收集器
@@ -0,0 +1,310 @@
+package monitor
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 延迟统计按 10 秒一个时间片滚动，查询时合并窗口内的时间片
+const (
+	latencySlotDuration = 10 * time.Second
+	latencySlotCount    = int(time.Hour / latencySlotDuration)
+
+	// sketchRelativeAccuracy 分位数的相对误差上限
+	sketchRelativeAccuracy = 0.01
+	// sketchMinValue 低于该值（毫秒）的观测计入零桶
+	sketchMinValue = 0.001
+)
+
+// LatencyWindows 对外提供的滑动窗口
+var LatencyWindows = []struct {
+	Name     string
+	Duration time.Duration
+}{
+	{"1m", time.Minute},
+	{"5m", 5 * time.Minute},
+	{"1h", time.Hour},
+}
+
+var (
+	sketchGamma    = (1 + sketchRelativeAccuracy) / (1 - sketchRelativeAccuracy)
+	sketchLogGamma = math.Log(sketchGamma)
+)
+
+// latencySketch 对数分桶的流式分位数草图（DDSketch），分位数相对误差不超过1%，可直接合并
+type latencySketch struct {
+	buckets map[int]uint64
+	zero    uint64
+	count   uint64
+	sum     float64
+	max     float64
+}
+
+func newLatencySketch() *latencySketch {
+	return &latencySketch{buckets: make(map[int]uint64)}
+}
+
+// add 记录一次观测值（毫秒）
+func (s *latencySketch) add(ms float64) {
+	s.count++
+	s.sum += ms
+	if ms > s.max {
+		s.max = ms
+	}
+	if ms < sketchMinValue {
+		s.zero++
+		return
+	}
+	s.buckets[int(math.Ceil(math.Log(ms)/sketchLogGamma))]++
+}
+
+func (s *latencySketch) merge(other *latencySketch) {
+	for idx, n := range other.buckets {
+		s.buckets[idx] += n
+	}
+	s.zero += other.zero
+	s.count += other.count
+	s.sum += other.sum
+	if other.max > s.max {
+		s.max = other.max
+	}
+}
+
+// quantile 返回分位数 q（0~1）的估计值（毫秒）
+func (s *latencySketch) quantile(q float64) float64 {
+	if s.count == 0 {
+		return 0
+	}
+	rank := uint64(q * float64(s.count-1))
+	if rank < s.zero {
+		return 0
+	}
+
+	indexes := make([]int, 0, len(s.buckets))
+	for idx := range s.buckets {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	cumulative := s.zero
+	for _, idx := range indexes {
+		cumulative += s.buckets[idx]
+		if cumulative > rank {
+			// 桶 (γ^(i-1), γ^i] 的中点估计，保证相对误差
+			value := 2 * math.Pow(sketchGamma, float64(idx)) / (sketchGamma + 1)
+			return math.Min(value, s.max)
+		}
+	}
+	return s.max
+}
+
+// latencyKey 统计维度：方法 + 路由模板
+type latencyKey struct {
+	method string
+	route  string
+}
+
+// latencyCell 某一维度在一个时间片内的统计
+type latencyCell struct {
+	sketch        *latencySketch
+	statusClasses [5]int64 // 1xx ~ 5xx
+}
+
+func newLatencyCell() *latencyCell {
+	return &latencyCell{sketch: newLatencySketch()}
+}
+
+func (c *latencyCell) merge(other *latencyCell) {
+	c.sketch.merge(other.sketch)
+	for i, n := range other.statusClasses {
+		c.statusClasses[i] += n
+	}
+}
+
+type latencySlot struct {
+	epoch int64 // 时间片序号，用于判断槽位是否过期
+	cells map[latencyKey]*latencyCell
+}
+
+// latencyTracker 按路由和方法统计延迟分位数与状态码分类，保留最近一小时
+type latencyTracker struct {
+	slots [latencySlotCount]latencySlot
+	mutex sync.Mutex
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{}
+}
+
+func slotEpoch(t time.Time) int64 {
+	return t.UnixNano() / int64(latencySlotDuration)
+}
+
+// record 记录一次请求耗时，方法同样归一化，避免任意方法名撑大分位数草图
+func (lt *latencyTracker) record(method, route string, status int, d time.Duration, now time.Time) {
+	epoch := slotEpoch(now)
+	key := latencyKey{method: normalizeMethod(method), route: route}
+
+	lt.mutex.Lock()
+	defer lt.mutex.Unlock()
+
+	slot := &lt.slots[epoch%int64(latencySlotCount)]
+	if slot.epoch != epoch || slot.cells == nil {
+		slot.epoch = epoch
+		slot.cells = make(map[latencyKey]*latencyCell)
+	}
+	cell, exists := slot.cells[key]
+	if !exists {
+		cell = newLatencyCell()
+		slot.cells[key] = cell
+	}
+	cell.sketch.add(float64(d) / float64(time.Millisecond))
+	if class := status/100 - 1; class >= 0 && class < len(cell.statusClasses) {
+		cell.statusClasses[class]++
+	}
+}
+
+// collect 合并最近 window 内的时间片，返回各维度的统计
+func (lt *latencyTracker) collect(window time.Duration, now time.Time) map[latencyKey]*latencyCell {
+	current := slotEpoch(now)
+	oldest := current - int64(window/latencySlotDuration) + 1
+
+	lt.mutex.Lock()
+	defer lt.mutex.Unlock()
+
+	result := make(map[latencyKey]*latencyCell)
+	for i := range lt.slots {
+		slot := &lt.slots[i]
+		if slot.cells == nil || slot.epoch < oldest || slot.epoch > current {
+			continue
+		}
+		for key, cell := range slot.cells {
+			merged, exists := result[key]
+			if !exists {
+				merged = newLatencyCell()
+				result[key] = merged
+			}
+			merged.merge(cell)
+		}
+	}
+	return result
+}
+
+func (lt *latencyTracker) reset() {
+	lt.mutex.Lock()
+	defer lt.mutex.Unlock()
+	lt.slots = [latencySlotCount]latencySlot{}
+}
+
+// LatencyStats 一组请求的延迟分位数（毫秒）与状态码分类计数
+// ErrorRate 为 5xx 占比（%），RequestRate 为窗口内平均每秒请求数
+type LatencyStats struct {
+	Count         int64            `json:"count"`
+	RequestRate   float64          `json:"requestRate"`
+	ErrorRate     float64          `json:"errorRate"`
+	AvgMs         float64          `json:"avgMs"`
+	P50Ms         float64          `json:"p50Ms"`
+	P90Ms         float64          `json:"p90Ms"`
+	P99Ms         float64          `json:"p99Ms"`
+	P999Ms        float64          `json:"p999Ms"`
+	MaxMs         float64          `json:"maxMs"`
+	StatusClasses map[string]int64 `json:"statusClasses"`
+}
+
+// RouteLatency 单个路由的延迟统计
+type RouteLatency struct {
+	Method string `json:"method"`
+	Route  string `json:"route"`
+	LatencyStats
+}
+
+// LatencyWindow 一个滑动窗口内的整体、按方法和按路由统计
+type LatencyWindow struct {
+	Window   string                  `json:"window"`
+	Overall  LatencyStats            `json:"overall"`
+	ByMethod map[string]LatencyStats `json:"byMethod"`
+	ByRoute  []RouteLatency          `json:"byRoute"`
+}
+
+func newLatencyStats(cell *latencyCell, window time.Duration) LatencyStats {
+	s := cell.sketch
+	stats := LatencyStats{
+		Count:         int64(s.count),
+		P50Ms:         roundMs(s.quantile(0.5)),
+		P90Ms:         roundMs(s.quantile(0.9)),
+		P99Ms:         roundMs(s.quantile(0.99)),
+		P999Ms:        roundMs(s.quantile(0.999)),
+		MaxMs:         roundMs(s.max),
+		StatusClasses: make(map[string]int64, len(cell.statusClasses)),
+	}
+	if s.count > 0 {
+		stats.AvgMs = roundMs(s.sum / float64(s.count))
+		stats.ErrorRate = float64(cell.statusClasses[4]) / float64(s.count) * 100
+	}
+	stats.RequestRate = float64(s.count) / window.Seconds()
+	for i, n := range cell.statusClasses {
+		stats.StatusClasses[string(rune('1'+i))+"xx"] = n
+	}
+	return stats
+}
+
+func roundMs(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
+
+// LatencyWindow 返回最近 window 内的延迟统计，路由按请求数降序
+func (m *Metrics) LatencyWindow(name string, window time.Duration) LatencyWindow {
+	cells := m.latency.collect(window, time.Now())
+
+	overall := newLatencyCell()
+	byMethod := make(map[string]*latencyCell)
+	routes := make([]RouteLatency, 0, len(cells))
+
+	for key, cell := range cells {
+		overall.merge(cell)
+		methodCell, exists := byMethod[key.method]
+		if !exists {
+			methodCell = newLatencyCell()
+			byMethod[key.method] = methodCell
+		}
+		methodCell.merge(cell)
+		routes = append(routes, RouteLatency{
+			Method:       key.method,
+			Route:        key.route,
+			LatencyStats: newLatencyStats(cell, window),
+		})
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Count != routes[j].Count {
+			return routes[i].Count > routes[j].Count
+		}
+		if routes[i].Route != routes[j].Route {
+			return routes[i].Route < routes[j].Route
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	methods := make(map[string]LatencyStats, len(byMethod))
+	for method, cell := range byMethod {
+		methods[method] = newLatencyStats(cell, window)
+	}
+
+	return LatencyWindow{
+		Window:   name,
+		Overall:  newLatencyStats(overall, window),
+		ByMethod: methods,
+		ByRoute:  routes,
+	}
+}
+
+// LatencyReport 返回所有滑动窗口（1m、5m、1h）的延迟统计
+func (m *Metrics) LatencyReport() []LatencyWindow {
+	report := make([]LatencyWindow, 0, len(LatencyWindows))
+	for _, w := range LatencyWindows {
+		report = append(report, m.LatencyWindow(w.Name, w.Duration))
+	}
+	return report
+}
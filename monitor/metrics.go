@@ -2,11 +2,15 @@ package monitor
 
 import (
 	"context"
+	"net/http"
 	"runtime"
+	"sort"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/nick0323/K8sVision/version"
 	"go.uber.org/zap"
 )
 
@@ -44,6 +48,10 @@ type Metrics struct {
 	K8sAPIDuration time.Duration `json:"k8sApiDuration"`
 	K8sAPIErrors   int64         `json:"k8sApiErrors"`
 
+	// 客户端限流等待（client-go 本地QPS/Burst限制）
+	K8sThrottledCalls  int64         `json:"k8sThrottledCalls"`
+	K8sThrottleWaiting time.Duration `json:"k8sThrottleWaiting"`
+
 	ResourceCounts map[string]int64 `json:"resourceCounts"`
 
 	MemoryUsage     int64 `json:"memoryUsage"`
@@ -54,6 +62,30 @@ type Metrics struct {
 	totalResponseTime time.Duration
 	requestCount      int64
 	logger            *zap.Logger
+
+	// 按路由统计，同时用于Prometheus导出
+	httpRequests *CounterVec
+	httpDuration *HistogramVec
+
+	// 按路由和方法的延迟分位数与滑动窗口
+	latency *latencyTracker
+
+	// 按动词、资源统计的K8s API调用
+	k8sRequests *CounterVec
+	k8sDuration *HistogramVec
+	k8sThrottle *HistogramVec
+}
+
+// K8sThrottleThreshold 限流等待超过该值时计为一次被限流的调用（与client-go日志阈值一致）
+const K8sThrottleThreshold = 50 * time.Millisecond
+
+// RouteStat 单个路由的请求统计
+type RouteStat struct {
+	Method     string        `json:"method"`
+	Route      string        `json:"route"`
+	Requests   int64         `json:"requests"`
+	Errors     int64         `json:"errors"`
+	AvgLatency time.Duration `json:"avgLatency"`
 }
 
 func NewMetrics(logger *zap.Logger) *Metrics {
@@ -61,7 +93,76 @@ func NewMetrics(logger *zap.Logger) *Metrics {
 		StartTime:      time.Now(),
 		logger:         logger,
 		ResourceCounts: make(map[string]int64),
+		httpRequests: NewCounterVec("k8svision_http_requests_total",
+			"HTTP请求总数", "method", "route", "status"),
+		httpDuration: NewHistogramVec("k8svision_http_request_duration_seconds",
+			"HTTP请求处理耗时（秒）", DefaultLatencyBuckets, "method", "route"),
+		latency: newLatencyTracker(),
+		k8sRequests: NewCounterVec("k8svision_k8s_api_requests_total",
+			"Kubernetes API请求数（按动词、资源、命名空间和状态码）", "verb", "resource", "namespace", "code"),
+		k8sDuration: NewHistogramVec("k8svision_k8s_api_request_duration_seconds",
+			"Kubernetes API请求耗时（秒）", DefaultLatencyBuckets, "verb", "resource"),
+		k8sThrottle: NewHistogramVec("k8svision_k8s_client_throttle_wait_seconds",
+			"client-go本地限流等待时间（秒）", DefaultLatencyBuckets, "verb"),
+	}
+}
+
+// otherMethod 非标准HTTP方法统一归入的标签值
+const otherMethod = "other"
+
+// normalizeMethod 将请求方法归一为标准方法，其余（客户端可任意构造）归入 other，避免指标序列无限增长
+func normalizeMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions, http.MethodConnect, http.MethodTrace:
+		return method
+	}
+	return otherMethod
+}
+
+// RecordRouteRequest 按方法、路由模板和状态码记录请求
+func (m *Metrics) RecordRouteRequest(method, route string, status int, responseTime time.Duration) {
+	label := normalizeMethod(method)
+	m.httpRequests.Inc(label, route, strconv.Itoa(status))
+	m.httpDuration.Observe(responseTime.Seconds(), label, route)
+	m.latency.record(label, route, status, responseTime, time.Now())
+}
+
+// RouteStats 返回各路由的请求数、错误数和平均耗时，按请求数降序
+func (m *Metrics) RouteStats() []RouteStat {
+	type routeKey struct{ method, route string }
+	stats := make(map[routeKey]*RouteStat)
+
+	for _, sample := range m.httpRequests.Snapshot() {
+		key := routeKey{sample.Labels["method"], sample.Labels["route"]}
+		stat, exists := stats[key]
+		if !exists {
+			stat = &RouteStat{Method: key.method, Route: key.route}
+			stats[key] = stat
+		}
+		stat.Requests += int64(sample.Value)
+		if status, err := strconv.Atoi(sample.Labels["status"]); err == nil && status >= 400 {
+			stat.Errors += int64(sample.Value)
+		}
 	}
+	for _, sample := range m.httpDuration.Snapshot() {
+		key := routeKey{sample.Labels["method"], sample.Labels["route"]}
+		if stat, exists := stats[key]; exists && sample.Count > 0 {
+			stat.AvgLatency = time.Duration(sample.Sum / float64(sample.Count) * float64(time.Second))
+		}
+	}
+
+	result := make([]RouteStat, 0, len(stats))
+	for _, stat := range stats {
+		result = append(result, *stat)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Requests != result[j].Requests {
+			return result[i].Requests > result[j].Requests
+		}
+		return result[i].Route < result[j].Route
+	})
+	return result
 }
 
 // RecordRequest 记录请求
@@ -149,6 +250,28 @@ func (m *Metrics) RecordK8sAPICall(duration time.Duration, success bool) {
 	}
 }
 
+// RecordK8sRequest 记录一次apiserver请求；code 为HTTP状态码，网络错误时为 "error"
+func (m *Metrics) RecordK8sRequest(verb, resource, namespace, code string, duration time.Duration) {
+	m.k8sRequests.Inc(verb, resource, namespace, code)
+	m.k8sDuration.Observe(duration.Seconds(), verb, resource)
+
+	status, err := strconv.Atoi(code)
+	m.RecordK8sAPICall(duration, err == nil && status < 400)
+}
+
+// RecordK8sThrottle 记录client-go本地限流的等待时间
+func (m *Metrics) RecordK8sThrottle(verb string, wait time.Duration) {
+	m.k8sThrottle.Observe(wait.Seconds(), verb)
+	if wait < K8sThrottleThreshold {
+		return
+	}
+	atomic.AddInt64(&m.K8sThrottledCalls, 1)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.K8sThrottleWaiting += wait
+}
+
 // RecordResourceCount 记录资源数量
 func (m *Metrics) RecordResourceCount(resourceType string, count int64) {
 	m.mutex.Lock()
@@ -184,6 +307,7 @@ func (m *Metrics) GetStats() map[string]interface{} {
 	errorCount := atomic.LoadInt64(&m.ErrorCount)
 	k8sAPICalls := atomic.LoadInt64(&m.K8sAPICalls)
 	k8sAPIErrors := atomic.LoadInt64(&m.K8sAPIErrors)
+	k8sThrottledCalls := atomic.LoadInt64(&m.K8sThrottledCalls)
 	memoryUsage := atomic.LoadInt64(&m.MemoryUsage)
 	memoryAllocated := atomic.LoadInt64(&m.MemoryAllocated)
 	goroutineCount := atomic.LoadInt64(&m.GoroutineCount)
@@ -243,6 +367,8 @@ func (m *Metrics) GetStats() map[string]interface{} {
 		"k8sApiSuccessRate":  k8sAPISuccessRate,
 		"k8sApiDuration":     m.K8sAPIDuration.String(),
 		"avgK8sApiDuration":  avgK8sAPIDuration.String(),
+		"k8sThrottledCalls":  k8sThrottledCalls,
+		"k8sThrottleWaiting": m.K8sThrottleWaiting.String(),
 		"resourceCounts":     m.ResourceCounts,
 		"memoryUsage":        memoryUsage,
 		"memoryAllocated":    memoryAllocated,
@@ -266,6 +392,7 @@ func (m *Metrics) Reset() {
 	atomic.StoreInt64(&m.ErrorCount, 0)
 	atomic.StoreInt64(&m.K8sAPICalls, 0)
 	atomic.StoreInt64(&m.K8sAPIErrors, 0)
+	atomic.StoreInt64(&m.K8sThrottledCalls, 0)
 	atomic.StoreInt64(&m.MemoryUsage, 0)
 	atomic.StoreInt64(&m.MemoryAllocated, 0)
 	atomic.StoreInt64(&m.GoroutineCount, 0)
@@ -276,22 +403,25 @@ func (m *Metrics) Reset() {
 	m.totalResponseTime = 0
 	m.requestCount = 0
 	m.K8sAPIDuration = 0
+	m.K8sThrottleWaiting = 0
 	m.LastError = ""
 	m.LastErrorTime = time.Time{}
 	m.StartTime = time.Now()
 
 	// 清空资源计数
 	m.ResourceCounts = make(map[string]int64)
+	m.latency.reset()
 
 	m.logger.Info("性能指标已重置")
 }
 
 // Monitor 性能监控器
 type Monitor struct {
-	metrics *Metrics
-	logger  *zap.Logger
-	ctx     context.Context
-	cancel  context.CancelFunc
+	metrics  *Metrics
+	registry *Registry
+	logger   *zap.Logger
+	ctx      context.Context
+	cancel   context.CancelFunc
 }
 
 // NewMonitor 创建新的性能监控器
@@ -299,11 +429,13 @@ func NewMonitor(logger *zap.Logger) *Monitor {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	monitor := &Monitor{
-		metrics: NewMetrics(logger),
-		logger:  logger,
-		ctx:     ctx,
-		cancel:  cancel,
+		metrics:  NewMetrics(logger),
+		registry: NewRegistry(),
+		logger:   logger,
+		ctx:      ctx,
+		cancel:   cancel,
 	}
+	monitor.registerPrometheusMetrics()
 
 	globalMonitor = monitor
 	return monitor
@@ -314,6 +446,51 @@ func (m *Monitor) GetMetrics() *Metrics {
 	return m.metrics
 }
 
+// Registry 获取Prometheus指标注册表
+func (m *Monitor) Registry() *Registry {
+	return m.registry
+}
+
+// registerPrometheusMetrics 注册请求、缓存、K8s API 及Go运行时指标
+func (m *Monitor) registerPrometheusMetrics() {
+	metrics := m.metrics
+	r := m.registry
+
+	r.Register(metrics.httpRequests)
+	r.Register(metrics.httpDuration)
+	r.Register(NewGaugeFunc("k8svision_http_requests_in_flight", "正在处理的HTTP请求数", func() float64 {
+		return float64(atomic.LoadInt64(&metrics.CurrentConnections))
+	}))
+	r.Register(NewCounterFunc("k8svision_cache_hits_total", "响应缓存命中次数", func() float64 {
+		return float64(atomic.LoadInt64(&metrics.CacheHits))
+	}))
+	r.Register(NewCounterFunc("k8svision_cache_misses_total", "响应缓存未命中次数", func() float64 {
+		return float64(atomic.LoadInt64(&metrics.CacheMisses))
+	}))
+	r.Register(metrics.k8sRequests)
+	r.Register(metrics.k8sDuration)
+	r.Register(metrics.k8sThrottle)
+	r.Register(NewCounterFunc("k8svision_k8s_api_errors_total", "Kubernetes API调用失败次数", func() float64 {
+		return float64(atomic.LoadInt64(&metrics.K8sAPIErrors))
+	}))
+	r.Register(NewCounterFunc("k8svision_k8s_client_throttled_requests_total", "被client-go本地限流明显延迟的请求数", func() float64 {
+		return float64(atomic.LoadInt64(&metrics.K8sThrottledCalls))
+	}))
+	r.Register(NewFuncCollector("k8svision_build_info", "构建信息", MetricTypeGauge, func() []Sample {
+		info := version.Get()
+		return []Sample{{Labels: map[string]string{
+			"version":   info.Version,
+			"commit":    info.Commit,
+			"builddate": info.BuildDate,
+		}, Value: 1}}
+	}))
+	r.Register(NewGaugeFunc("process_start_time_seconds", "进程启动时间（Unix秒）", func() float64 {
+		return float64(metrics.StartTime.Unix())
+	}))
+
+	registerRuntimeMetrics(r)
+}
+
 // GetAllMetrics 获取所有指标（兼容性方法）
 func (m *Monitor) GetAllMetrics() map[string]interface{} {
 	metrics := m.GetMetrics()
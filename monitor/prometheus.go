@@ -0,0 +1,371 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Prometheus 文本格式（0.0.4）导出，不依赖外部客户端库
+
+// PrometheusContentType /metrics 响应的Content-Type
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// 指标类型
+const (
+	MetricTypeCounter   = "counter"
+	MetricTypeGauge     = "gauge"
+	MetricTypeHistogram = "histogram"
+)
+
+// DefaultLatencyBuckets 默认延迟直方图分桶（秒）
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Sample 一个带标签的指标值
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// PromCollector 可导出为Prometheus文本格式的指标
+type PromCollector interface {
+	Name() string
+	Expose(w *bufio.Writer)
+}
+
+// Registry 指标注册表
+type Registry struct {
+	collectors []PromCollector
+	names      map[string]bool
+	mutex      sync.RWMutex
+}
+
+// NewRegistry 创建指标注册表
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// Register 注册指标，同名指标重复注册时忽略
+func (r *Registry) Register(c PromCollector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.names[c.Name()] {
+		return
+	}
+	r.names[c.Name()] = true
+	r.collectors = append(r.collectors, c)
+}
+
+// WritePrometheus 按指标名排序输出所有指标
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mutex.RLock()
+	collectors := make([]PromCollector, len(r.collectors))
+	copy(collectors, r.collectors)
+	r.mutex.RUnlock()
+
+	sort.Slice(collectors, func(i, j int) bool {
+		return collectors[i].Name() < collectors[j].Name()
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, c := range collectors {
+		c.Expose(bw)
+	}
+	return bw.Flush()
+}
+
+// CounterVec 带标签的计数器
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+	values map[string]*counterEntry
+	mutex  sync.RWMutex
+}
+
+type counterEntry struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounterVec 创建带标签的计数器
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		values: make(map[string]*counterEntry),
+	}
+}
+
+func (cv *CounterVec) Name() string { return cv.name }
+
+// Inc 计数加一，labelValues 顺序与创建时的标签一致
+func (cv *CounterVec) Inc(labelValues ...string) {
+	cv.Add(1, labelValues...)
+}
+
+// Add 计数增加 v
+func (cv *CounterVec) Add(v float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+
+	cv.mutex.Lock()
+	defer cv.mutex.Unlock()
+
+	entry, exists := cv.values[key]
+	if !exists {
+		entry = &counterEntry{labelValues: append([]string(nil), labelValues...)}
+		cv.values[key] = entry
+	}
+	entry.value += v
+}
+
+// Snapshot 返回当前所有标签组合的值
+func (cv *CounterVec) Snapshot() []Sample {
+	cv.mutex.RLock()
+	defer cv.mutex.RUnlock()
+
+	samples := make([]Sample, 0, len(cv.values))
+	for _, entry := range cv.values {
+		samples = append(samples, Sample{
+			Labels: labelMap(cv.labels, entry.labelValues),
+			Value:  entry.value,
+		})
+	}
+	return samples
+}
+
+func (cv *CounterVec) Expose(w *bufio.Writer) {
+	writeHeader(w, cv.name, cv.help, MetricTypeCounter)
+	writeSamples(w, cv.name, cv.Snapshot())
+}
+
+// HistogramVec 带标签的直方图
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	values  map[string]*histogramEntry
+	mutex   sync.RWMutex
+}
+
+type histogramEntry struct {
+	labelValues []string
+	counts      []uint64 // 各分桶的非累计计数
+	count       uint64
+	sum         float64
+}
+
+// NewHistogramVec 创建带标签的直方图，buckets 需升序
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: buckets,
+		values:  make(map[string]*histogramEntry),
+	}
+}
+
+func (hv *HistogramVec) Name() string { return hv.name }
+
+// Observe 记录一次观测值
+func (hv *HistogramVec) Observe(v float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+	idx := sort.SearchFloat64s(hv.buckets, v)
+
+	hv.mutex.Lock()
+	defer hv.mutex.Unlock()
+
+	entry, exists := hv.values[key]
+	if !exists {
+		entry = &histogramEntry{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(hv.buckets)),
+		}
+		hv.values[key] = entry
+	}
+	if idx < len(hv.buckets) {
+		entry.counts[idx]++
+	}
+	entry.count++
+	entry.sum += v
+}
+
+// HistogramSample 直方图某一标签组合的汇总
+type HistogramSample struct {
+	Labels map[string]string
+	Count  uint64
+	Sum    float64
+}
+
+// Snapshot 返回各标签组合的观测次数与总和
+func (hv *HistogramVec) Snapshot() []HistogramSample {
+	hv.mutex.RLock()
+	defer hv.mutex.RUnlock()
+
+	samples := make([]HistogramSample, 0, len(hv.values))
+	for _, entry := range hv.values {
+		samples = append(samples, HistogramSample{
+			Labels: labelMap(hv.labels, entry.labelValues),
+			Count:  entry.count,
+			Sum:    entry.sum,
+		})
+	}
+	return samples
+}
+
+func (hv *HistogramVec) Expose(w *bufio.Writer) {
+	writeHeader(w, hv.name, hv.help, MetricTypeHistogram)
+
+	hv.mutex.RLock()
+	defer hv.mutex.RUnlock()
+
+	keys := make([]string, 0, len(hv.values))
+	for k := range hv.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		entry := hv.values[k]
+		labels := labelMap(hv.labels, entry.labelValues)
+
+		var cumulative uint64
+		for i, upper := range hv.buckets {
+			cumulative += entry.counts[i]
+			writeLine(w, hv.name+"_bucket", withLabel(labels, "le", formatFloat(upper)), float64(cumulative))
+		}
+		writeLine(w, hv.name+"_bucket", withLabel(labels, "le", "+Inf"), float64(entry.count))
+		writeLine(w, hv.name+"_sum", labels, entry.sum)
+		writeLine(w, hv.name+"_count", labels, float64(entry.count))
+	}
+}
+
+// FuncCollector 在导出时调用函数取值，适用于已有数据源（如运行时统计、缓存计数）
+type FuncCollector struct {
+	name       string
+	help       string
+	metricType string
+	collect    func() []Sample
+}
+
+// NewGaugeFunc 创建无标签的仪表盘指标
+func NewGaugeFunc(name, help string, fn func() float64) *FuncCollector {
+	return NewFuncCollector(name, help, MetricTypeGauge, func() []Sample {
+		return []Sample{{Value: fn()}}
+	})
+}
+
+// NewCounterFunc 创建无标签的计数器指标，fn 需返回单调递增的值
+func NewCounterFunc(name, help string, fn func() float64) *FuncCollector {
+	return NewFuncCollector(name, help, MetricTypeCounter, func() []Sample {
+		return []Sample{{Value: fn()}}
+	})
+}
+
+// NewFuncCollector 创建由函数提供多组标签值的指标
+func NewFuncCollector(name, help, metricType string, collect func() []Sample) *FuncCollector {
+	return &FuncCollector{name: name, help: help, metricType: metricType, collect: collect}
+}
+
+func (fc *FuncCollector) Name() string { return fc.name }
+
+func (fc *FuncCollector) Expose(w *bufio.Writer) {
+	writeHeader(w, fc.name, fc.help, fc.metricType)
+	writeSamples(w, fc.name, fc.collect())
+}
+
+func writeHeader(w *bufio.Writer, name, help, metricType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, escapeHelp(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+func writeSamples(w *bufio.Writer, name string, samples []Sample) {
+	sort.Slice(samples, func(i, j int) bool {
+		return formatLabels(samples[i].Labels) < formatLabels(samples[j].Labels)
+	})
+	for _, s := range samples {
+		writeLine(w, name, s.Labels, s.Value)
+	}
+}
+
+func writeLine(w *bufio.Writer, name string, labels map[string]string, value float64) {
+	w.WriteString(name)
+	w.WriteString(formatLabels(labels))
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, k := range names {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(k)
+		sb.WriteString(`="`)
+		sb.WriteString(escapeLabelValue(labels[k]))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(v string) string { return labelValueEscaper.Replace(v) }
+
+func escapeHelp(v string) string { return helpEscaper.Replace(v) }
+
+func labelMap(names, values []string) map[string]string {
+	labels := make(map[string]string, len(names))
+	for i, name := range names {
+		if i < len(values) {
+			labels[name] = values[i]
+		}
+	}
+	return labels
+}
+
+func withLabel(labels map[string]string, name, value string) map[string]string {
+	result := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		result[k] = v
+	}
+	result[name] = value
+	return result
+}
@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// memStatsCache 缓存 runtime.MemStats，避免一次抓取中多个指标重复触发 STW
+type memStatsCache struct {
+	stats    runtime.MemStats
+	readAt   time.Time
+	maxStale time.Duration
+	mutex    sync.Mutex
+}
+
+func (mc *memStatsCache) get() runtime.MemStats {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	if time.Since(mc.readAt) > mc.maxStale {
+		runtime.ReadMemStats(&mc.stats)
+		mc.readAt = time.Now()
+	}
+	return mc.stats
+}
+
+// registerRuntimeMetrics 注册Go运行时指标，命名与官方客户端保持一致
+func registerRuntimeMetrics(r *Registry) {
+	mc := &memStatsCache{maxStale: time.Second}
+	memGauge := func(name, help string, fn func(ms *runtime.MemStats) float64) *FuncCollector {
+		return NewGaugeFunc(name, help, func() float64 {
+			ms := mc.get()
+			return fn(&ms)
+		})
+	}
+	memCounter := func(name, help string, fn func(ms *runtime.MemStats) float64) *FuncCollector {
+		return NewCounterFunc(name, help, func() float64 {
+			ms := mc.get()
+			return fn(&ms)
+		})
+	}
+
+	r.Register(NewFuncCollector("go_info", "Go版本信息", MetricTypeGauge, func() []Sample {
+		return []Sample{{Labels: map[string]string{"version": runtime.Version()}, Value: 1}}
+	}))
+	r.Register(NewGaugeFunc("go_goroutines", "当前Goroutine数量", func() float64 {
+		return float64(runtime.NumGoroutine())
+	}))
+	r.Register(NewGaugeFunc("go_threads", "操作系统线程数", func() float64 {
+		n, _ := runtime.ThreadCreateProfile(nil)
+		return float64(n)
+	}))
+	r.Register(memGauge("go_memstats_alloc_bytes", "已分配且仍在使用的堆内存字节数", func(ms *runtime.MemStats) float64 {
+		return float64(ms.Alloc)
+	}))
+	r.Register(memCounter("go_memstats_alloc_bytes_total", "累计分配的堆内存字节数", func(ms *runtime.MemStats) float64 {
+		return float64(ms.TotalAlloc)
+	}))
+	r.Register(memGauge("go_memstats_sys_bytes", "从操作系统获取的内存字节数", func(ms *runtime.MemStats) float64 {
+		return float64(ms.Sys)
+	}))
+	r.Register(memGauge("go_memstats_heap_inuse_bytes", "使用中的堆span字节数", func(ms *runtime.MemStats) float64 {
+		return float64(ms.HeapInuse)
+	}))
+	r.Register(memGauge("go_memstats_heap_objects", "已分配的堆对象数", func(ms *runtime.MemStats) float64 {
+		return float64(ms.HeapObjects)
+	}))
+	r.Register(memGauge("go_memstats_next_gc_bytes", "下次GC的堆大小目标", func(ms *runtime.MemStats) float64 {
+		return float64(ms.NextGC)
+	}))
+	r.Register(memGauge("go_memstats_last_gc_time_seconds", "上次GC完成时间（Unix秒）", func(ms *runtime.MemStats) float64 {
+		return float64(ms.LastGC) / 1e9
+	}))
+	r.Register(memCounter("go_gc_cycles_total", "已完成的GC次数", func(ms *runtime.MemStats) float64 {
+		return float64(ms.NumGC)
+	}))
+	r.Register(memCounter("go_gc_pause_seconds_total", "GC累计暂停时间（秒）", func(ms *runtime.MemStats) float64 {
+		return float64(ms.PauseTotalNs) / 1e9
+	}))
+}
@@ -1,19 +1,169 @@
 package monitor
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/nick0323/K8sVision/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
-type Tracer interface {
+// TracerName 本服务创建Span时使用的instrumentation名称
+const TracerName = "github.com/nick0323/K8sVision"
+
+// 支持的导出器
+const (
+	TraceExporterOTLP   = "otlp"
+	TraceExporterStdout = "stdout"
+	TraceExporterFile   = "file"
+	TraceExporterNone   = "none"
+)
+
+var (
+	tracerProvider *sdktrace.TracerProvider
+	traceFile      *os.File
+	tracingMutex   sync.Mutex
+)
+
+// InitTracing 初始化全局TracerProvider与W3C传播器
+// 未启用时仍安装不采样的Provider，保证每个请求都有符合W3C规范的traceId并能透传上游trace
+func InitTracing(cfg *model.TracingConfig, logger *zap.Logger) error {
+	tracingMutex.Lock()
+	defer tracingMutex.Unlock()
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("链路追踪内部错误", zap.Error(err))
+	}))
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return fmt.Errorf("创建追踪资源失败: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	if !cfg.Enabled || cfg.Exporter == TraceExporterNone {
+		opts = append(opts, sdktrace.WithSampler(sdktrace.NeverSample()))
+	} else {
+		exporter, err := newTraceExporter(cfg)
+		if err != nil {
+			return err
+		}
+		opts = append(opts,
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		)
+	}
+
+	tracerProvider = sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tracerProvider)
+
+	logger.Info("初始化链路追踪",
+		zap.Bool("enabled", cfg.Enabled),
+		zap.String("exporter", cfg.Exporter),
+		zap.Float64("sampleRatio", cfg.SampleRatio),
+	)
+	return nil
 }
 
-func NewTracer(logger *zap.Logger) Tracer {
-	logger.Info("初始化追踪器")
-	return &tracerImpl{}
+// newTraceExporter 按配置创建导出器
+func newTraceExporter(cfg *model.TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case TraceExporterOTLP:
+		// 未配置的项交给 OTEL_EXPORTER_OTLP_* 环境变量决定
+		var opts []otlptracehttp.Option
+		if cfg.OTLP.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLP.Endpoint))
+		}
+		if cfg.OTLP.URLPath != "" {
+			opts = append(opts, otlptracehttp.WithURLPath(cfg.OTLP.URLPath))
+		}
+		if cfg.OTLP.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.OTLP.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.OTLP.Headers))
+		}
+		if cfg.OTLP.Timeout > 0 {
+			opts = append(opts, otlptracehttp.WithTimeout(cfg.OTLP.Timeout))
+		}
+		exporter, err := otlptracehttp.New(context.Background(), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("创建OTLP导出器失败: %w", err)
+		}
+		return exporter, nil
+
+	case TraceExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+
+	case TraceExporterFile:
+		if dir := filepath.Dir(cfg.File); dir != "" {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, fmt.Errorf("创建追踪文件目录失败: %w", err)
+			}
+		}
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("打开追踪文件失败: %w", err)
+		}
+		traceFile = f
+		// 每行一个Span，便于 jq 等工具处理
+		return stdouttrace.New(stdouttrace.WithWriter(f))
+	}
+	return nil, fmt.Errorf("不支持的追踪导出器: %s", cfg.Exporter)
 }
 
-type tracerImpl struct{}
+// ShutdownTracing 导出剩余Span并释放资源
+func ShutdownTracing(ctx context.Context) error {
+	tracingMutex.Lock()
+	defer tracingMutex.Unlock()
+
+	var errs []error
+	if tracerProvider != nil {
+		errs = append(errs, tracerProvider.Shutdown(ctx))
+		tracerProvider = nil
+	}
+	if traceFile != nil {
+		errs = append(errs, traceFile.Close())
+		traceFile = nil
+	}
+	return errors.Join(errs...)
+}
+
+// Tracer 返回本服务的Tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// StartSpan 在ctx下创建子Span
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
 
-func InitTracing(logger *zap.Logger) {
-	NewTracer(logger)
+// EndSpan 结束Span，err 非空时记录错误并标记失败
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 }
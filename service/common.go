@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/nick0323/K8sVision/model"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -100,6 +102,14 @@ func GetCronJobStatus(activeCount int, lastSuccessfulTime interface{}) string {
 	}
 }
 
+// FormatAge 以 kubectl 风格返回资源存在时长（如 5m、3d2h），零值时返回空字符串
+func FormatAge(created time.Time) string {
+	if created.IsZero() {
+		return ""
+	}
+	return duration.HumanDuration(time.Since(created))
+}
+
 func ExtractKeys[T any](data map[string]T) []string {
 	keys := make([]string, 0, len(data))
 	for key := range data {
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nick0323/K8sVision/model"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// kubectl drain 处理对应Pod所需的参数
+const (
+	drainFlagIgnoreDaemonSets = "--ignore-daemonsets"
+	drainFlagDeleteEmptyDir   = "--delete-emptydir-data"
+	drainFlagForce            = "--force"
+)
+
+// 预览中Pod的排列顺序：被阻塞的在前，跳过的在后
+var drainActionOrder = map[string]int{
+	model.DrainActionBlocked: 0,
+	model.DrainActionEvict:   1,
+	model.DrainActionDelete:  2,
+	model.DrainActionSkip:    3,
+}
+
+// GetDrainPreview 预览驱逐节点时各Pod的处理方式，找出会阻塞驱逐的Pod，不会修改集群
+func GetDrainPreview(ctx context.Context, clientset *kubernetes.Clientset, nodeName string) (*model.DrainPreview, error) {
+	node, err := clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := listPodsOnNode(ctx, clientset, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	pdbList, err := clientset.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &model.DrainPreview{
+		Node:          node.Name,
+		Unschedulable: node.Spec.Unschedulable,
+		TotalPods:     len(pods),
+		RequiredFlags: make([]string, 0),
+		Pods:          make([]model.DrainPod, 0, len(pods)),
+	}
+	for i := range pods {
+		dp := classifyDrainPod(&pods[i], pdbList.Items)
+		switch dp.Action {
+		case model.DrainActionBlocked:
+			preview.BlockedPods++
+		case model.DrainActionSkip:
+			preview.SkipPods++
+		default:
+			preview.EvictPods++
+		}
+		for _, flag := range dp.Flags {
+			if !ContainsString(preview.RequiredFlags, flag) {
+				preview.RequiredFlags = append(preview.RequiredFlags, flag)
+			}
+		}
+		preview.Pods = append(preview.Pods, dp)
+	}
+	sort.Strings(preview.RequiredFlags)
+	sort.SliceStable(preview.Pods, func(i, j int) bool {
+		a, b := preview.Pods[i], preview.Pods[j]
+		if a.Action != b.Action {
+			return drainActionOrder[a.Action] < drainActionOrder[b.Action]
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	preview.CanDrain = preview.BlockedPods == 0
+	return preview, nil
+}
+
+// listPodsOnNode 获取调度到指定节点上的Pod
+func listPodsOnNode(ctx context.Context, clientset *kubernetes.Clientset, nodeName string) ([]corev1.Pod, error) {
+	podList, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return podList.Items, nil
+}
+
+// classifyDrainPod 按 kubectl drain 的规则判断Pod的处理方式，pdbs 可以包含其他命名空间的PDB
+func classifyDrainPod(pod *corev1.Pod, pdbs []policyv1.PodDisruptionBudget) model.DrainPod {
+	dp := model.DrainPod{
+		Namespace:     pod.Namespace,
+		Name:          pod.Name,
+		Phase:         string(pod.Status.Phase),
+		Owner:         controllerOwner(pod.OwnerReferences),
+		PriorityClass: pod.Spec.PriorityClassName,
+		Priority:      SafeInt32Ptr(pod.Spec.Priority, 0),
+		PDBs:          make([]string, 0),
+		Action:        model.DrainActionEvict,
+		Flags:         make([]string, 0),
+		Reasons:       make([]string, 0),
+	}
+	dp.Unmanaged = dp.Owner == ""
+	dp.DaemonSet = strings.HasPrefix(dp.Owner, WorkloadDaemonSet+"/")
+
+	if _, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]; ok {
+		dp.Mirror = true
+		dp.Action = model.DrainActionSkip
+		dp.Reasons = append(dp.Reasons, "静态Pod，由kubelet管理，无法通过API驱逐")
+		return dp
+	}
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		dp.Action = model.DrainActionDelete
+		dp.Reasons = append(dp.Reasons, "Pod已结束，将直接删除")
+		return dp
+	}
+	if dp.DaemonSet {
+		dp.Action = model.DrainActionSkip
+		dp.Flags = append(dp.Flags, drainFlagIgnoreDaemonSets)
+		dp.Reasons = append(dp.Reasons, "由DaemonSet管理，驱逐后会在原节点重建")
+		return dp
+	}
+	if dp.Unmanaged {
+		dp.Flags = append(dp.Flags, drainFlagForce)
+		dp.Reasons = append(dp.Reasons, "未被控制器管理，驱逐后不会重建")
+	}
+	for _, v := range pod.Spec.Volumes {
+		if v.EmptyDir != nil {
+			dp.LocalStorage = true
+			dp.Flags = append(dp.Flags, drainFlagDeleteEmptyDir)
+			dp.Reasons = append(dp.Reasons, fmt.Sprintf("使用 emptyDir 卷 %s，驱逐后数据丢失", v.Name))
+			break
+		}
+	}
+
+	for i := range pdbs {
+		pdb := &pdbs[i]
+		if !pdbMatchesPod(pdb, pod) {
+			continue
+		}
+		dp.PDBs = append(dp.PDBs, pdb.Name)
+		if pdbBlocksEviction(pdb, pod) {
+			dp.PDBBlocked = true
+			dp.Reasons = append(dp.Reasons, fmt.Sprintf("PDB %s 当前允许的中断数为 %d", pdb.Name, pdb.Status.DisruptionsAllowed))
+		}
+	}
+	if len(dp.PDBs) > 1 {
+		dp.PDBBlocked = true
+		dp.Reasons = append(dp.Reasons, "Pod被多个PDB覆盖，Eviction API 会拒绝驱逐")
+	}
+	if dp.PDBBlocked {
+		dp.Action = model.DrainActionBlocked
+	}
+	return dp
+}
+
+// pdbBlocksEviction 判断PDB是否会拒绝驱逐该Pod：未就绪的Pod在 AlwaysAllow 策略或健康Pod数已满足时仍可驱逐
+func pdbBlocksEviction(pdb *policyv1.PodDisruptionBudget, pod *corev1.Pod) bool {
+	if pdb.Status.DisruptionsAllowed > 0 {
+		return false
+	}
+	if isPodReady(pod) {
+		return true
+	}
+	if p := pdb.Spec.UnhealthyPodEvictionPolicy; p != nil && *p == policyv1.AlwaysAllow {
+		return false
+	}
+	return pdb.Status.CurrentHealthy < pdb.Status.DesiredHealthy
+}
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nick0323/K8sVision/model"
+
+	"go.uber.org/zap"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	ErrDrainJobNotFound = errors.New("驱逐任务不存在")
+	ErrDrainJobFinished = errors.New("驱逐任务已结束")
+	ErrDrainInProgress  = errors.New("该节点已有进行中的驱逐任务")
+	ErrDrainNotAllowed  = errors.New("节点上存在需要额外参数才能驱逐的Pod")
+	ErrInvalidDrainOpts = errors.New("驱逐参数无效")
+)
+
+const (
+	defaultDrainTimeout = 10 * time.Minute
+	maxDrainTimeout     = time.Hour
+	drainPollInterval   = 5 * time.Second
+	// 保留的驱逐任务数量，超出时移除最早结束的任务
+	maxDrainJobs = 50
+)
+
+// drainJob 一个后台驱逐任务，uids 与 job.Pods 一一对应，用于识别同名重建的Pod
+type drainJob struct {
+	mu     sync.Mutex
+	job    model.DrainJob
+	uids   []types.UID
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type drainJobStore struct {
+	mu    sync.Mutex
+	jobs  map[string]*drainJob
+	order []string
+}
+
+var drainJobs = &drainJobStore{jobs: make(map[string]*drainJob)}
+
+// CordonNode 将节点标记为可调度或不可调度
+func CordonNode(ctx context.Context, clientset *kubernetes.Clientset, name string, unschedulable bool) error {
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable)
+	_, err := clientset.CoreV1().Nodes().Patch(ctx, name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	return err
+}
+
+// StartDrain 检查节点上的Pod是否都能按参数驱逐，然后封锁节点并在后台通过 Eviction API 逐个驱逐
+// 后台任务使用调用者的客户端，因此驱逐同样受其权限约束；PDB 拒绝的驱逐会重试直到超时
+func StartDrain(ctx context.Context, clientset *kubernetes.Clientset, nodeName string, opts model.DrainOptions, createdBy string) (*model.DrainJob, error) {
+	timeout := defaultDrainTimeout
+	if opts.TimeoutSeconds < 0 || (opts.GracePeriodSeconds != nil && *opts.GracePeriodSeconds < 0) {
+		return nil, fmt.Errorf("%w: timeoutSeconds 与 gracePeriodSeconds 不能为负数", ErrInvalidDrainOpts)
+	}
+	if opts.TimeoutSeconds > 0 {
+		timeout = time.Duration(opts.TimeoutSeconds) * time.Second
+	}
+	if timeout > maxDrainTimeout {
+		return nil, fmt.Errorf("%w: timeoutSeconds 不能超过 %d", ErrInvalidDrainOpts, int(maxDrainTimeout.Seconds()))
+	}
+	opts.TimeoutSeconds = int(timeout.Seconds())
+
+	if _, err := clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{}); err != nil {
+		return nil, err
+	}
+	pods, err := listPodsOnNode(ctx, clientset, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	pdbList, err := clientset.PolicyV1().PodDisruptionBudgets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	job := &drainJob{
+		job: model.DrainJob{
+			Node:      nodeName,
+			Status:    model.DrainJobRunning,
+			Options:   opts,
+			CreatedBy: createdBy,
+			Pods:      make([]model.DrainJobPod, 0, len(pods)),
+		},
+		uids: make([]types.UID, 0, len(pods)),
+		done: make(chan struct{}),
+	}
+	problems := make([]string, 0)
+	for i := range pods {
+		pod := &pods[i]
+		dp := classifyDrainPod(pod, pdbList.Items)
+		key := pod.Namespace + "/" + pod.Name
+		entry := model.DrainJobPod{Namespace: pod.Namespace, Name: pod.Name, Status: model.DrainPodPending}
+		switch {
+		case dp.Mirror:
+			entry.Status, entry.Message = model.DrainPodSkipped, "静态Pod"
+		case dp.DaemonSet && !opts.IgnoreDaemonSets:
+			problems = append(problems, key+" 由DaemonSet管理（需要 ignoreDaemonSets）")
+		case dp.DaemonSet:
+			entry.Status, entry.Message = model.DrainPodSkipped, "DaemonSet管理的Pod"
+		default:
+			if dp.Unmanaged && !opts.Force {
+				problems = append(problems, key+" 未被控制器管理（需要 force）")
+			}
+			if dp.LocalStorage && !opts.DeleteEmptyDirData {
+				problems = append(problems, key+" 使用 emptyDir（需要 deleteEmptyDirData）")
+			}
+		}
+		job.job.Pods = append(job.job.Pods, entry)
+		job.uids = append(job.uids, pod.UID)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrDrainNotAllowed, strings.Join(problems, "；"))
+	}
+
+	if err := CordonNode(ctx, clientset, nodeName, true); err != nil {
+		return nil, err
+	}
+	// cancel 必须在任务可被查询（进而被取消）之前设置
+	runCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	job.cancel = cancel
+	if err := drainJobs.add(job); err != nil {
+		cancel()
+		return nil, err
+	}
+	go job.run(runCtx, clientset)
+
+	zap.L().Info("节点驱逐任务已启动",
+		zap.String("jobId", job.job.ID),
+		zap.String("node", nodeName),
+		zap.String("operator", createdBy),
+		zap.Int("pods", len(pods)),
+	)
+	snapshot := job.snapshot()
+	return &snapshot, nil
+}
+
+// ListDrainJobs 返回所有驱逐任务，最近启动的在前
+func ListDrainJobs() []model.DrainJob {
+	drainJobs.mu.Lock()
+	defer drainJobs.mu.Unlock()
+	result := make([]model.DrainJob, 0, len(drainJobs.order))
+	for i := len(drainJobs.order) - 1; i >= 0; i-- {
+		result = append(result, drainJobs.jobs[drainJobs.order[i]].snapshot())
+	}
+	return result
+}
+
+func GetDrainJob(id string) (*model.DrainJob, error) {
+	job := drainJobs.get(id)
+	if job == nil {
+		return nil, ErrDrainJobNotFound
+	}
+	snapshot := job.snapshot()
+	return &snapshot, nil
+}
+
+// CancelDrainJob 取消进行中的驱逐任务，已发起的驱逐不会撤销，节点保持封锁
+func CancelDrainJob(id string) (*model.DrainJob, error) {
+	job := drainJobs.get(id)
+	if job == nil {
+		return nil, ErrDrainJobNotFound
+	}
+	select {
+	case <-job.done:
+		return nil, ErrDrainJobFinished
+	default:
+	}
+	job.cancel()
+	// 等待后台任务记录最终状态，正在进行的apiserver调用会随 context 取消返回
+	select {
+	case <-job.done:
+	case <-time.After(drainPollInterval):
+	}
+	snapshot := job.snapshot()
+	return &snapshot, nil
+}
+
+// add 登记新任务，同一节点只允许一个进行中的任务
+func (s *drainJobStore) add(job *drainJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.jobs {
+		if existing.snapshot().Node == job.job.Node && !existing.finished() {
+			return ErrDrainInProgress
+		}
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Errorf("生成任务ID失败: %w", err)
+	}
+	job.job.ID = hex.EncodeToString(id)
+	job.job.StartedAt = time.Now().Format(model.TimeFormat)
+	job.refreshCounts()
+	s.jobs[job.job.ID] = job
+	s.order = append(s.order, job.job.ID)
+
+	// 超出保留数量时移除最早结束的任务
+	for i := 0; len(s.order) > maxDrainJobs && i < len(s.order); {
+		if old := s.jobs[s.order[i]]; old.finished() {
+			delete(s.jobs, s.order[i])
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			continue
+		}
+		i++
+	}
+	return nil
+}
+
+func (s *drainJobStore) get(id string) *drainJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jobs[id]
+}
+
+func (j *drainJob) finished() bool {
+	select {
+	case <-j.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (j *drainJob) snapshot() model.DrainJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	snapshot := j.job
+	snapshot.Pods = append([]model.DrainJobPod(nil), j.job.Pods...)
+	return snapshot
+}
+
+// run 周期性地驱逐待处理的Pod并确认已驱逐的Pod被删除，直到全部完成、超时或被取消
+func (j *drainJob) run(ctx context.Context, clientset *kubernetes.Clientset) {
+	defer close(j.done)
+	defer j.cancel()
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		if j.step(ctx, clientset) {
+			j.finish(nil)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			j.finish(ctx.Err())
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// step 处理一轮驱逐，所有Pod都已结束时返回 true
+func (j *drainJob) step(ctx context.Context, clientset *kubernetes.Clientset) bool {
+	j.mu.Lock()
+	grace := j.job.Options.GracePeriodSeconds
+	pods := append([]model.DrainJobPod(nil), j.job.Pods...)
+	j.mu.Unlock()
+
+	for i, pod := range pods {
+		if ctx.Err() != nil {
+			return false
+		}
+		uid := j.uids[i]
+		switch pod.Status {
+		case model.DrainPodPending:
+			pods[i] = evictPod(ctx, clientset, pod, uid, grace)
+		case model.DrainPodEvicting:
+			current, err := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) || (err == nil && current.UID != uid) {
+				pods[i].Status, pods[i].Message = model.DrainPodEvicted, ""
+			}
+		}
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.job.Pods = pods
+	j.refreshCounts()
+	return j.job.Pending == 0
+}
+
+// evictPod 发起一次驱逐：PDB 不允许中断时保持待处理以便重试，Pod已不存在或已被替换时视为驱逐完成
+func evictPod(ctx context.Context, clientset *kubernetes.Clientset, pod model.DrainJobPod, uid types.UID, grace *int64) model.DrainJobPod {
+	pod.Attempts++
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name},
+		DeleteOptions: &metav1.DeleteOptions{
+			GracePeriodSeconds: grace,
+			Preconditions:      &metav1.Preconditions{UID: &uid},
+		},
+	}
+	err := clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, eviction)
+	switch {
+	case err == nil:
+		pod.Status, pod.Message = model.DrainPodEvicting, ""
+	case apierrors.IsNotFound(err):
+		pod.Status, pod.Message = model.DrainPodEvicted, ""
+	case apierrors.IsConflict(err):
+		pod.Status, pod.Message = model.DrainPodEvicted, "Pod已被替换"
+	case apierrors.IsTooManyRequests(err):
+		pod.Message = "PDB 暂不允许驱逐，稍后重试: " + err.Error()
+	case ctx.Err() != nil:
+		pod.Attempts--
+	default:
+		pod.Status, pod.Message = model.DrainPodFailed, err.Error()
+	}
+	return pod
+}
+
+// finish 记录任务的最终状态，cause 为超时或取消时未完成的Pod分别标记为失败或保持原状态
+func (j *drainJob) finish(cause error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	switch {
+	case errors.Is(cause, context.Canceled):
+		j.job.Status = model.DrainJobCancelled
+		j.job.Message = "任务已取消，已发起的驱逐不会撤销"
+	case errors.Is(cause, context.DeadlineExceeded):
+		for i := range j.job.Pods {
+			if p := &j.job.Pods[i]; p.Status == model.DrainPodPending || p.Status == model.DrainPodEvicting {
+				p.Status = model.DrainPodFailed
+				p.Message = strings.TrimSpace("驱逐超时 " + p.Message)
+			}
+		}
+		j.job.Status = model.DrainJobFailed
+		j.job.Message = "驱逐超时"
+	default:
+		j.job.Status = model.DrainJobSucceeded
+	}
+	j.refreshCounts()
+	if j.job.Status == model.DrainJobSucceeded && j.job.Failed > 0 {
+		j.job.Status = model.DrainJobFailed
+		j.job.Message = fmt.Sprintf("%d 个Pod驱逐失败", j.job.Failed)
+	}
+	j.job.FinishedAt = time.Now().Format(model.TimeFormat)
+
+	zap.L().Info("节点驱逐任务结束",
+		zap.String("jobId", j.job.ID),
+		zap.String("node", j.job.Node),
+		zap.String("status", j.job.Status),
+		zap.Int("evicted", j.job.Evicted),
+		zap.Int("failed", j.job.Failed),
+	)
+}
+
+// refreshCounts 按Pod状态重新统计进度，调用方需持有锁
+func (j *drainJob) refreshCounts() {
+	j.job.Total = len(j.job.Pods)
+	j.job.Evicted, j.job.Pending, j.job.Failed, j.job.Skipped = 0, 0, 0, 0
+	for _, p := range j.job.Pods {
+		switch p.Status {
+		case model.DrainPodEvicted:
+			j.job.Evicted++
+		case model.DrainPodPending, model.DrainPodEvicting:
+			j.job.Pending++
+		case model.DrainPodFailed:
+			j.job.Failed++
+		case model.DrainPodSkipped:
+			j.job.Skipped++
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/nick0323/K8sVision/model"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Service 诊断级别
+const (
+	DiagnosticError   = "Error"
+	DiagnosticWarning = corev1.EventTypeWarning
+)
+
+// Service 诊断代码
+const (
+	DiagNoSelector           = "NoSelector"
+	DiagNoMatchingPods       = "NoMatchingPods"
+	DiagNoReadyPods          = "NoReadyPods"
+	DiagTargetPortNotExposed = "TargetPortNotExposed"
+	DiagNoReadyEndpoints     = "NoReadyEndpoints"
+	DiagBackendLookupFailed  = "BackendLookupFailed"
+)
+
+// serviceBackends Service 后端分析结果
+type serviceBackends struct {
+	slices      []model.EndpointSliceInfo
+	ready       int
+	notReady    int
+	matchedPods int
+	readyPods   int
+	diagnostics []model.ServiceDiagnostic
+}
+
+// ListServicesWithoutEndpoints 找出没有就绪端点的 Service（ExternalName 除外），并给出可能的原因
+func ListServicesWithoutEndpoints(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]model.ServiceEndpointIssue, error) {
+	svcList, err := clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sliceList, err := clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	podList, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	slicesByService := make(map[string][]discoveryv1.EndpointSlice)
+	for _, slice := range sliceList.Items {
+		if svcName := slice.Labels[discoveryv1.LabelServiceName]; svcName != "" {
+			key := slice.Namespace + "/" + svcName
+			slicesByService[key] = append(slicesByService[key], slice)
+		}
+	}
+	podsByNamespace := make(map[string][]corev1.Pod)
+	for _, pod := range podList.Items {
+		podsByNamespace[pod.Namespace] = append(podsByNamespace[pod.Namespace], pod)
+	}
+
+	result := make([]model.ServiceEndpointIssue, 0)
+	for i := range svcList.Items {
+		svc := &svcList.Items[i]
+		if svc.Spec.Type == corev1.ServiceTypeExternalName {
+			continue
+		}
+		backends := analyzeServiceBackends(svc, podsByNamespace[svc.Namespace], slicesByService[svc.Namespace+"/"+svc.Name])
+		if backends.ready > 0 {
+			continue
+		}
+		result = append(result, model.ServiceEndpointIssue{
+			Namespace:         svc.Namespace,
+			Name:              svc.Name,
+			Type:              string(svc.Spec.Type),
+			Selector:          labels.FormatLabels(svc.Spec.Selector),
+			MatchedPods:       backends.matchedPods,
+			ReadyPods:         backends.readyPods,
+			NotReadyEndpoints: backends.notReady,
+			Diagnostics:       backends.diagnostics,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Namespace != result[j].Namespace {
+			return result[i].Namespace < result[j].Namespace
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// getServiceBackends 查询 Service 所在命名空间的Pod及其 EndpointSlice 并进行分析
+func getServiceBackends(ctx context.Context, clientset *kubernetes.Clientset, svc *corev1.Service) (*serviceBackends, error) {
+	sliceList, err := clientset.DiscoveryV1().EndpointSlices(svc.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + svc.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var pods []corev1.Pod
+	if len(svc.Spec.Selector) > 0 {
+		podList, err := clientset.CoreV1().Pods(svc.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		pods = podList.Items
+	}
+	return analyzeServiceBackends(svc, pods, sliceList.Items), nil
+}
+
+// analyzeServiceBackends 汇总 EndpointSlice 中的端点，并结合 selector 选中的Pod诊断没有后端的原因
+// pods 可以包含未被选中的Pod，这里会按 selector 重新过滤
+func analyzeServiceBackends(svc *corev1.Service, pods []corev1.Pod, slices []discoveryv1.EndpointSlice) *serviceBackends {
+	result := &serviceBackends{
+		slices:      make([]model.EndpointSliceInfo, 0, len(slices)),
+		diagnostics: make([]model.ServiceDiagnostic, 0),
+	}
+	sort.Slice(slices, func(i, j int) bool { return slices[i].Name < slices[j].Name })
+	for i := range slices {
+		info := endpointSliceInfo(&slices[i])
+		result.ready += info.Ready
+		result.notReady += info.NotReady
+		result.slices = append(result.slices, info)
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return result
+	}
+	add := func(level, code, format string, args ...interface{}) {
+		result.diagnostics = append(result.diagnostics, model.ServiceDiagnostic{
+			Level:   level,
+			Code:    code,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if len(svc.Spec.Selector) == 0 {
+		if result.ready == 0 {
+			add(DiagnosticWarning, DiagNoSelector, "Service 未设置 selector，且没有手动维护的就绪端点")
+		}
+		return result
+	}
+
+	selector := labels.SelectorFromSet(svc.Spec.Selector)
+	matched := make([]*corev1.Pod, 0)
+	for i := range pods {
+		pod := &pods[i]
+		// 与 EndpointSlice 控制器一致，忽略已结束的Pod
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			matched = append(matched, pod)
+			if isPodReady(pod) {
+				result.readyPods++
+			}
+		}
+	}
+	result.matchedPods = len(matched)
+
+	switch {
+	case len(matched) == 0:
+		add(DiagnosticError, DiagNoMatchingPods, "selector %s 没有匹配任何Pod", labels.FormatLabels(svc.Spec.Selector))
+	case result.readyPods == 0:
+		add(DiagnosticError, DiagNoReadyPods, "selector 匹配了 %d 个Pod，但没有就绪的Pod", len(matched))
+	}
+
+	if len(matched) > 0 {
+		for _, port := range svc.Spec.Ports {
+			if exposed, named := targetPortExposed(matched, port); !exposed {
+				level := DiagnosticWarning
+				if named {
+					// 命名端口无法解析时该端口不会出现在端点中
+					level = DiagnosticError
+				}
+				add(level, DiagTargetPortNotExposed, "端口 %s 的 targetPort %s 未在任何匹配Pod的容器端口中声明",
+					servicePortName(port), port.TargetPort.String())
+			}
+		}
+	}
+
+	// 其他诊断无法解释时才提示端点未同步
+	if result.ready == 0 && result.readyPods > 0 && len(result.diagnostics) == 0 {
+		add(DiagnosticWarning, DiagNoReadyEndpoints, "存在就绪的Pod，但 EndpointSlice 中没有就绪端点，可能尚未同步")
+	}
+	return result
+}
+
+func endpointSliceInfo(slice *discoveryv1.EndpointSlice) model.EndpointSliceInfo {
+	info := model.EndpointSliceInfo{
+		Name:        slice.Name,
+		AddressType: string(slice.AddressType),
+		Ports:       make([]string, 0, len(slice.Ports)),
+		Endpoints:   make([]model.EndpointAddress, 0, len(slice.Endpoints)),
+	}
+	for _, p := range slice.Ports {
+		protocol := corev1.ProtocolTCP
+		if p.Protocol != nil {
+			protocol = *p.Protocol
+		}
+		port := fmt.Sprintf("%d/%s", SafeInt32Ptr(p.Port, 0), protocol)
+		if name := SafeStringPtr(p.Name, ""); name != "" {
+			port = name + ":" + port
+		}
+		info.Ports = append(info.Ports, port)
+	}
+	for _, ep := range slice.Endpoints {
+		// 条件为空时按 EndpointSlice 约定视为就绪
+		ready := SafeBoolPtr(ep.Conditions.Ready, true)
+		addr := model.EndpointAddress{
+			Addresses:   ep.Addresses,
+			Ready:       ready,
+			Serving:     SafeBoolPtr(ep.Conditions.Serving, ready),
+			Terminating: SafeBoolPtr(ep.Conditions.Terminating, false),
+			NodeName:    SafeStringPtr(ep.NodeName, ""),
+			Zone:        SafeStringPtr(ep.Zone, ""),
+		}
+		if ep.TargetRef != nil && ep.TargetRef.Kind == "Pod" {
+			addr.TargetPod = ep.TargetRef.Name
+		}
+		if ready {
+			info.Ready++
+		} else {
+			info.NotReady++
+		}
+		info.Endpoints = append(info.Endpoints, addr)
+	}
+	return info
+}
+
+// targetPortExposed 判断 targetPort 是否在任一Pod的容器端口中声明，named 表示 targetPort 为命名端口
+func targetPortExposed(pods []*corev1.Pod, port corev1.ServicePort) (exposed, named bool) {
+	target := port.TargetPort
+	if target.Type == intstr.Int && target.IntVal == 0 {
+		// 未设置 targetPort 时默认与 port 相同
+		target = intstr.FromInt32(port.Port)
+	}
+	named = target.Type == intstr.String
+	protocol := protocolOrTCP(port.Protocol)
+	for _, pod := range pods {
+		for _, c := range pod.Spec.Containers {
+			for _, cp := range c.Ports {
+				if protocolOrTCP(cp.Protocol) != protocol {
+					continue
+				}
+				if (named && cp.Name == target.StrVal) || (!named && cp.ContainerPort == target.IntVal) {
+					return true, named
+				}
+			}
+		}
+	}
+	return false, named
+}
+
+func servicePortName(port corev1.ServicePort) string {
+	s := fmt.Sprintf("%d/%s", port.Port, protocolOrTCP(port.Protocol))
+	if port.Name != "" {
+		s = port.Name + "(" + s + ")"
+	}
+	return s
+}
+
+func protocolOrTCP(p corev1.Protocol) corev1.Protocol {
+	if p == "" {
+		return corev1.ProtocolTCP
+	}
+	return p
+}
+
+// isPodReady 判断Pod的 Ready 条件是否为 True
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func servicePorts(svc *corev1.Service) []model.ServicePort {
+	result := make([]model.ServicePort, 0, len(svc.Spec.Ports))
+	for _, p := range svc.Spec.Ports {
+		result = append(result, model.ServicePort{
+			Name:       p.Name,
+			Protocol:   string(p.Protocol),
+			Port:       p.Port,
+			TargetPort: p.TargetPort.String(),
+			NodePort:   p.NodePort,
+		})
+	}
+	return result
+}
+
+// formatServicePorts 以 port/protocol 形式列出 Service 端口
+func formatServicePorts(svc *corev1.Service) []string {
+	ports := make([]string, 0, len(svc.Spec.Ports))
+	for _, p := range svc.Spec.Ports {
+		ports = append(ports, fmt.Sprintf("%d/%s", p.Port, p.Protocol))
+	}
+	return ports
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/nick0323/K8sVision/model"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 )
 
 func ListEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]model.EventStatus, error) {
@@ -30,3 +32,20 @@ func ListEvents(ctx context.Context, clientset *kubernetes.Clientset, namespace
 	}
 	return result, nil
 }
+
+// NewEventInformer 以服务自身身份创建监听全集群事件的Informer，供事件归档使用
+// 监听是长连接，因此使用单独的客户端并取消请求超时
+func NewEventInformer() (cache.SharedIndexInformer, error) {
+	config, err := GetK8sConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Timeout = 0
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	factory := informers.NewSharedInformerFactory(clientset, 0)
+	return factory.Core().V1().Events().Informer(), nil
+}
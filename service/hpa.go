@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nick0323/K8sVision/model"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func ListHPAs(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]model.HPAStatus, error) {
+	hpaList, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.HPAStatus, 0, len(hpaList.Items))
+	for i := range hpaList.Items {
+		result = append(result, hpaStatus(&hpaList.Items[i]))
+	}
+	return result, nil
+}
+
+// FindHPAForTarget 查找以指定工作负载为伸缩目标的HPA，不存在时返回 nil
+func FindHPAForTarget(ctx context.Context, clientset *kubernetes.Clientset, namespace, kind, name string) (*model.HPAStatus, error) {
+	hpaList, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range hpaList.Items {
+		ref := hpaList.Items[i].Spec.ScaleTargetRef
+		if ref.Kind == kind && ref.Name == name {
+			status := hpaStatus(&hpaList.Items[i])
+			return &status, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetHPADetail 获取HPA详情，包括各指标的当前值与目标值以及伸缩目标的当前状态
+func GetHPADetail(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.HPADetail, error) {
+	hpa, err := clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.HPADetail{}, err
+	}
+
+	conditions := make([]model.ResourceCondition, 0, len(hpa.Status.Conditions))
+	for _, c := range hpa.Status.Conditions {
+		conditions = append(conditions, model.ResourceCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: formatConditionTime(c.LastTransitionTime),
+		})
+	}
+	lastScaleTime := ""
+	if hpa.Status.LastScaleTime != nil {
+		lastScaleTime = formatConditionTime(*hpa.Status.LastScaleTime)
+	}
+
+	return model.HPADetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Namespace: hpa.Namespace,
+			Name:      hpa.Name,
+			Status:    hpaHealth(hpa),
+			BaseMetadata: model.BaseMetadata{
+				Labels:      hpa.Labels,
+				Annotations: hpa.Annotations,
+			},
+		},
+		MinReplicas:     SafeInt32Ptr(hpa.Spec.MinReplicas, 1),
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+		LastScaleTime:   lastScaleTime,
+		Metrics:         hpaMetrics(hpa),
+		Conditions:      conditions,
+		ScaleTarget:     getScaleTarget(ctx, clientset, hpa.Namespace, hpa.Spec.ScaleTargetRef),
+	}, nil
+}
+
+func hpaStatus(hpa *autoscalingv2.HorizontalPodAutoscaler) model.HPAStatus {
+	metrics := hpaMetrics(hpa)
+	summary := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		current := m.Current
+		if current == "" {
+			current = "<unknown>"
+		}
+		summary = append(summary, fmt.Sprintf("%s: %s/%s", m.Name, current, m.Target))
+	}
+	return model.HPAStatus{
+		Namespace:       hpa.Namespace,
+		Name:            hpa.Name,
+		Target:          hpa.Spec.ScaleTargetRef.Kind + "/" + hpa.Spec.ScaleTargetRef.Name,
+		MinReplicas:     SafeInt32Ptr(hpa.Spec.MinReplicas, 1),
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+		Metrics:         summary,
+		Status:          hpaHealth(hpa),
+	}
+}
+
+// hpaHealth 根据 AbleToScale 与 ScalingActive 条件判断HPA是否正常工作
+func hpaHealth(hpa *autoscalingv2.HorizontalPodAutoscaler) string {
+	if len(hpa.Status.Conditions) == 0 {
+		return model.StatusUnknown
+	}
+	for _, c := range hpa.Status.Conditions {
+		switch c.Type {
+		case autoscalingv2.AbleToScale, autoscalingv2.ScalingActive:
+			if c.Status == corev1.ConditionFalse {
+				return model.StatusAbnormal
+			}
+		}
+	}
+	return model.StatusHealthy
+}
+
+// hpaMetrics 按规格中的指标顺序输出目标值，并从状态中按类型和名称匹配当前值
+func hpaMetrics(hpa *autoscalingv2.HorizontalPodAutoscaler) []model.HPAMetric {
+	result := make([]model.HPAMetric, 0, len(hpa.Spec.Metrics))
+	for _, spec := range hpa.Spec.Metrics {
+		metric := model.HPAMetric{Type: string(spec.Type)}
+		var target autoscalingv2.MetricTarget
+		switch spec.Type {
+		case autoscalingv2.ResourceMetricSourceType:
+			if spec.Resource == nil {
+				continue
+			}
+			metric.Name = string(spec.Resource.Name)
+			target = spec.Resource.Target
+		case autoscalingv2.ContainerResourceMetricSourceType:
+			if spec.ContainerResource == nil {
+				continue
+			}
+			metric.Name = spec.ContainerResource.Container + "/" + string(spec.ContainerResource.Name)
+			target = spec.ContainerResource.Target
+		case autoscalingv2.PodsMetricSourceType:
+			if spec.Pods == nil {
+				continue
+			}
+			metric.Name = spec.Pods.Metric.Name
+			target = spec.Pods.Target
+		case autoscalingv2.ObjectMetricSourceType:
+			if spec.Object == nil {
+				continue
+			}
+			ref := spec.Object.DescribedObject
+			metric.Name = ref.Kind + "/" + ref.Name + ":" + spec.Object.Metric.Name
+			target = spec.Object.Target
+		case autoscalingv2.ExternalMetricSourceType:
+			if spec.External == nil {
+				continue
+			}
+			metric.Name = spec.External.Metric.Name
+			target = spec.External.Target
+		default:
+			continue
+		}
+		metric.Target = formatMetricTarget(target)
+		for _, status := range hpa.Status.CurrentMetrics {
+			if current, ok := currentMetricValue(status, spec.Type, metric.Name); ok {
+				metric.Current = current
+				break
+			}
+		}
+		result = append(result, metric)
+	}
+	return result
+}
+
+// currentMetricValue 当状态与指定类型和名称匹配时返回格式化后的当前值
+func currentMetricValue(status autoscalingv2.MetricStatus, metricType autoscalingv2.MetricSourceType, name string) (string, bool) {
+	if status.Type != metricType {
+		return "", false
+	}
+	switch metricType {
+	case autoscalingv2.ResourceMetricSourceType:
+		if status.Resource != nil && string(status.Resource.Name) == name {
+			return formatMetricValue(status.Resource.Current), true
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if c := status.ContainerResource; c != nil && c.Container+"/"+string(c.Name) == name {
+			return formatMetricValue(c.Current), true
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if status.Pods != nil && status.Pods.Metric.Name == name {
+			return formatMetricValue(status.Pods.Current), true
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if o := status.Object; o != nil && o.DescribedObject.Kind+"/"+o.DescribedObject.Name+":"+o.Metric.Name == name {
+			return formatMetricValue(o.Current), true
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if status.External != nil && status.External.Metric.Name == name {
+			return formatMetricValue(status.External.Current), true
+		}
+	}
+	return "", false
+}
+
+// formatMetricTarget 百分比表示平均利用率，(avg) 表示按Pod平均的值
+func formatMetricTarget(t autoscalingv2.MetricTarget) string {
+	switch {
+	case t.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *t.AverageUtilization)
+	case t.AverageValue != nil:
+		return t.AverageValue.String() + " (avg)"
+	case t.Value != nil:
+		return t.Value.String()
+	}
+	return ""
+}
+
+func formatMetricValue(v autoscalingv2.MetricValueStatus) string {
+	switch {
+	case v.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *v.AverageUtilization)
+	case v.AverageValue != nil:
+		return v.AverageValue.String() + " (avg)"
+	case v.Value != nil:
+		return v.Value.String()
+	}
+	return ""
+}
+
+// getScaleTarget 查询伸缩目标的副本状态，目前支持 Deployment、StatefulSet 和 ReplicaSet。
+// 查询失败时记录错误，NotFound 另行标记，以便区分目标已删除与无权访问等情况
+func getScaleTarget(ctx context.Context, clientset *kubernetes.Clientset, namespace string, ref autoscalingv2.CrossVersionObjectReference) model.HPAScaleTarget {
+	target := model.HPAScaleTarget{
+		Kind:       ref.Kind,
+		Name:       ref.Name,
+		APIVersion: ref.APIVersion,
+		Status:     model.StatusUnknown,
+	}
+
+	var replicas, ready int32
+	var err error
+	switch ref.Kind {
+	case WorkloadDeployment:
+		var dep *appsv1.Deployment
+		if dep, err = clientset.AppsV1().Deployments(namespace).Get(ctx, ref.Name, metav1.GetOptions{}); err == nil {
+			replicas, ready = SafeInt32Ptr(dep.Spec.Replicas, 1), dep.Status.ReadyReplicas
+		}
+	case WorkloadStatefulSet:
+		var sts *appsv1.StatefulSet
+		if sts, err = clientset.AppsV1().StatefulSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{}); err == nil {
+			replicas, ready = SafeInt32Ptr(sts.Spec.Replicas, 1), sts.Status.ReadyReplicas
+		}
+	case WorkloadReplicaSet:
+		var rs *appsv1.ReplicaSet
+		if rs, err = clientset.AppsV1().ReplicaSets(namespace).Get(ctx, ref.Name, metav1.GetOptions{}); err == nil {
+			replicas, ready = SafeInt32Ptr(rs.Spec.Replicas, 1), rs.Status.ReadyReplicas
+		}
+	default:
+		target.Error = fmt.Sprintf("不支持的伸缩目标类型 %s", ref.Kind)
+		return target
+	}
+	if err != nil {
+		target.NotFound = apierrors.IsNotFound(err)
+		target.Error = err.Error()
+		return target
+	}
+
+	target.Found = true
+	target.Replicas = replicas
+	target.Ready = ready
+	target.Status = GetWorkloadStatus(ready, replicas)
+	return target
+}
@@ -2,15 +2,44 @@ package service
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/nick0323/K8sVision/model"
 
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+// Ingress 诊断代码
+const (
+	DiagBackendServiceNotFound = "BackendServiceNotFound"
+	DiagBackendPortNotFound    = "BackendPortNotFound"
+	DiagBackendNoEndpoints     = "BackendNoEndpoints"
+	DiagBackendUnchecked       = "BackendUnchecked"
+	DiagTLSSecretNotFound      = "TLSSecretNotFound"
+	DiagTLSSecretUnreadable    = "TLSSecretUnreadable"
+	DiagTLSSecretInvalid       = "TLSSecretInvalid"
+	DiagTLSCertExpired         = "TLSCertExpired"
+	DiagTLSCertExpiringSoon    = "TLSCertExpiringSoon"
+	DiagTLSHostNotCovered      = "TLSHostNotCovered"
+	DiagTLSHostNotConfigured   = "TLSHostNotConfigured"
+)
+
+// 证书剩余有效天数少于该值时给出警告
+const tlsExpiryWarningDays = 30
+
 func ListIngresses(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]model.IngressStatus, error) {
 	ingresses, err := clientset.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -18,41 +47,435 @@ func ListIngresses(ctx context.Context, clientset *kubernetes.Clientset, namespa
 		return nil, err
 	}
 	result := make([]model.IngressStatus, 0, len(ingresses.Items))
-	for _, ing := range ingresses.Items {
-		hosts := make([]string, 0)
-		paths := make([]string, 0)
-		targetSvcs := make([]string, 0)
-		for _, rule := range ing.Spec.Rules {
-			hosts = append(hosts, rule.Host)
-			if rule.HTTP != nil {
-				for _, path := range rule.HTTP.Paths {
-					paths = append(paths, path.Path)
-					targetSvcs = append(targetSvcs, path.Backend.Service.Name)
-				}
-			}
-		}
-		address := ""
-		if len(ing.Status.LoadBalancer.Ingress) > 0 {
-			address = ing.Status.LoadBalancer.Ingress[0].IP
-			if address == "" {
-				address = ing.Status.LoadBalancer.Ingress[0].Hostname
-			}
-		}
-		class := ""
-		if ing.Spec.IngressClassName != nil {
-			class = *ing.Spec.IngressClassName
+	for i := range ingresses.Items {
+		ing := &ingresses.Items[i]
+		hosts, paths, targetSvcs := ingressRuleSummary(ing)
+		address := ingressAddress(ing)
+		status := model.StatusActive
+		if address == "" {
+			status = model.StatusPending
 		}
 		result = append(result, model.IngressStatus{
 			Namespace:     ing.Namespace,
 			Name:          ing.Name,
 			Hosts:         hosts,
 			Address:       address,
-			Ports:         []string{},
-			Class:         class,
-			Status:        "Active",
+			Ports:         ingressPorts(ing),
+			Class:         ingressClass(ing),
+			Status:        status,
 			Path:          paths,
 			TargetService: targetSvcs,
 		})
 	}
 	return result, nil
 }
+
+// GetIngressDetail 获取Ingress详情：检查每个后端 Service 是否存在、端口是否存在及是否有就绪端点，
+// 并解析 TLS secret 中的证书，检查过期时间以及主机是否被证书覆盖
+func GetIngressDetail(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.IngressDetail, error) {
+	ing, err := clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.IngressDetail{}, err
+	}
+
+	hosts, paths, targetSvcs := ingressRuleSummary(ing)
+	address := ingressAddress(ing)
+	detail := model.IngressDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Namespace: ing.Namespace,
+			Name:      ing.Name,
+			BaseMetadata: model.BaseMetadata{
+				Labels:      ing.Labels,
+				Annotations: ing.Annotations,
+			},
+		},
+		Hosts:         hosts,
+		Address:       address,
+		Ports:         ingressPorts(ing),
+		Class:         ingressClass(ing),
+		Path:          paths,
+		TargetService: targetSvcs,
+		Age:           FormatAge(ing.CreationTimestamp.Time),
+		Rules:         make([]model.IngressRule, 0, len(ing.Spec.Rules)),
+		TLS:           make([]model.IngressTLS, 0, len(ing.Spec.TLS)),
+		Diagnostics:   make([]model.ServiceDiagnostic, 0),
+	}
+	add := func(level, code, format string, args ...interface{}) {
+		detail.Diagnostics = append(detail.Diagnostics, model.ServiceDiagnostic{
+			Level:   level,
+			Code:    code,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	checker := &ingressBackendChecker{
+		ctx:       ctx,
+		clientset: clientset,
+		namespace: ing.Namespace,
+		services:  make(map[string]*ingressServiceInfo),
+	}
+	checkBackend := func(where string, b networkingv1.IngressBackend) model.IngressBackend {
+		backend, level, code := checker.check(b)
+		if code != "" {
+			add(level, code, "%s: %s", where, backend.Message)
+		}
+		return backend
+	}
+
+	if ing.Spec.DefaultBackend != nil {
+		backend := checkBackend("默认后端", *ing.Spec.DefaultBackend)
+		detail.DefaultBackend = &backend
+	}
+	for _, rule := range ing.Spec.Rules {
+		r := model.IngressRule{Host: rule.Host, Paths: make([]model.IngressPath, 0)}
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		if rule.HTTP != nil {
+			for _, p := range rule.HTTP.Paths {
+				pathType := ""
+				if p.PathType != nil {
+					pathType = string(*p.PathType)
+				}
+				r.Paths = append(r.Paths, model.IngressPath{
+					Path:     p.Path,
+					PathType: pathType,
+					Backend:  checkBackend(host+p.Path, p.Backend),
+				})
+			}
+		}
+		detail.Rules = append(detail.Rules, r)
+	}
+
+	now := time.Now()
+	for _, t := range ing.Spec.TLS {
+		tls := model.IngressTLS{Hosts: t.Hosts, SecretName: t.SecretName}
+		if tls.Hosts == nil {
+			tls.Hosts = []string{}
+		}
+		if t.SecretName == "" {
+			// 未指定 secret 时由 Ingress 控制器使用默认证书，无法在此检查
+			detail.TLS = append(detail.TLS, tls)
+			continue
+		}
+		certs, err := loadTLSSecretCertificates(ctx, clientset, ing.Namespace, t.SecretName)
+		if err != nil {
+			tls.Error = err.Error()
+			switch {
+			case apierrors.IsNotFound(err):
+				add(DiagnosticError, DiagTLSSecretNotFound, "TLS secret %s 不存在", t.SecretName)
+			case apierrors.IsForbidden(err):
+				add(DiagnosticWarning, DiagTLSSecretUnreadable, "无权读取 TLS secret %s，未检查证书", t.SecretName)
+			default:
+				add(DiagnosticError, DiagTLSSecretInvalid, "TLS secret %s 无效: %v", t.SecretName, err)
+			}
+			detail.TLS = append(detail.TLS, tls)
+			continue
+		}
+
+		leaf := certs[0]
+		tls.ChainLength = len(certs)
+		tls.Certificate = tlsCertificateInfo(leaf, now)
+		switch {
+		case tls.Certificate.Expired:
+			add(DiagnosticError, DiagTLSCertExpired, "TLS secret %s 的证书已于 %s 过期", t.SecretName, tls.Certificate.NotAfter)
+		case tls.Certificate.DaysRemaining < tlsExpiryWarningDays:
+			add(DiagnosticWarning, DiagTLSCertExpiringSoon, "TLS secret %s 的证书将在 %d 天后过期", t.SecretName, tls.Certificate.DaysRemaining)
+		}
+		for _, h := range t.Hosts {
+			if err := leaf.VerifyHostname(h); err != nil {
+				add(DiagnosticError, DiagTLSHostNotCovered, "主机 %s 不在 TLS secret %s 的证书范围内", h, t.SecretName)
+			}
+		}
+		detail.TLS = append(detail.TLS, tls)
+	}
+
+	for _, h := range ingressHostsWithoutTLS(ing) {
+		add(DiagnosticWarning, DiagTLSHostNotConfigured, "主机 %s 未配置 TLS，只能通过 HTTP 访问", h)
+	}
+
+	detail.Status = model.StatusActive
+	if address == "" {
+		detail.Status = model.StatusPending
+	}
+	for _, d := range detail.Diagnostics {
+		if d.Level == DiagnosticError {
+			detail.Status = model.StatusAbnormal
+			break
+		}
+	}
+	return detail, nil
+}
+
+// ingressServiceInfo 一次详情查询中缓存的 Service 及其 EndpointSlice
+type ingressServiceInfo struct {
+	svc    *corev1.Service
+	slices []discoveryv1.EndpointSlice
+	err    error
+}
+
+// ingressBackendChecker 检查 Ingress 后端，同一 Service 只查询一次
+type ingressBackendChecker struct {
+	ctx       context.Context
+	clientset *kubernetes.Clientset
+	namespace string
+	services  map[string]*ingressServiceInfo
+}
+
+func (c *ingressBackendChecker) service(name string) *ingressServiceInfo {
+	if info, ok := c.services[name]; ok {
+		return info
+	}
+	info := &ingressServiceInfo{}
+	c.services[name] = info
+	svc, err := c.clientset.CoreV1().Services(c.namespace).Get(c.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		info.err = err
+		return info
+	}
+	info.svc = svc
+	sliceList, err := c.clientset.DiscoveryV1().EndpointSlices(c.namespace).List(c.ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + name,
+	})
+	if err != nil {
+		info.err = err
+		return info
+	}
+	info.slices = sliceList.Items
+	return info
+}
+
+// check 返回后端的检查结果，存在问题时同时返回诊断级别和代码
+func (c *ingressBackendChecker) check(b networkingv1.IngressBackend) (model.IngressBackend, string, string) {
+	backend := model.IngressBackend{Status: model.StatusUnknown}
+	if b.Resource != nil {
+		backend.Resource = b.Resource.Kind + "/" + b.Resource.Name
+		return backend, "", ""
+	}
+	if b.Service == nil {
+		return backend, "", ""
+	}
+
+	backend.Service = b.Service.Name
+	backend.Port = ingressServicePort(b.Service.Port)
+	info := c.service(b.Service.Name)
+	if info.svc == nil {
+		if apierrors.IsNotFound(info.err) {
+			backend.Status = model.StatusAbnormal
+			backend.Message = fmt.Sprintf("Service %s 不存在", b.Service.Name)
+			return backend, DiagnosticError, DiagBackendServiceNotFound
+		}
+		backend.Message = fmt.Sprintf("无法检查 Service %s: %v", b.Service.Name, info.err)
+		return backend, DiagnosticWarning, DiagBackendUnchecked
+	}
+	backend.ServiceExists = true
+
+	svc := info.svc
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		// ExternalName 由 DNS 解析，没有端口和端点可检查
+		backend.PortExists = true
+		backend.Status = model.StatusHealthy
+		backend.Message = "ExternalName: " + svc.Spec.ExternalName
+		return backend, "", ""
+	}
+
+	var svcPort *corev1.ServicePort
+	for i := range svc.Spec.Ports {
+		p := &svc.Spec.Ports[i]
+		if (b.Service.Port.Name != "" && p.Name == b.Service.Port.Name) ||
+			(b.Service.Port.Name == "" && p.Port == b.Service.Port.Number) {
+			svcPort = p
+			break
+		}
+	}
+	if svcPort == nil {
+		backend.Status = model.StatusAbnormal
+		backend.Message = fmt.Sprintf("Service %s 没有端口 %s", svc.Name, backend.Port)
+		return backend, DiagnosticError, DiagBackendPortNotFound
+	}
+	backend.PortExists = true
+
+	if info.err != nil {
+		backend.Message = fmt.Sprintf("无法查询 Service %s 的端点: %v", svc.Name, info.err)
+		return backend, DiagnosticWarning, DiagBackendUnchecked
+	}
+	// EndpointSlice 按 Service 端口名称记录端口，只统计包含该端口的切片
+	for i := range info.slices {
+		slice := &info.slices[i]
+		for _, p := range slice.Ports {
+			if SafeStringPtr(p.Name, "") == svcPort.Name {
+				backend.ReadyEndpoints += endpointSliceInfo(slice).Ready
+				break
+			}
+		}
+	}
+	if backend.ReadyEndpoints == 0 {
+		backend.Status = model.StatusAbnormal
+		backend.Message = fmt.Sprintf("Service %s 端口 %s 没有就绪端点", svc.Name, servicePortName(*svcPort))
+		return backend, DiagnosticError, DiagBackendNoEndpoints
+	}
+	backend.Status = model.StatusHealthy
+	return backend, "", ""
+}
+
+// loadTLSSecretCertificates 读取 TLS secret 中 tls.crt 的证书链，第一个为叶子证书
+func loadTLSSecretCertificates(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) ([]*x509.Certificate, error) {
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	data, ok := secret.Data[corev1.TLSCertKey]
+	if !ok || len(data) == 0 {
+		return nil, fmt.Errorf("secret 中缺少 %s", corev1.TLSCertKey)
+	}
+	certs := make([]*x509.Certificate, 0, 1)
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("解析证书失败: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("未找到 PEM 格式的证书")
+	}
+	return certs, nil
+}
+
+func tlsCertificateInfo(cert *x509.Certificate, now time.Time) *model.TLSCertificate {
+	info := &model.TLSCertificate{
+		Subject:       cert.Subject.String(),
+		Issuer:        cert.Issuer.String(),
+		DNSNames:      cert.DNSNames,
+		IPAddresses:   make([]string, 0, len(cert.IPAddresses)),
+		NotBefore:     cert.NotBefore.Local().Format(model.TimeFormat),
+		NotAfter:      cert.NotAfter.Local().Format(model.TimeFormat),
+		DaysRemaining: int(cert.NotAfter.Sub(now).Hours() / 24),
+		Expired:       now.After(cert.NotAfter),
+	}
+	if info.DNSNames == nil {
+		info.DNSNames = []string{}
+	}
+	for _, ip := range cert.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+	return info
+}
+
+// ingressHostsWithoutTLS 配置了 TLS 时，找出规则中未出现在任何 TLS hosts 里的主机；
+// TLS 条目未列出主机时由控制器决定证书，不做检查
+func ingressHostsWithoutTLS(ing *networkingv1.Ingress) []string {
+	if len(ing.Spec.TLS) == 0 {
+		return nil
+	}
+	for _, t := range ing.Spec.TLS {
+		if len(t.Hosts) == 0 {
+			return nil
+		}
+	}
+	result := make([]string, 0)
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host == "" || ContainsString(result, rule.Host) {
+			continue
+		}
+		covered := false
+		for _, t := range ing.Spec.TLS {
+			for _, h := range t.Hosts {
+				if hostMatches(h, rule.Host) {
+					covered = true
+					break
+				}
+			}
+			if covered {
+				break
+			}
+		}
+		if !covered {
+			result = append(result, rule.Host)
+		}
+	}
+	return result
+}
+
+// hostMatches 判断主机是否匹配 pattern，pattern 可以是 *.example.com 形式的通配符（只匹配一级）
+func hostMatches(pattern, host string) bool {
+	if strings.EqualFold(pattern, host) {
+		return true
+	}
+	if !strings.HasPrefix(pattern, "*.") {
+		return false
+	}
+	i := strings.Index(host, ".")
+	return i > 0 && strings.EqualFold(host[i:], pattern[1:])
+}
+
+func ingressRuleSummary(ing *networkingv1.Ingress) (hosts, paths, targetSvcs []string) {
+	hosts = make([]string, 0, len(ing.Spec.Rules))
+	paths = make([]string, 0)
+	targetSvcs = make([]string, 0)
+	for _, rule := range ing.Spec.Rules {
+		hosts = append(hosts, rule.Host)
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Path != "" {
+				paths = append(paths, path.Path)
+			} else {
+				paths = append(paths, "/")
+			}
+			switch {
+			case path.Backend.Service != nil:
+				targetSvcs = append(targetSvcs, path.Backend.Service.Name)
+			case path.Backend.Resource != nil:
+				targetSvcs = append(targetSvcs, path.Backend.Resource.Kind+"/"+path.Backend.Resource.Name)
+			}
+		}
+	}
+	return hosts, paths, targetSvcs
+}
+
+// ingressAddress 返回负载均衡器分配的全部地址，逗号分隔
+func ingressAddress(ing *networkingv1.Ingress) string {
+	addrs := make([]string, 0, len(ing.Status.LoadBalancer.Ingress))
+	for _, lb := range ing.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			addrs = append(addrs, lb.IP)
+		} else if lb.Hostname != "" {
+			addrs = append(addrs, lb.Hostname)
+		}
+	}
+	return strings.Join(addrs, ",")
+}
+
+// ingressPorts 与 kubectl get ingress 一致，配置 TLS 时为 80 和 443
+func ingressPorts(ing *networkingv1.Ingress) []string {
+	if len(ing.Spec.TLS) > 0 {
+		return []string{"80", "443"}
+	}
+	return []string{"80"}
+}
+
+func ingressClass(ing *networkingv1.Ingress) string {
+	if ing.Spec.IngressClassName != nil {
+		return *ing.Spec.IngressClassName
+	}
+	// 兼容旧的注解方式
+	return ing.Annotations["kubernetes.io/ingress.class"]
+}
+
+func ingressServicePort(port networkingv1.ServiceBackendPort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	return strconv.Itoa(int(port.Number))
+}
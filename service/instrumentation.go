@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/monitor"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	clientmetrics "k8s.io/client-go/tools/metrics"
+)
+
+var registerClientMetricsOnce sync.Once
+
+// instrumentedTransport 记录每次apiserver调用的动词、资源、命名空间、状态码和耗时
+// 并为每次调用创建客户端Span，通过 traceparent 传递给apiserver
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	verb, resource, namespace := parseK8sRequest(req.Method, req.URL)
+
+	ctx, span := monitor.StartSpan(req.Context(), "k8s."+verb+" "+resource,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.ServerAddress(req.URL.Hostname()),
+			semconv.URLFull(req.URL.Redacted()),
+			attribute.String("k8s.verb", verb),
+			attribute.String("k8s.resource", resource),
+			attribute.String("k8s.namespace", namespace),
+		),
+	)
+	// RoundTripper 不能修改原请求，注入追踪头前先复制
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+		}
+	}
+	monitor.EndSpan(span, err)
+
+	if m := monitor.GetMetricsManager(); m != nil {
+		m.GetMetrics().RecordK8sRequest(verb, resource, namespace, code, duration)
+	}
+
+	// watch 请求为长连接，耗时不代表性能问题
+	if threshold := slowK8sRequestThreshold(); threshold > 0 && duration >= threshold && verb != "watch" {
+		zap.L().Warn("Kubernetes API调用缓慢",
+			zap.String("traceId", model.TraceIDFromContext(req.Context())),
+			zap.String("verb", verb),
+			zap.String("resource", resource),
+			zap.String("namespace", namespace),
+			zap.String("code", code),
+			zap.Duration("duration", duration),
+		)
+	}
+	return resp, err
+}
+
+// instrumentTransport 作为 rest.Config 的 WrapTransport 使用
+func instrumentTransport(rt http.RoundTripper) http.RoundTripper {
+	return &instrumentedTransport{next: rt}
+}
+
+func slowK8sRequestThreshold() time.Duration {
+	if configManager == nil {
+		return 0
+	}
+	return configManager.GetConfig().Kubernetes.SlowRequestThreshold
+}
+
+// throttleLatencyMetric 接收client-go本地限流等待时间，限流发生在发出HTTP请求之前，传输层无法感知
+type throttleLatencyMetric struct{}
+
+func (throttleLatencyMetric) Observe(ctx context.Context, method string, u url.URL, latency time.Duration) {
+	// client-go 传入的URL已将命名空间和名称替换为模板，这里只取动词和资源
+	verb, resource, _ := parseK8sRequest(method, &u)
+	if m := monitor.GetMetricsManager(); m != nil {
+		m.GetMetrics().RecordK8sThrottle(verb, latency)
+	}
+	if latency >= monitor.K8sThrottleThreshold {
+		trace.SpanFromContext(ctx).AddEvent("k8s.client.throttled", trace.WithAttributes(
+			attribute.String("k8s.verb", verb),
+			attribute.String("k8s.resource", resource),
+			attribute.Int64("k8s.throttle.wait_ms", latency.Milliseconds()),
+		))
+		zap.L().Info("Kubernetes API调用被客户端限流",
+			zap.String("traceId", model.TraceIDFromContext(ctx)),
+			zap.String("verb", verb),
+			zap.String("resource", resource),
+			zap.Duration("wait", latency),
+		)
+	}
+}
+
+// registerClientMetrics 向client-go注册限流指标回调，进程内只能注册一次
+func registerClientMetrics() {
+	registerClientMetricsOnce.Do(func() {
+		clientmetrics.Register(clientmetrics.RegisterOpts{
+			RateLimiterLatency: throttleLatencyMetric{},
+		})
+	})
+}
+
+// parseK8sRequest 从请求路径解析动词、资源和命名空间
+// 支持 /api/v1/... 与 /apis/{group}/{version}/...，以及带前缀的apiserver地址（如代理路径）
+func parseK8sRequest(method string, u *url.URL) (verb, resource, namespace string) {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	start := -1
+	for i, seg := range segments {
+		if seg == "api" && i+1 < len(segments) {
+			start = i + 2
+			break
+		}
+		if seg == "apis" && i+2 < len(segments) {
+			start = i + 3
+			break
+		}
+	}
+	if start < 0 || start >= len(segments) {
+		return strings.ToLower(method), "discovery", ""
+	}
+
+	rest := segments[start:]
+	if rest[0] == "namespaces" && len(rest) >= 3 {
+		namespace = rest[1]
+		rest = rest[2:]
+	}
+
+	resource = rest[0]
+	name := ""
+	if len(rest) >= 2 {
+		name = rest[1]
+	}
+	if len(rest) >= 3 {
+		resource += "/" + rest[2]
+	}
+
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		switch {
+		case u.Query().Get("watch") == "true":
+			verb = "watch"
+		case name != "":
+			verb = "get"
+		default:
+			verb = "list"
+		}
+	case http.MethodPost:
+		verb = "create"
+	case http.MethodPut:
+		verb = "update"
+	case http.MethodPatch:
+		verb = "patch"
+	case http.MethodDelete:
+		if name != "" {
+			verb = "delete"
+		} else {
+			verb = "deletecollection"
+		}
+	default:
+		verb = strings.ToLower(method)
+	}
+	return verb, resource, namespace
+}
@@ -1,13 +1,19 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/nick0323/K8sVision/cache"
 	"github.com/nick0323/K8sVision/config"
 	"github.com/nick0323/K8sVision/model"
+	"github.com/nick0323/K8sVision/monitor"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -28,7 +34,7 @@ func SetCacheManager(cm *cache.Manager) {
 		Enabled:         true,
 		Type:            "memory",
 		TTL:             30 * time.Minute,
-		MaxSize:         10,
+		MaxSize:         100, // 启用模拟身份时每个身份一组客户端
 		CleanupInterval: 5 * time.Minute,
 	}, cm.GetLogger())
 }
@@ -81,6 +87,9 @@ func GetK8sConfig() (*rest.Config, error) {
 }
 
 func applyK8sConfig(config *rest.Config, k8sConfig *model.KubernetesConfig) {
+	registerClientMetrics()
+	config.Wrap(instrumentTransport)
+
 	if k8sConfig.Timeout > 0 {
 		config.Timeout = k8sConfig.Timeout
 	}
@@ -109,16 +118,33 @@ func applyK8sConfig(config *rest.Config, k8sConfig *model.KubernetesConfig) {
 	}
 }
 
-func GetK8sClient() (*kubernetes.Clientset, *metrics.Clientset, error) {
+// ClientCacheStats 返回按身份缓存的客户端数量等统计
+func ClientCacheStats() map[string]interface{} {
+	if clientsCache == nil {
+		return nil
+	}
+	return clientsCache.GetStats()
+}
+
+// GetK8sClient 获取客户端；启用模拟身份且上下文中有登录用户时，以该用户身份访问apiserver
+func GetK8sClient(ctx context.Context) (*kubernetes.Clientset, *metrics.Clientset, error) {
 	config, err := GetK8sConfig()
 	if err != nil {
 		return nil, nil, err
 	}
+	applyImpersonation(config, model.IdentityFromContext(ctx))
 
 	cacheKey := generateK8sClientCacheKey(config)
 
 	if clientsCache != nil {
-		if cached, exists := clientsCache.Get(cacheKey); exists {
+		_, span := monitor.StartSpan(ctx, "cache.get", trace.WithAttributes(
+			attribute.String("cache.name", "k8s_clients"),
+		))
+		cached, exists := clientsCache.Get(cacheKey)
+		span.SetAttributes(attribute.Bool("cache.hit", exists))
+		span.End()
+
+		if exists {
 			if clients, ok := cached.([]interface{}); ok && len(clients) == 2 {
 				if k8sClient, ok := clients[0].(*kubernetes.Clientset); ok {
 					if metricsClient, ok := clients[1].(*metrics.Clientset); ok {
@@ -163,5 +189,29 @@ func generateK8sClientCacheKey(config *rest.Config) string {
 		config.Insecure,
 		config.QPS,
 	)
+	if config.Impersonate.UserName != "" {
+		key += fmt.Sprintf("_as:%s_%s", config.Impersonate.UserName, strings.Join(config.Impersonate.Groups, ","))
+	}
 	return key
 }
+
+// applyImpersonation 按登录用户设置模拟身份，无用户上下文（如后台任务）时保持服务自身身份
+func applyImpersonation(config *rest.Config, identity *model.AuthIdentity) {
+	if identity == nil || configManager == nil {
+		return
+	}
+	impCfg := configManager.GetConfig().Kubernetes.Impersonation
+	if !impCfg.Enabled {
+		return
+	}
+
+	groups := make([]string, 0, len(impCfg.Groups)+1)
+	groups = append(groups, impCfg.Groups...)
+	groups = append(groups, impCfg.RoleGroups[identity.Role]...)
+	sort.Strings(groups)
+
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: impCfg.UserPrefix + identity.Username,
+		Groups:   groups,
+	}
+}
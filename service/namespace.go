@@ -4,8 +4,10 @@ import (
 	"context"
 
 	"github.com/nick0323/K8sVision/model"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	metrics "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 func ListNamespaces(ctx context.Context, clientset *kubernetes.Clientset) ([]model.NamespaceDetail, error) {
@@ -26,3 +28,98 @@ func ListNamespaces(ctx context.Context, clientset *kubernetes.Clientset) ([]mod
 	}
 	return result, nil
 }
+
+// GetNamespaceDetail 获取命名空间详情及资源汇总：对象数量、请求/限制/实际用量、配额使用率和默认限制
+// 除命名空间本身外，其余部分获取失败时只记录到 Warnings，不影响整体返回
+func GetNamespaceDetail(ctx context.Context, clientset *kubernetes.Clientset, metricsClient *metrics.Clientset, name string) (*model.NamespaceDetail, error) {
+	ns, err := clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &model.NamespaceSummary{
+		CreatedAt:   ns.CreationTimestamp.Local().Format(model.TimeFormat),
+		Quotas:      make([]model.QuotaResource, 0),
+		LimitRanges: make([]model.LimitRangeItem, 0),
+		Warnings:    make([]string, 0),
+	}
+	warn := func(part string, err error) {
+		summary.Warnings = append(summary.Warnings, part+": "+err.Error())
+	}
+
+	if podList, err := clientset.CoreV1().Pods(name).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("pods", err)
+	} else {
+		summary.PodCount = len(podList.Items)
+		for i := range podList.Items {
+			pod := &podList.Items[i]
+			if pod.Status.Phase == corev1.PodRunning {
+				summary.RunningPods++
+			}
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			// 与配额计算一致，计入初始化容器、边车容器和 Pod 开销
+			requests, limits := podRequestsAndLimits(pod)
+			summary.CPURequests += requests.Cpu().MilliValue()
+			summary.CPULimits += limits.Cpu().MilliValue()
+			summary.MemoryRequests += requests.Memory().Value()
+			summary.MemoryLimits += limits.Memory().Value()
+		}
+	}
+
+	if svcList, err := clientset.CoreV1().Services(name).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("services", err)
+	} else {
+		summary.ServiceCount = len(svcList.Items)
+	}
+	if pvcList, err := clientset.CoreV1().PersistentVolumeClaims(name).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("persistentvolumeclaims", err)
+	} else {
+		summary.PVCCount = len(pvcList.Items)
+	}
+
+	if metricsClient != nil {
+		if podMetrics, err := metricsClient.MetricsV1beta1().PodMetricses(name).List(ctx, metav1.ListOptions{}); err != nil {
+			warn("metrics", err)
+		} else {
+			summary.MetricsAvailable = true
+			for _, m := range podMetrics.Items {
+				for _, c := range m.Containers {
+					summary.CPUUsage += c.Usage.Cpu().MilliValue()
+					summary.MemoryUsage += c.Usage.Memory().Value()
+				}
+			}
+		}
+	}
+
+	if quotaList, err := clientset.CoreV1().ResourceQuotas(name).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("resourcequotas", err)
+	} else {
+		for i := range quotaList.Items {
+			resources, maxPercent := quotaResources(&quotaList.Items[i], true)
+			summary.Quotas = append(summary.Quotas, resources...)
+			if maxPercent > summary.QuotaMaxPercent {
+				summary.QuotaMaxPercent = maxPercent
+			}
+		}
+		sortQuotaResources(summary.Quotas)
+	}
+	if lrList, err := clientset.CoreV1().LimitRanges(name).List(ctx, metav1.ListOptions{}); err != nil {
+		warn("limitranges", err)
+	} else {
+		for i := range lrList.Items {
+			summary.LimitRanges = append(summary.LimitRanges, limitRangeItems(&lrList.Items[i], true)...)
+		}
+	}
+
+	return &model.NamespaceDetail{
+		Name:   ns.Name,
+		Status: string(ns.Status.Phase),
+		BaseMetadata: model.BaseMetadata{
+			Labels:      ns.Labels,
+			Annotations: ns.Annotations,
+		},
+		Summary: summary,
+	}, nil
+}
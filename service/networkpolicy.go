@@ -0,0 +1,458 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nick0323/K8sVision/model"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// 选择器为空（选中全部）时的显示值
+const selectAll = "<all>"
+
+// ErrInvalidPort 连通性查询的端口超出范围，或命名端口在目标Pod上不存在
+var ErrInvalidPort = errors.New("无效的端口")
+
+func ListNetworkPolicies(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]model.NetworkPolicyStatus, error) {
+	npList, err := clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.NetworkPolicyStatus, 0, len(npList.Items))
+	for i := range npList.Items {
+		np := &npList.Items[i]
+		result = append(result, model.NetworkPolicyStatus{
+			Namespace:    np.Namespace,
+			Name:         np.Name,
+			PodSelector:  formatSelector(&np.Spec.PodSelector),
+			PolicyTypes:  effectivePolicyTypes(np),
+			IngressRules: len(np.Spec.Ingress),
+			EgressRules:  len(np.Spec.Egress),
+		})
+	}
+	return result, nil
+}
+
+// GetNetworkPolicyDetail 获取NetworkPolicy详情及当前选中的Pod
+func GetNetworkPolicyDetail(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.NetworkPolicyDetail, error) {
+	np, err := clientset.NetworkingV1().NetworkPolicies(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.NetworkPolicyDetail{}, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&np.Spec.PodSelector)
+	if err != nil {
+		return model.NetworkPolicyDetail{}, err
+	}
+	podList, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return model.NetworkPolicyDetail{}, err
+	}
+	pods := make([]string, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		pods = append(pods, pod.Name)
+	}
+
+	return model.NetworkPolicyDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Namespace: np.Namespace,
+			Name:      np.Name,
+			Status:    model.StatusActive,
+			BaseMetadata: model.BaseMetadata{
+				Labels:      np.Labels,
+				Annotations: np.Annotations,
+			},
+		},
+		PodSelector:  formatSelector(&np.Spec.PodSelector),
+		PolicyTypes:  effectivePolicyTypes(np),
+		Ingress:      ingressRules(np, false),
+		Egress:       egressRules(np, false),
+		SelectedPods: pods,
+	}, nil
+}
+
+// GetPodNetworkPolicies 计算选中指定Pod的策略以及合并后的入站、出站规则
+func GetPodNetworkPolicies(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (*model.PodNetworkPolicies, error) {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	npList, err := clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.PodNetworkPolicies{
+		Namespace: namespace,
+		Pod:       name,
+		Policies:  make([]string, 0),
+		Ingress:   make([]model.NetworkPolicyRule, 0),
+		Egress:    make([]model.NetworkPolicyRule, 0),
+	}
+	for i := range npList.Items {
+		np := &npList.Items[i]
+		if !policySelectsPod(np, pod) {
+			continue
+		}
+		result.Policies = append(result.Policies, np.Name)
+		ingress, egress := policyDirections(np)
+		if ingress {
+			result.IngressIsolated = true
+			result.Ingress = append(result.Ingress, ingressRules(np, true)...)
+		}
+		if egress {
+			result.EgressIsolated = true
+			result.Egress = append(result.Egress, egressRules(np, true)...)
+		}
+	}
+	sort.Strings(result.Policies)
+	return result, nil
+}
+
+// CheckReachability 仅根据API对象判断源Pod能否通过指定端口访问目标Pod
+// port 可以是端口号或目标Pod容器声明的命名端口；protocol 为空时默认 TCP
+func CheckReachability(ctx context.Context, clientset *kubernetes.Clientset, srcNamespace, srcName, dstNamespace, dstName, port string, protocol corev1.Protocol) (*model.ReachabilityResult, error) {
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
+	}
+	src, err := clientset.CoreV1().Pods(srcNamespace).Get(ctx, srcName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	dst, err := clientset.CoreV1().Pods(dstNamespace).Get(ctx, dstName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := resolveRequestPort(dst, port, protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	srcPolicies, err := clientset.NetworkingV1().NetworkPolicies(srcNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	dstPolicies := srcPolicies
+	if dstNamespace != srcNamespace {
+		if dstPolicies, err = clientset.NetworkingV1().NetworkPolicies(dstNamespace).List(ctx, metav1.ListOptions{}); err != nil {
+			return nil, err
+		}
+	}
+	srcNsLabels := namespaceLabels(ctx, clientset, srcNamespace)
+	dstNsLabels := namespaceLabels(ctx, clientset, dstNamespace)
+
+	egress := evaluateDirection(srcPolicies.Items, src, false, func(np *networkingv1.NetworkPolicy) bool {
+		for _, rule := range np.Spec.Egress {
+			if peersMatch(rule.To, np.Namespace, dst, dstNsLabels) && portsMatch(rule.Ports, dst, resolved, protocol) {
+				return true
+			}
+		}
+		return false
+	})
+	ingress := evaluateDirection(dstPolicies.Items, dst, true, func(np *networkingv1.NetworkPolicy) bool {
+		for _, rule := range np.Spec.Ingress {
+			if peersMatch(rule.From, np.Namespace, src, srcNsLabels) && portsMatch(rule.Ports, dst, resolved, protocol) {
+				return true
+			}
+		}
+		return false
+	})
+
+	return &model.ReachabilityResult{
+		Source:       srcNamespace + "/" + srcName,
+		Destination:  dstNamespace + "/" + dstName,
+		Port:         port,
+		ResolvedPort: resolved,
+		Protocol:     string(protocol),
+		Allowed:      egress.Allowed && ingress.Allowed,
+		Egress:       egress,
+		Ingress:      ingress,
+	}, nil
+}
+
+// evaluateDirection 判断单个方向：未被该方向的任何策略选中时放行，否则需至少一条策略的规则允许
+func evaluateDirection(policies []networkingv1.NetworkPolicy, pod *corev1.Pod, ingress bool, allows func(*networkingv1.NetworkPolicy) bool) model.ReachabilityVerdict {
+	direction := "出站"
+	if ingress {
+		direction = "入站"
+	}
+	verdict := model.ReachabilityVerdict{Policies: make([]string, 0)}
+	for i := range policies {
+		np := &policies[i]
+		if !policySelectsPod(np, pod) {
+			continue
+		}
+		affectsIngress, affectsEgress := policyDirections(np)
+		if (ingress && !affectsIngress) || (!ingress && !affectsEgress) {
+			continue
+		}
+		verdict.Isolated = true
+		if allows(np) {
+			verdict.Policies = append(verdict.Policies, np.Name)
+		}
+	}
+
+	switch {
+	case !verdict.Isolated:
+		verdict.Allowed = true
+		verdict.Reason = fmt.Sprintf("Pod %s 未被任何%s策略隔离，默认允许", pod.Name, direction)
+	case len(verdict.Policies) > 0:
+		verdict.Allowed = true
+		verdict.Reason = fmt.Sprintf("%s流量被策略 %s 允许", direction, strings.Join(verdict.Policies, ", "))
+	default:
+		verdict.Reason = fmt.Sprintf("Pod %s 已被%s策略隔离，且没有规则允许该流量", pod.Name, direction)
+	}
+	return verdict
+}
+
+// policyDirections 返回策略是否作用于入站和出站；未声明 policyTypes 时总是作用于入站，存在出站规则时也作用于出站
+func policyDirections(np *networkingv1.NetworkPolicy) (ingress, egress bool) {
+	if len(np.Spec.PolicyTypes) == 0 {
+		return true, len(np.Spec.Egress) > 0
+	}
+	for _, t := range np.Spec.PolicyTypes {
+		switch t {
+		case networkingv1.PolicyTypeIngress:
+			ingress = true
+		case networkingv1.PolicyTypeEgress:
+			egress = true
+		}
+	}
+	return ingress, egress
+}
+
+func effectivePolicyTypes(np *networkingv1.NetworkPolicy) []string {
+	types := make([]string, 0, 2)
+	ingress, egress := policyDirections(np)
+	if ingress {
+		types = append(types, string(networkingv1.PolicyTypeIngress))
+	}
+	if egress {
+		types = append(types, string(networkingv1.PolicyTypeEgress))
+	}
+	return types
+}
+
+func policySelectsPod(np *networkingv1.NetworkPolicy, pod *corev1.Pod) bool {
+	return np.Namespace == pod.Namespace && selectorMatches(&np.Spec.PodSelector, pod.Labels)
+}
+
+// selectorMatches 判断标签是否满足选择器，空选择器匹配全部，非法选择器不匹配任何对象
+func selectorMatches(sel *metav1.LabelSelector, set map[string]string) bool {
+	selector, err := metav1.LabelSelectorAsSelector(sel)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(set))
+}
+
+// peersMatch 判断Pod是否属于规则的对端，peers 为空表示任意对端
+func peersMatch(peers []networkingv1.NetworkPolicyPeer, policyNamespace string, pod *corev1.Pod, nsLabels map[string]string) bool {
+	if len(peers) == 0 {
+		return true
+	}
+	for _, peer := range peers {
+		if peerMatches(peer, policyNamespace, pod, nsLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+func peerMatches(peer networkingv1.NetworkPolicyPeer, policyNamespace string, pod *corev1.Pod, nsLabels map[string]string) bool {
+	// 集群内Pod是否受 ipBlock 约束取决于网络插件，这里按Pod IP匹配
+	if peer.IPBlock != nil {
+		return ipBlockContains(peer.IPBlock, pod.Status.PodIP)
+	}
+	if peer.NamespaceSelector == nil {
+		return peer.PodSelector != nil && pod.Namespace == policyNamespace && selectorMatches(peer.PodSelector, pod.Labels)
+	}
+	if !selectorMatches(peer.NamespaceSelector, nsLabels) {
+		return false
+	}
+	return peer.PodSelector == nil || selectorMatches(peer.PodSelector, pod.Labels)
+}
+
+func ipBlockContains(block *networkingv1.IPBlock, ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	_, cidr, err := net.ParseCIDR(block.CIDR)
+	if err != nil || !cidr.Contains(addr) {
+		return false
+	}
+	for _, except := range block.Except {
+		if _, ex, err := net.ParseCIDR(except); err == nil && ex.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// portsMatch 判断端口是否被规则允许，ports 为空表示全部端口；命名端口按目标Pod的容器端口解析
+func portsMatch(ports []networkingv1.NetworkPolicyPort, dst *corev1.Pod, port int32, protocol corev1.Protocol) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, p := range ports {
+		proto := corev1.ProtocolTCP
+		if p.Protocol != nil {
+			proto = *p.Protocol
+		}
+		if proto != protocol {
+			continue
+		}
+		if p.Port == nil {
+			return true
+		}
+		if p.Port.Type == intstr.String {
+			if named, ok := namedContainerPort(dst, p.Port.StrVal, protocol); ok && named == port {
+				return true
+			}
+			continue
+		}
+		start, end := p.Port.IntVal, p.Port.IntVal
+		if p.EndPort != nil {
+			end = *p.EndPort
+		}
+		if port >= start && port <= end {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveRequestPort(dst *corev1.Pod, port string, protocol corev1.Protocol) (int32, error) {
+	if n, err := strconv.ParseInt(port, 10, 32); err == nil {
+		if n < 1 || n > 65535 {
+			return 0, fmt.Errorf("%w: %s 超出范围", ErrInvalidPort, port)
+		}
+		return int32(n), nil
+	}
+	if resolved, ok := namedContainerPort(dst, port, protocol); ok {
+		return resolved, nil
+	}
+	return 0, fmt.Errorf("%w: 目标Pod未声明命名端口 %s/%s", ErrInvalidPort, protocol, port)
+}
+
+func namedContainerPort(pod *corev1.Pod, name string, protocol corev1.Protocol) (int32, bool) {
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			proto := p.Protocol
+			if proto == "" {
+				proto = corev1.ProtocolTCP
+			}
+			if p.Name == name && proto == protocol {
+				return p.ContainerPort, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// namespaceLabels 获取命名空间标签；无权限读取时退回到自动设置的 kubernetes.io/metadata.name 标签
+func namespaceLabels(ctx context.Context, clientset *kubernetes.Clientset, namespace string) map[string]string {
+	ns, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return map[string]string{corev1.LabelMetadataName: namespace}
+	}
+	return ns.Labels
+}
+
+func ingressRules(np *networkingv1.NetworkPolicy, withPolicy bool) []model.NetworkPolicyRule {
+	rules := make([]model.NetworkPolicyRule, 0, len(np.Spec.Ingress))
+	for _, r := range np.Spec.Ingress {
+		rules = append(rules, convertRule(np, r.From, r.Ports, withPolicy))
+	}
+	return rules
+}
+
+func egressRules(np *networkingv1.NetworkPolicy, withPolicy bool) []model.NetworkPolicyRule {
+	rules := make([]model.NetworkPolicyRule, 0, len(np.Spec.Egress))
+	for _, r := range np.Spec.Egress {
+		rules = append(rules, convertRule(np, r.To, r.Ports, withPolicy))
+	}
+	return rules
+}
+
+func convertRule(np *networkingv1.NetworkPolicy, peers []networkingv1.NetworkPolicyPeer, ports []networkingv1.NetworkPolicyPort, withPolicy bool) model.NetworkPolicyRule {
+	rule := model.NetworkPolicyRule{
+		Peers: make([]model.NetworkPolicyPeer, 0, len(peers)),
+		Ports: make([]string, 0, len(ports)),
+	}
+	if withPolicy {
+		rule.Policy = np.Name
+	}
+	for _, peer := range peers {
+		rule.Peers = append(rule.Peers, convertPeer(peer, np.Namespace))
+	}
+	for _, p := range ports {
+		rule.Ports = append(rule.Ports, formatPolicyPort(p))
+	}
+	return rule
+}
+
+func convertPeer(peer networkingv1.NetworkPolicyPeer, policyNamespace string) model.NetworkPolicyPeer {
+	if peer.IPBlock != nil {
+		desc := "IP " + peer.IPBlock.CIDR
+		if len(peer.IPBlock.Except) > 0 {
+			desc += "（排除 " + strings.Join(peer.IPBlock.Except, ", ") + "）"
+		}
+		return model.NetworkPolicyPeer{IPBlock: peer.IPBlock.CIDR, Except: peer.IPBlock.Except, Description: desc}
+	}
+
+	result := model.NetworkPolicyPeer{
+		PodSelector:       formatSelector(peer.PodSelector),
+		NamespaceSelector: formatSelector(peer.NamespaceSelector),
+	}
+	namespaces := "命名空间 " + policyNamespace
+	if peer.NamespaceSelector != nil {
+		namespaces = "命名空间(" + result.NamespaceSelector + ")"
+	}
+	pods := "全部Pod"
+	if peer.PodSelector != nil && result.PodSelector != selectAll {
+		pods = "Pod(" + result.PodSelector + ")"
+	}
+	result.Description = namespaces + " 中的" + pods
+	return result
+}
+
+// formatPolicyPort 以 协议/端口 形式显示，未指定端口时为 协议/*
+func formatPolicyPort(p networkingv1.NetworkPolicyPort) string {
+	proto := string(corev1.ProtocolTCP)
+	if p.Protocol != nil {
+		proto = string(*p.Protocol)
+	}
+	if p.Port == nil {
+		return proto + "/*"
+	}
+	port := p.Port.String()
+	if p.EndPort != nil {
+		port += "-" + strconv.Itoa(int(*p.EndPort))
+	}
+	return proto + "/" + port
+}
+
+// formatSelector 返回标签选择器的字符串形式，nil 时返回空字符串，空选择器返回 <all>
+func formatSelector(sel *metav1.LabelSelector) string {
+	if sel == nil {
+		return ""
+	}
+	if len(sel.MatchLabels) == 0 && len(sel.MatchExpressions) == 0 {
+		return selectAll
+	}
+	return metav1.FormatLabelSelector(sel)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -11,6 +12,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	metrics "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 func ParseCPU(cpuStr string) float64 {
@@ -68,41 +70,13 @@ func ListNodes(ctx context.Context, clientset *kubernetes.Clientset, pods *v1.Po
 	if err != nil {
 		return nil, err
 	}
+	podsByNode := GroupPodsByNode(pods)
 	nodeStatuses := make([]model.NodeStatus, 0, len(nodes.Items))
 	for _, node := range nodes.Items {
-		status := "Unknown"
-		ip := ""
-		for _, addr := range node.Status.Addresses {
-			if addr.Type == "InternalIP" {
-				ip = addr.Address
-				break
-			}
-		}
-		for _, cond := range node.Status.Conditions {
-			if cond.Type == "Ready" && cond.Status == "True" {
-				status = "Active"
-				break
-			}
-		}
-		roles := []string{}
-		for k := range node.Labels {
-			if strings.HasPrefix(k, "node-role.kubernetes.io/") {
-				role := strings.TrimPrefix(k, "node-role.kubernetes.io/")
-				if role == "" {
-					role = "worker"
-				}
-				roles = append(roles, role)
-			}
-		}
-		if len(roles) == 0 {
-			roles = append(roles, "worker")
-		}
-		podsUsed := 0
-		for _, pod := range pods.Items {
-			if pod.Spec.NodeName == node.Name {
-				podsUsed++
-			}
-		}
+		status := nodeStatus(&node)
+		ip := nodeInternalIP(&node)
+		roles := nodeRoles(&node)
+		podsUsed := len(podsByNode[node.Name])
 		podsCapacity := 0
 		if v, ok := node.Status.Allocatable["pods"]; ok {
 			podsCapacity = int(v.Value())
@@ -133,3 +107,244 @@ func ListNodes(ctx context.Context, clientset *kubernetes.Clientset, pods *v1.Po
 	}
 	return nodeStatuses, nil
 }
+
+// GroupPodsByNode 按所在节点对Pod分组，未调度的Pod不计入
+func GroupPodsByNode(pods *v1.PodList) map[string][]*v1.Pod {
+	result := make(map[string][]*v1.Pod)
+	if pods == nil {
+		return result
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != "" {
+			result[pod.Spec.NodeName] = append(result[pod.Spec.NodeName], pod)
+		}
+	}
+	return result
+}
+
+// GetNodeDetail 获取节点详情：状态、污点、容量与可分配资源、系统信息以及节点上Pod的资源承诺
+func GetNodeDetail(ctx context.Context, clientset *kubernetes.Clientset, metricsClient *metrics.Clientset, name string) (*model.NodeDetail, error) {
+	node, err := clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := listPodsOnNode(ctx, clientset, node.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &model.NodeDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Namespace: "", // Node没有namespace
+			Name:      node.Name,
+			Status:    nodeStatus(node),
+			BaseMetadata: model.BaseMetadata{
+				Labels:      node.Labels,
+				Annotations: node.Annotations,
+			},
+		},
+		IP:            nodeInternalIP(node),
+		Role:          nodeRoles(node),
+		CreatedAt:     node.CreationTimestamp.Local().Format(model.TimeFormat),
+		Age:           FormatAge(node.CreationTimestamp.Time),
+		Unschedulable: node.Spec.Unschedulable,
+		Addresses:     make([]model.NodeAddress, 0, len(node.Status.Addresses)),
+		Conditions:    make([]model.ResourceCondition, 0, len(node.Status.Conditions)),
+		Taints:        make([]string, 0, len(node.Spec.Taints)),
+		Capacity:      resourceListStrings(node.Status.Capacity),
+		Allocatable:   resourceListStrings(node.Status.Allocatable),
+		SystemInfo: model.NodeSystemInfo{
+			KubeletVersion:          node.Status.NodeInfo.KubeletVersion,
+			KubeProxyVersion:        node.Status.NodeInfo.KubeProxyVersion,
+			ContainerRuntimeVersion: node.Status.NodeInfo.ContainerRuntimeVersion,
+			OSImage:                 node.Status.NodeInfo.OSImage,
+			KernelVersion:           node.Status.NodeInfo.KernelVersion,
+			OperatingSystem:         node.Status.NodeInfo.OperatingSystem,
+			Architecture:            node.Status.NodeInfo.Architecture,
+		},
+		Pods: make([]model.NodePod, 0, len(pods)),
+	}
+	if v, ok := node.Status.Allocatable[v1.ResourcePods]; ok {
+		detail.PodsCapacity = int(v.Value())
+	}
+	for _, addr := range node.Status.Addresses {
+		detail.Addresses = append(detail.Addresses, model.NodeAddress{Type: string(addr.Type), Address: addr.Address})
+	}
+	for _, c := range node.Status.Conditions {
+		detail.Conditions = append(detail.Conditions, model.ResourceCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: formatConditionTime(c.LastTransitionTime),
+		})
+	}
+	for i := range node.Spec.Taints {
+		detail.Taints = append(detail.Taints, node.Spec.Taints[i].ToString())
+	}
+
+	// 与 kubectl describe node 一致，已结束的Pod不占用节点资源
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		requests, limits := podRequestsAndLimits(pod)
+		np := model.NodePod{
+			Namespace:      pod.Namespace,
+			Name:           pod.Name,
+			Status:         string(pod.Status.Phase),
+			Age:            FormatAge(pod.CreationTimestamp.Time),
+			CPURequests:    requests.Cpu().MilliValue(),
+			CPULimits:      limits.Cpu().MilliValue(),
+			MemoryRequests: requests.Memory().Value(),
+			MemoryLimits:   limits.Memory().Value(),
+		}
+		detail.Allocated.CPURequests += np.CPURequests
+		detail.Allocated.CPULimits += np.CPULimits
+		detail.Allocated.MemoryRequests += np.MemoryRequests
+		detail.Allocated.MemoryLimits += np.MemoryLimits
+		detail.Pods = append(detail.Pods, np)
+	}
+	sort.SliceStable(detail.Pods, func(i, j int) bool {
+		if detail.Pods[i].Namespace != detail.Pods[j].Namespace {
+			return detail.Pods[i].Namespace < detail.Pods[j].Namespace
+		}
+		return detail.Pods[i].Name < detail.Pods[j].Name
+	})
+	detail.PodsUsed = len(detail.Pods)
+
+	cpuTotal := node.Status.Allocatable.Cpu().MilliValue()
+	memTotal := node.Status.Allocatable.Memory().Value()
+	if cpuTotal > 0 {
+		detail.Allocated.CPURequestsPercent = round1(float64(detail.Allocated.CPURequests) / float64(cpuTotal) * 100)
+		detail.Allocated.CPULimitsPercent = round1(float64(detail.Allocated.CPULimits) / float64(cpuTotal) * 100)
+	}
+	if memTotal > 0 {
+		detail.Allocated.MemoryRequestsPercent = round1(float64(detail.Allocated.MemoryRequests) / float64(memTotal) * 100)
+		detail.Allocated.MemoryLimitsPercent = round1(float64(detail.Allocated.MemoryLimits) / float64(memTotal) * 100)
+	}
+
+	// metrics-server 不可用时不影响详情返回
+	if metricsClient != nil {
+		if m, err := metricsClient.MetricsV1beta1().NodeMetricses().Get(ctx, name, metav1.GetOptions{}); err == nil {
+			if cpuTotal > 0 {
+				detail.CPUUsage = round1(float64(m.Usage.Cpu().MilliValue()) / float64(cpuTotal) * 100)
+			}
+			if memTotal > 0 {
+				detail.MemoryUsage = round1(float64(m.Usage.Memory().Value()) / float64(memTotal) * 100)
+			}
+		}
+	}
+	return detail, nil
+}
+
+// nodeStatus 根据 Ready 条件判断节点状态，缺少 Ready 条件时为 Unknown
+func nodeStatus(node *v1.Node) string {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type != v1.NodeReady {
+			continue
+		}
+		switch cond.Status {
+		case v1.ConditionTrue:
+			return model.StatusActive
+		case v1.ConditionFalse:
+			return model.StatusNotReady
+		}
+		break
+	}
+	return model.StatusUnknown
+}
+
+func nodeInternalIP(node *v1.Node) string {
+	for _, addr := range node.Status.Addresses {
+		if addr.Type == v1.NodeInternalIP {
+			return addr.Address
+		}
+	}
+	return ""
+}
+
+func nodeRoles(node *v1.Node) []string {
+	roles := []string{}
+	for k := range node.Labels {
+		if strings.HasPrefix(k, "node-role.kubernetes.io/") {
+			role := strings.TrimPrefix(k, "node-role.kubernetes.io/")
+			if role == "" {
+				role = "worker"
+			}
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		roles = append(roles, "worker")
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// podRequestsAndLimits 按 kubectl 的规则计算Pod的有效请求与限制：
+// 应用容器与边车容器（restartPolicy=Always 的初始化容器）求和，再与每个普通初始化容器取较大值，最后加上 Pod 开销
+func podRequestsAndLimits(pod *v1.Pod) (v1.ResourceList, v1.ResourceList) {
+	requests, limits := v1.ResourceList{}, v1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		addResourceList(requests, c.Resources.Requests)
+		addResourceList(limits, c.Resources.Limits)
+	}
+
+	sidecarRequests, sidecarLimits := v1.ResourceList{}, v1.ResourceList{}
+	initRequests, initLimits := v1.ResourceList{}, v1.ResourceList{}
+	for _, c := range pod.Spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == v1.ContainerRestartPolicyAlways {
+			addResourceList(requests, c.Resources.Requests)
+			addResourceList(limits, c.Resources.Limits)
+			addResourceList(sidecarRequests, c.Resources.Requests)
+			addResourceList(sidecarLimits, c.Resources.Limits)
+			continue
+		}
+		// 普通初始化容器运行时，此前启动的边车容器仍在运行
+		req, lim := c.Resources.Requests.DeepCopy(), c.Resources.Limits.DeepCopy()
+		if req == nil {
+			req = v1.ResourceList{}
+		}
+		if lim == nil {
+			lim = v1.ResourceList{}
+		}
+		addResourceList(req, sidecarRequests)
+		addResourceList(lim, sidecarLimits)
+		maxResourceList(initRequests, req)
+		maxResourceList(initLimits, lim)
+	}
+	maxResourceList(requests, initRequests)
+	maxResourceList(limits, initLimits)
+
+	addResourceList(requests, pod.Spec.Overhead)
+	// 仅当Pod设置了对应限制时才计入开销，避免把无限制的Pod算成有限制
+	for name, q := range pod.Spec.Overhead {
+		if v, ok := limits[name]; ok {
+			v.Add(q)
+			limits[name] = v
+		}
+	}
+	return requests, limits
+}
+
+func addResourceList(list, add v1.ResourceList) {
+	for name, q := range add {
+		if v, ok := list[name]; ok {
+			v.Add(q)
+			list[name] = v
+		} else {
+			list[name] = q.DeepCopy()
+		}
+	}
+}
+
+func maxResourceList(list, other v1.ResourceList) {
+	for name, q := range other {
+		if v, ok := list[name]; !ok || q.Cmp(v) > 0 {
+			list[name] = q.DeepCopy()
+		}
+	}
+}
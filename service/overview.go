@@ -167,3 +167,62 @@ func GetOverviewStatus(clientset *kubernetes.Clientset) (*model.OverviewStatus,
 
 	return overview, nil
 }
+
+// CountResources 按命名空间统计主要资源数量，供指标采集使用
+// 使用 ResourceVersion "0" 由apiserver缓存响应，降低周期性采集的开销
+func CountResources(ctx context.Context) (map[string]map[string]int64, error) {
+	clientset, _, err := GetK8sClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts := v1.ListOptions{ResourceVersion: "0"}
+	counts := make(map[string]map[string]int64)
+	add := func(kind, namespace string) {
+		if counts[kind] == nil {
+			counts[kind] = make(map[string]int64)
+		}
+		counts[kind][namespace]++
+	}
+
+	pods, err := clientset.CoreV1().Pods("").List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range pods.Items {
+		add("pods", item.Namespace)
+	}
+
+	deployments, err := clientset.AppsV1().Deployments("").List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range deployments.Items {
+		add("deployments", item.Namespace)
+	}
+
+	statefulSets, err := clientset.AppsV1().StatefulSets("").List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range statefulSets.Items {
+		add("statefulsets", item.Namespace)
+	}
+
+	daemonSets, err := clientset.AppsV1().DaemonSets("").List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range daemonSets.Items {
+		add("daemonsets", item.Namespace)
+	}
+
+	services, err := clientset.CoreV1().Services("").List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range services.Items {
+		add("services", item.Namespace)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nick0323/K8sVision/model"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+func ListPDBs(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]model.PDBStatus, error) {
+	pdbList, err := clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.PDBStatus, 0, len(pdbList.Items))
+	for i := range pdbList.Items {
+		pdb := &pdbList.Items[i]
+		result = append(result, model.PDBStatus{
+			Namespace:          pdb.Namespace,
+			Name:               pdb.Name,
+			MinAvailable:       formatIntOrString(pdb.Spec.MinAvailable),
+			MaxUnavailable:     formatIntOrString(pdb.Spec.MaxUnavailable),
+			Selector:           formatSelector(pdb.Spec.Selector),
+			CurrentHealthy:     pdb.Status.CurrentHealthy,
+			DesiredHealthy:     pdb.Status.DesiredHealthy,
+			ExpectedPods:       pdb.Status.ExpectedPods,
+			DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+			Status:             pdbHealth(pdb),
+		})
+	}
+	return result, nil
+}
+
+// GetPDBDetail 获取PodDisruptionBudget详情及当前选中的Pod
+func GetPDBDetail(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.PDBDetail, error) {
+	pdb, err := clientset.PolicyV1().PodDisruptionBudgets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.PDBDetail{}, err
+	}
+
+	// policy/v1 中空选择器匹配命名空间内全部Pod，nil 不匹配任何Pod
+	pods := make([]string, 0)
+	if pdb.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return model.PDBDetail{}, err
+		}
+		podList, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return model.PDBDetail{}, err
+		}
+		for _, pod := range podList.Items {
+			pods = append(pods, pod.Name)
+		}
+	}
+
+	conditions := make([]model.ResourceCondition, 0, len(pdb.Status.Conditions))
+	for _, c := range pdb.Status.Conditions {
+		conditions = append(conditions, model.ResourceCondition{
+			Type:               c.Type,
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: formatConditionTime(c.LastTransitionTime),
+		})
+	}
+	evictionPolicy := string(policyv1.IfHealthyBudget)
+	if pdb.Spec.UnhealthyPodEvictionPolicy != nil {
+		evictionPolicy = string(*pdb.Spec.UnhealthyPodEvictionPolicy)
+	}
+
+	return model.PDBDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Namespace: pdb.Namespace,
+			Name:      pdb.Name,
+			Status:    pdbHealth(pdb),
+			BaseMetadata: model.BaseMetadata{
+				Labels:      pdb.Labels,
+				Annotations: pdb.Annotations,
+			},
+		},
+		MinAvailable:               formatIntOrString(pdb.Spec.MinAvailable),
+		MaxUnavailable:             formatIntOrString(pdb.Spec.MaxUnavailable),
+		Selector:                   formatSelector(pdb.Spec.Selector),
+		UnhealthyPodEvictionPolicy: evictionPolicy,
+		CurrentHealthy:             pdb.Status.CurrentHealthy,
+		DesiredHealthy:             pdb.Status.DesiredHealthy,
+		ExpectedPods:               pdb.Status.ExpectedPods,
+		DisruptionsAllowed:         pdb.Status.DisruptionsAllowed,
+		Pods:                       pods,
+		Conditions:                 conditions,
+	}, nil
+}
+
+// pdbHealth 健康Pod不足时为异常，不允许任何中断时为耗尽（会阻塞节点驱逐）
+func pdbHealth(pdb *policyv1.PodDisruptionBudget) string {
+	switch {
+	case pdb.Status.CurrentHealthy < pdb.Status.DesiredHealthy:
+		return model.StatusAbnormal
+	case pdb.Status.DisruptionsAllowed <= 0:
+		return model.StatusExhausted
+	default:
+		return model.StatusHealthy
+	}
+}
+
+// pdbMatchesPod 判断PDB是否覆盖该Pod，选择器为 nil 时不匹配任何Pod
+func pdbMatchesPod(pdb *policyv1.PodDisruptionBudget, pod *corev1.Pod) bool {
+	if pdb.Namespace != pod.Namespace || pdb.Spec.Selector == nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(pod.Labels))
+}
+
+func formatIntOrString(v *intstr.IntOrString) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
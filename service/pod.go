@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/nick0323/K8sVision/model"
 
@@ -24,13 +26,17 @@ func ListPodsWithRaw(ctx context.Context, clientset *kubernetes.Clientset, podMe
 	}
 
 	podStatuses := make([]model.PodStatus, 0, len(pods.Items))
-	for _, pod := range pods.Items {
+	for i := range pods.Items {
+		pod := &pods.Items[i]
 		cpuVal, memVal := FormatPodResourceUsage(podMetricsMap, pod.Namespace, pod.Name)
 
 		podStatuses = append(podStatuses, model.PodStatus{
 			Namespace:   pod.Namespace,
 			Name:        pod.Name,
 			Status:      string(pod.Status.Phase),
+			Ready:       podReadyString(pod),
+			Restarts:    podRestarts(pod),
+			Age:         FormatAge(pod.CreationTimestamp.Time),
 			CPUUsage:    cpuVal,
 			MemoryUsage: memVal,
 			PodIP:       pod.Status.PodIP,
@@ -39,3 +45,249 @@ func ListPodsWithRaw(ctx context.Context, clientset *kubernetes.Clientset, podMe
 	}
 	return podStatuses, pods, nil
 }
+
+// GetPodDetail 获取Pod详情，包括各容器的状态与规格、条件、QoS、容忍和控制器链
+func GetPodDetail(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.PodDetail, error) {
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.PodDetail{}, err
+	}
+
+	statuses := make(map[string]v1.ContainerStatus)
+	for _, list := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, pod.Status.EphemeralContainerStatuses} {
+		for _, cs := range list {
+			statuses[cs.Name] = cs
+		}
+	}
+	initContainers := make([]model.ContainerDetail, 0, len(pod.Spec.InitContainers))
+	for i := range pod.Spec.InitContainers {
+		initContainers = append(initContainers, containerDetail(&pod.Spec.InitContainers[i], statuses))
+	}
+	containers := make([]model.ContainerDetail, 0, len(pod.Spec.Containers))
+	for i := range pod.Spec.Containers {
+		containers = append(containers, containerDetail(&pod.Spec.Containers[i], statuses))
+	}
+	ephemeral := make([]model.ContainerDetail, 0, len(pod.Spec.EphemeralContainers))
+	for i := range pod.Spec.EphemeralContainers {
+		c := v1.Container(pod.Spec.EphemeralContainers[i].EphemeralContainerCommon)
+		ephemeral = append(ephemeral, containerDetail(&c, statuses))
+	}
+
+	conditions := make([]model.ResourceCondition, 0, len(pod.Status.Conditions))
+	for _, c := range pod.Status.Conditions {
+		conditions = append(conditions, model.ResourceCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: formatConditionTime(c.LastTransitionTime),
+		})
+	}
+	tolerations := make([]string, 0, len(pod.Spec.Tolerations))
+	for _, t := range pod.Spec.Tolerations {
+		tolerations = append(tolerations, formatToleration(t))
+	}
+	startTime := ""
+	if pod.Status.StartTime != nil {
+		startTime = formatConditionTime(*pod.Status.StartTime)
+	}
+
+	return model.PodDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Status:    string(pod.Status.Phase),
+			BaseMetadata: model.BaseMetadata{
+				Labels:      pod.Labels,
+				Annotations: pod.Annotations,
+			},
+		},
+		PodIP:               pod.Status.PodIP,
+		HostIP:              pod.Status.HostIP,
+		NodeName:            pod.Spec.NodeName,
+		StartTime:           startTime,
+		Age:                 FormatAge(pod.CreationTimestamp.Time),
+		Ready:               podReadyString(pod),
+		Restarts:            podRestarts(pod),
+		QOSClass:            string(pod.Status.QOSClass),
+		ServiceAccount:      pod.Spec.ServiceAccountName,
+		PriorityClass:       pod.Spec.PriorityClassName,
+		Owners:              ownerChain(ctx, clientset, pod.Namespace, pod.OwnerReferences),
+		Conditions:          conditions,
+		Tolerations:         tolerations,
+		InitContainers:      initContainers,
+		Containers:          containers,
+		EphemeralContainers: ephemeral,
+	}, nil
+}
+
+// podReadyString 返回 就绪容器数/容器总数，与 kubectl get pods 的 READY 列一致
+func podReadyString(pod *v1.Pod) string {
+	ready := 0
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			ready++
+		}
+	}
+	return fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers))
+}
+
+// podRestarts 统计初始化容器与业务容器的重启次数之和
+func podRestarts(pod *v1.Pod) int32 {
+	var restarts int32
+	for _, cs := range pod.Status.InitContainerStatuses {
+		restarts += cs.RestartCount
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		restarts += cs.RestartCount
+	}
+	return restarts
+}
+
+func containerDetail(c *v1.Container, statuses map[string]v1.ContainerStatus) model.ContainerDetail {
+	detail := model.ContainerDetail{
+		Name:           c.Name,
+		Image:          c.Image,
+		State:          model.ContainerWaiting,
+		Requests:       resourceListStrings(c.Resources.Requests),
+		Limits:         resourceListStrings(c.Resources.Limits),
+		Ports:          make([]string, 0, len(c.Ports)),
+		LivenessProbe:  formatProbe(c.LivenessProbe),
+		ReadinessProbe: formatProbe(c.ReadinessProbe),
+		StartupProbe:   formatProbe(c.StartupProbe),
+		Mounts:         make([]model.ContainerMount, 0, len(c.VolumeMounts)),
+	}
+	for _, p := range c.Ports {
+		port := fmt.Sprintf("%d/%s", p.ContainerPort, protocolOrTCP(p.Protocol))
+		if p.Name != "" {
+			port = p.Name + ":" + port
+		}
+		detail.Ports = append(detail.Ports, port)
+	}
+	for _, m := range c.VolumeMounts {
+		detail.Mounts = append(detail.Mounts, model.ContainerMount{
+			Volume:    m.Name,
+			MountPath: m.MountPath,
+			SubPath:   m.SubPath,
+			ReadOnly:  m.ReadOnly,
+		})
+	}
+
+	// 尚未创建的容器没有状态，保持 Waiting
+	cs, ok := statuses[c.Name]
+	if !ok {
+		return detail
+	}
+	detail.ImageID = cs.ImageID
+	detail.Ready = cs.Ready
+	detail.RestartCount = cs.RestartCount
+	switch {
+	case cs.State.Running != nil:
+		detail.State = model.ContainerRunning
+		detail.StartedAt = formatConditionTime(cs.State.Running.StartedAt)
+	case cs.State.Terminated != nil:
+		t := cs.State.Terminated
+		detail.State = model.ContainerTerminated
+		detail.Reason, detail.Message = t.Reason, t.Message
+		detail.ExitCode = &t.ExitCode
+		detail.StartedAt = formatConditionTime(t.StartedAt)
+	case cs.State.Waiting != nil:
+		detail.Reason, detail.Message = cs.State.Waiting.Reason, cs.State.Waiting.Message
+	}
+	if t := cs.LastTerminationState.Terminated; t != nil {
+		detail.LastTermination = &model.ContainerTermination{
+			Reason:     t.Reason,
+			Message:    t.Message,
+			ExitCode:   t.ExitCode,
+			StartedAt:  formatConditionTime(t.StartedAt),
+			FinishedAt: formatConditionTime(t.FinishedAt),
+		}
+	}
+	return detail
+}
+
+func resourceListStrings(list v1.ResourceList) map[string]string {
+	result := make(map[string]string, len(list))
+	for name, q := range list {
+		result[string(name)] = q.String()
+	}
+	return result
+}
+
+// formatProbe 以 kubectl describe 的格式描述探针，如 http-get http://:8080/healthz delay=0s timeout=1s period=10s #success=1 #failure=3
+func formatProbe(probe *v1.Probe) string {
+	if probe == nil {
+		return ""
+	}
+	var handler string
+	switch h := probe.ProbeHandler; {
+	case h.Exec != nil:
+		handler = "exec [" + strings.Join(h.Exec.Command, " ") + "]"
+	case h.HTTPGet != nil:
+		scheme := strings.ToLower(string(h.HTTPGet.Scheme))
+		if scheme == "" {
+			scheme = "http"
+		}
+		handler = fmt.Sprintf("http-get %s://%s:%s%s", scheme, h.HTTPGet.Host, h.HTTPGet.Port.String(), h.HTTPGet.Path)
+	case h.TCPSocket != nil:
+		handler = fmt.Sprintf("tcp-socket %s:%s", h.TCPSocket.Host, h.TCPSocket.Port.String())
+	case h.GRPC != nil:
+		handler = fmt.Sprintf("grpc <pod>:%d %s", h.GRPC.Port, SafeStringPtr(h.GRPC.Service, ""))
+	default:
+		handler = "unknown"
+	}
+	return fmt.Sprintf("%s delay=%ds timeout=%ds period=%ds #success=%d #failure=%d", strings.TrimSpace(handler),
+		probe.InitialDelaySeconds, probe.TimeoutSeconds, probe.PeriodSeconds, probe.SuccessThreshold, probe.FailureThreshold)
+}
+
+// formatToleration 以 key=value:effect 形式描述容忍，并附加操作符和容忍时间
+func formatToleration(t v1.Toleration) string {
+	s := t.Key
+	if t.Value != "" {
+		s += "=" + t.Value
+	}
+	if t.Effect != "" {
+		s += ":" + string(t.Effect)
+	}
+	if s == "" {
+		s = "<all>"
+	}
+	if t.Operator == v1.TolerationOpExists {
+		s += " op=Exists"
+	}
+	if t.TolerationSeconds != nil {
+		s += fmt.Sprintf(" for %ds", *t.TolerationSeconds)
+	}
+	return s
+}
+
+// 控制器链的最大查询深度
+const maxOwnerDepth = 5
+
+// ownerChain 沿控制器引用向上查找，如 ReplicaSet/web-abc -> Deployment/web；无法继续查询时停止
+func ownerChain(ctx context.Context, clientset *kubernetes.Clientset, namespace string, refs []metav1.OwnerReference) []string {
+	chain := make([]string, 0, 2)
+	for depth := 0; depth < maxOwnerDepth; depth++ {
+		owner := controllerOwner(refs)
+		if owner == "" {
+			break
+		}
+		chain = append(chain, owner)
+		kind, name, _ := strings.Cut(owner, "/")
+		var meta metav1.Object
+		var err error
+		switch kind {
+		case WorkloadReplicaSet:
+			meta, err = clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		case WorkloadJob:
+			meta, err = clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		default:
+			return chain
+		}
+		if err != nil {
+			break
+		}
+		refs = meta.GetOwnerReferences()
+	}
+	return chain
+}
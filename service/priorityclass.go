@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/nick0323/K8sVision/model"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ListPriorityClasses 获取PriorityClass列表，按优先级值降序排列；PriorityClass 为集群级资源，忽略 namespace
+func ListPriorityClasses(ctx context.Context, clientset *kubernetes.Clientset, _ string) ([]model.PriorityClassStatus, error) {
+	pcList, err := clientset.SchedulingV1().PriorityClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.PriorityClassStatus, 0, len(pcList.Items))
+	for i := range pcList.Items {
+		pc := &pcList.Items[i]
+		result = append(result, model.PriorityClassStatus{
+			Name:             pc.Name,
+			Value:            pc.Value,
+			GlobalDefault:    pc.GlobalDefault,
+			PreemptionPolicy: preemptionPolicy(pc),
+			Description:      pc.Description,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Value > result[j].Value })
+	return result, nil
+}
+
+// GetPriorityClassDetail 获取PriorityClass详情并统计使用它的Pod数量
+func GetPriorityClassDetail(ctx context.Context, clientset *kubernetes.Clientset, _, name string) (model.PriorityClassDetail, error) {
+	pc, err := clientset.SchedulingV1().PriorityClasses().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.PriorityClassDetail{}, err
+	}
+	podList, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return model.PriorityClassDetail{}, err
+	}
+	podCount := 0
+	for _, pod := range podList.Items {
+		if pod.Spec.PriorityClassName == pc.Name {
+			podCount++
+		}
+	}
+
+	return model.PriorityClassDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Name:   pc.Name,
+			Status: model.StatusActive,
+			BaseMetadata: model.BaseMetadata{
+				Labels:      pc.Labels,
+				Annotations: pc.Annotations,
+			},
+		},
+		Value:            pc.Value,
+		GlobalDefault:    pc.GlobalDefault,
+		PreemptionPolicy: preemptionPolicy(pc),
+		Description:      pc.Description,
+		PodCount:         podCount,
+	}, nil
+}
+
+// preemptionPolicy 未设置时默认为 PreemptLowerPriority
+func preemptionPolicy(pc *schedulingv1.PriorityClass) string {
+	if pc.PreemptionPolicy == nil {
+		return string(corev1.PreemptLowerPriority)
+	}
+	return string(*pc.PreemptionPolicy)
+}
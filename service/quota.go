@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/nick0323/K8sVision/model"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ListResourceQuotas 获取ResourceQuota列表，按最高使用率降序排列，便于发现即将耗尽的配额
+func ListResourceQuotas(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]model.ResourceQuotaStatus, error) {
+	quotaList, err := clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.ResourceQuotaStatus, 0, len(quotaList.Items))
+	for i := range quotaList.Items {
+		q := &quotaList.Items[i]
+		resources, maxPercent := quotaResources(q, false)
+		result = append(result, model.ResourceQuotaStatus{
+			Namespace:      q.Namespace,
+			Name:           q.Name,
+			Resources:      resources,
+			MaxUsedPercent: maxPercent,
+			Status:         GetQuotaStatus(maxPercent),
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].MaxUsedPercent > result[j].MaxUsedPercent })
+	return result, nil
+}
+
+func GetResourceQuotaDetail(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.ResourceQuotaDetail, error) {
+	q, err := clientset.CoreV1().ResourceQuotas(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.ResourceQuotaDetail{}, err
+	}
+	resources, maxPercent := quotaResources(q, false)
+	scopes := make([]string, 0, len(q.Spec.Scopes))
+	for _, scope := range q.Spec.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	return model.ResourceQuotaDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Namespace: q.Namespace,
+			Name:      q.Name,
+			Status:    GetQuotaStatus(maxPercent),
+			BaseMetadata: model.BaseMetadata{
+				Labels:      q.Labels,
+				Annotations: q.Annotations,
+			},
+		},
+		Scopes:         scopes,
+		Resources:      resources,
+		MaxUsedPercent: maxPercent,
+	}, nil
+}
+
+func ListLimitRanges(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]model.LimitRangeStatus, error) {
+	lrList, err := clientset.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.LimitRangeStatus, 0, len(lrList.Items))
+	for i := range lrList.Items {
+		lr := &lrList.Items[i]
+		types := make([]string, 0, len(lr.Spec.Limits))
+		for _, item := range lr.Spec.Limits {
+			if !ContainsString(types, string(item.Type)) {
+				types = append(types, string(item.Type))
+			}
+		}
+		result = append(result, model.LimitRangeStatus{
+			Namespace: lr.Namespace,
+			Name:      lr.Name,
+			Types:     types,
+			Limits:    limitRangeItems(lr, false),
+		})
+	}
+	return result, nil
+}
+
+func GetLimitRangeDetail(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.LimitRangeDetail, error) {
+	lr, err := clientset.CoreV1().LimitRanges(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.LimitRangeDetail{}, err
+	}
+	return model.LimitRangeDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Namespace: lr.Namespace,
+			Name:      lr.Name,
+			Status:    model.StatusActive,
+			BaseMetadata: model.BaseMetadata{
+				Labels:      lr.Labels,
+				Annotations: lr.Annotations,
+			},
+		},
+		Limits: limitRangeItems(lr, false),
+	}, nil
+}
+
+// GetQuotaStatus 根据最高使用率返回配额状态
+func GetQuotaStatus(maxPercent float64) string {
+	switch {
+	case maxPercent >= 100:
+		return model.StatusExhausted
+	case maxPercent >= model.QuotaNearLimitPercent:
+		return model.StatusNearLimit
+	default:
+		return model.StatusHealthy
+	}
+}
+
+// quotaResources 按使用率降序返回各资源的上限与已用量，以及最高使用率
+func quotaResources(q *corev1.ResourceQuota, withQuota bool) ([]model.QuotaResource, float64) {
+	result := make([]model.QuotaResource, 0, len(q.Status.Hard))
+	maxPercent := 0.0
+	for name, hard := range q.Status.Hard {
+		used := q.Status.Used[name]
+		percent := 0.0
+		if h := hard.AsApproximateFloat64(); h > 0 {
+			percent = round1(used.AsApproximateFloat64() / h * 100)
+		} else if !used.IsZero() {
+			percent = 100
+		}
+		item := model.QuotaResource{
+			Resource:    string(name),
+			Hard:        hard.String(),
+			Used:        used.String(),
+			UsedPercent: percent,
+		}
+		if withQuota {
+			item.Quota = q.Name
+		}
+		result = append(result, item)
+		maxPercent = math.Max(maxPercent, percent)
+	}
+	sortQuotaResources(result)
+	return result, maxPercent
+}
+
+func sortQuotaResources(resources []model.QuotaResource) {
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].UsedPercent != resources[j].UsedPercent {
+			return resources[i].UsedPercent > resources[j].UsedPercent
+		}
+		return resources[i].Resource < resources[j].Resource
+	})
+}
+
+// limitRangeItems 将每条限制按资源展开为一行
+func limitRangeItems(lr *corev1.LimitRange, withName bool) []model.LimitRangeItem {
+	result := make([]model.LimitRangeItem, 0)
+	for _, limit := range lr.Spec.Limits {
+		resources := make(map[corev1.ResourceName]bool)
+		for _, list := range []corev1.ResourceList{limit.Min, limit.Max, limit.Default, limit.DefaultRequest, limit.MaxLimitRequestRatio} {
+			for name := range list {
+				resources[name] = true
+			}
+		}
+		names := make([]string, 0, len(resources))
+		for name := range resources {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			res := corev1.ResourceName(name)
+			item := model.LimitRangeItem{
+				Type:                 string(limit.Type),
+				Resource:             name,
+				Min:                  quantityString(limit.Min, res),
+				Max:                  quantityString(limit.Max, res),
+				Default:              quantityString(limit.Default, res),
+				DefaultRequest:       quantityString(limit.DefaultRequest, res),
+				MaxLimitRequestRatio: quantityString(limit.MaxLimitRequestRatio, res),
+			}
+			if withName {
+				item.LimitRange = lr.Name
+			}
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func quantityString(list corev1.ResourceList, name corev1.ResourceName) string {
+	if q, ok := list[name]; ok {
+		return q.String()
+	}
+	return ""
+}
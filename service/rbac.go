@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/nick0323/K8sVision/model"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RBAC 对象类型
+const (
+	KindRole               = "Role"
+	KindClusterRole        = "ClusterRole"
+	KindRoleBinding        = "RoleBinding"
+	KindClusterRoleBinding = "ClusterRoleBinding"
+)
+
+func ListServiceAccounts(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]model.ServiceAccountStatus, error) {
+	saList, err := clientset.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.ServiceAccountStatus, 0, len(saList.Items))
+	for _, sa := range saList.Items {
+		result = append(result, model.ServiceAccountStatus{
+			Namespace:        sa.Namespace,
+			Name:             sa.Name,
+			Secrets:          len(sa.Secrets),
+			ImagePullSecrets: len(sa.ImagePullSecrets),
+			Age:              FormatAge(sa.CreationTimestamp.Time),
+		})
+	}
+	return result, nil
+}
+
+// GetServiceAccountDetail 获取ServiceAccount详情及引用它的RoleBinding与ClusterRoleBinding
+func GetServiceAccountDetail(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.ServiceAccountDetail, error) {
+	sa, err := clientset.CoreV1().ServiceAccounts(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.ServiceAccountDetail{}, err
+	}
+	secrets := make([]string, 0, len(sa.Secrets))
+	for _, ref := range sa.Secrets {
+		secrets = append(secrets, ref.Name)
+	}
+	pullSecrets := make([]string, 0, len(sa.ImagePullSecrets))
+	for _, ref := range sa.ImagePullSecrets {
+		pullSecrets = append(pullSecrets, ref.Name)
+	}
+
+	subject := model.RBACSubject{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace}
+	bindings, err := listBindingsFor(ctx, clientset, namespace, func(b *rbacBinding) bool {
+		return b.hasSubject(subject)
+	})
+	if err != nil {
+		return model.ServiceAccountDetail{}, err
+	}
+
+	return model.ServiceAccountDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Namespace: sa.Namespace,
+			Name:      sa.Name,
+			Status:    model.StatusActive,
+			BaseMetadata: model.BaseMetadata{
+				Labels:      sa.Labels,
+				Annotations: sa.Annotations,
+			},
+		},
+		Secrets:                      secrets,
+		ImagePullSecrets:             pullSecrets,
+		AutomountServiceAccountToken: SafeBoolPtr(sa.AutomountServiceAccountToken, true),
+		Bindings:                     bindings,
+	}, nil
+}
+
+func ListRoles(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]model.RoleStatus, error) {
+	roleList, err := clientset.RbacV1().Roles(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.RoleStatus, 0, len(roleList.Items))
+	for _, r := range roleList.Items {
+		result = append(result, model.RoleStatus{
+			Namespace: r.Namespace,
+			Name:      r.Name,
+			Kind:      KindRole,
+			Rules:     len(r.Rules),
+			Age:       FormatAge(r.CreationTimestamp.Time),
+		})
+	}
+	return result, nil
+}
+
+// ListClusterRoles 获取ClusterRole列表，集群级资源忽略 namespace 参数
+func ListClusterRoles(ctx context.Context, clientset *kubernetes.Clientset, _ string) ([]model.RoleStatus, error) {
+	roleList, err := clientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.RoleStatus, 0, len(roleList.Items))
+	for _, r := range roleList.Items {
+		result = append(result, model.RoleStatus{
+			Name:       r.Name,
+			Kind:       KindClusterRole,
+			Rules:      len(r.Rules),
+			Aggregated: r.AggregationRule != nil,
+			Age:        FormatAge(r.CreationTimestamp.Time),
+		})
+	}
+	return result, nil
+}
+
+// GetRoleDetail 获取Role详情及引用它的RoleBinding
+func GetRoleDetail(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.RoleDetail, error) {
+	role, err := clientset.RbacV1().Roles(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.RoleDetail{}, err
+	}
+	bindings, err := listBindingsFor(ctx, clientset, namespace, func(b *rbacBinding) bool {
+		return !b.cluster && b.roleRef.Kind == KindRole && b.roleRef.Name == name
+	})
+	if err != nil {
+		return model.RoleDetail{}, err
+	}
+	return model.RoleDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Namespace: role.Namespace,
+			Name:      role.Name,
+			Status:    model.StatusActive,
+			BaseMetadata: model.BaseMetadata{
+				Labels:      role.Labels,
+				Annotations: role.Annotations,
+			},
+		},
+		Kind:                 KindRole,
+		Rules:                convertPolicyRules(role.Rules),
+		AggregationSelectors: make([]string, 0),
+		Bindings:             bindings,
+	}, nil
+}
+
+// GetClusterRoleDetail 获取ClusterRole详情及所有命名空间中引用它的绑定
+func GetClusterRoleDetail(ctx context.Context, clientset *kubernetes.Clientset, _, name string) (model.RoleDetail, error) {
+	role, err := clientset.RbacV1().ClusterRoles().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.RoleDetail{}, err
+	}
+	selectors := make([]string, 0)
+	if role.AggregationRule != nil {
+		for i := range role.AggregationRule.ClusterRoleSelectors {
+			selectors = append(selectors, formatSelector(&role.AggregationRule.ClusterRoleSelectors[i]))
+		}
+	}
+	bindings, err := listBindingsFor(ctx, clientset, metav1.NamespaceAll, func(b *rbacBinding) bool {
+		return b.roleRef.Kind == KindClusterRole && b.roleRef.Name == name
+	})
+	if err != nil {
+		return model.RoleDetail{}, err
+	}
+	return model.RoleDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Name:   role.Name,
+			Status: model.StatusActive,
+			BaseMetadata: model.BaseMetadata{
+				Labels:      role.Labels,
+				Annotations: role.Annotations,
+			},
+		},
+		Kind:                 KindClusterRole,
+		Rules:                convertPolicyRules(role.Rules),
+		AggregationSelectors: selectors,
+		Bindings:             bindings,
+	}, nil
+}
+
+func ListRoleBindings(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]model.RoleBindingStatus, error) {
+	rbList, err := clientset.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.RoleBindingStatus, 0, len(rbList.Items))
+	for _, rb := range rbList.Items {
+		result = append(result, model.RoleBindingStatus{
+			Namespace: rb.Namespace,
+			Name:      rb.Name,
+			Kind:      KindRoleBinding,
+			RoleRef:   rb.RoleRef.Kind + "/" + rb.RoleRef.Name,
+			Subjects:  formatSubjects(rb.Subjects),
+			Age:       FormatAge(rb.CreationTimestamp.Time),
+		})
+	}
+	return result, nil
+}
+
+// ListClusterRoleBindings 获取ClusterRoleBinding列表，集群级资源忽略 namespace 参数
+func ListClusterRoleBindings(ctx context.Context, clientset *kubernetes.Clientset, _ string) ([]model.RoleBindingStatus, error) {
+	crbList, err := clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.RoleBindingStatus, 0, len(crbList.Items))
+	for _, crb := range crbList.Items {
+		result = append(result, model.RoleBindingStatus{
+			Name:     crb.Name,
+			Kind:     KindClusterRoleBinding,
+			RoleRef:  crb.RoleRef.Kind + "/" + crb.RoleRef.Name,
+			Subjects: formatSubjects(crb.Subjects),
+			Age:      FormatAge(crb.CreationTimestamp.Time),
+		})
+	}
+	return result, nil
+}
+
+// GetRoleBindingDetail 获取RoleBinding详情，并解析所引用角色的规则
+func GetRoleBindingDetail(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.RoleBindingDetail, error) {
+	rb, err := clientset.RbacV1().RoleBindings(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.RoleBindingDetail{}, err
+	}
+	detail := bindingDetail(KindRoleBinding, rb.ObjectMeta, rb.RoleRef, rb.Subjects)
+	detail.Rules, detail.RoleFound = resolveRoleRules(ctx, clientset, namespace, rb.RoleRef)
+	return detail, nil
+}
+
+// GetClusterRoleBindingDetail 获取ClusterRoleBinding详情，并解析所引用ClusterRole的规则
+func GetClusterRoleBindingDetail(ctx context.Context, clientset *kubernetes.Clientset, _, name string) (model.RoleBindingDetail, error) {
+	crb, err := clientset.RbacV1().ClusterRoleBindings().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.RoleBindingDetail{}, err
+	}
+	detail := bindingDetail(KindClusterRoleBinding, crb.ObjectMeta, crb.RoleRef, crb.Subjects)
+	detail.Rules, detail.RoleFound = resolveRoleRules(ctx, clientset, metav1.NamespaceAll, crb.RoleRef)
+	return detail, nil
+}
+
+func bindingDetail(kind string, meta metav1.ObjectMeta, ref rbacv1.RoleRef, subjects []rbacv1.Subject) model.RoleBindingDetail {
+	converted := make([]model.RBACSubject, 0, len(subjects))
+	for _, s := range subjects {
+		converted = append(converted, model.RBACSubject{Kind: s.Kind, Name: s.Name, Namespace: s.Namespace})
+	}
+	return model.RoleBindingDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Namespace: meta.Namespace,
+			Name:      meta.Name,
+			Status:    model.StatusActive,
+			BaseMetadata: model.BaseMetadata{
+				Labels:      meta.Labels,
+				Annotations: meta.Annotations,
+			},
+		},
+		Kind:     kind,
+		RoleKind: ref.Kind,
+		RoleName: ref.Name,
+		Subjects: converted,
+		Rules:    make([]model.PolicyRule, 0),
+	}
+}
+
+// resolveRoleRules 获取绑定所引用角色的规则，角色不存在或无权读取时返回 false
+func resolveRoleRules(ctx context.Context, clientset *kubernetes.Clientset, namespace string, ref rbacv1.RoleRef) ([]model.PolicyRule, bool) {
+	var rules []rbacv1.PolicyRule
+	switch ref.Kind {
+	case KindRole:
+		role, err := clientset.RbacV1().Roles(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return make([]model.PolicyRule, 0), false
+		}
+		rules = role.Rules
+	case KindClusterRole:
+		role, err := clientset.RbacV1().ClusterRoles().Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return make([]model.PolicyRule, 0), false
+		}
+		rules = role.Rules
+	default:
+		return make([]model.PolicyRule, 0), false
+	}
+	return convertPolicyRules(rules), true
+}
+
+func convertPolicyRules(rules []rbacv1.PolicyRule) []model.PolicyRule {
+	result := make([]model.PolicyRule, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, convertPolicyRule(r))
+	}
+	return result
+}
+
+func convertPolicyRule(r rbacv1.PolicyRule) model.PolicyRule {
+	return model.PolicyRule{
+		APIGroups:       nonNilStrings(r.APIGroups),
+		Resources:       nonNilStrings(r.Resources),
+		ResourceNames:   nonNilStrings(r.ResourceNames),
+		Verbs:           nonNilStrings(r.Verbs),
+		NonResourceURLs: nonNilStrings(r.NonResourceURLs),
+	}
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func formatSubjects(subjects []rbacv1.Subject) []string {
+	result := make([]string, 0, len(subjects))
+	for _, s := range subjects {
+		result = append(result, formatSubject(model.RBACSubject{Kind: s.Kind, Name: s.Name, Namespace: s.Namespace}))
+	}
+	return result
+}
+
+// formatSubject 返回 Kind:名称 形式，ServiceAccount 为 ServiceAccount:namespace/name
+func formatSubject(s model.RBACSubject) string {
+	if s.Kind == rbacv1.ServiceAccountKind {
+		return s.Kind + ":" + s.Namespace + "/" + s.Name
+	}
+	return s.Kind + ":" + s.Name
+}
+
+// rbacBinding RoleBinding 与 ClusterRoleBinding 的统一表示，便于分析时一并遍历
+type rbacBinding struct {
+	cluster   bool
+	namespace string
+	name      string
+	roleRef   rbacv1.RoleRef
+	subjects  []rbacv1.Subject
+}
+
+// ref 返回 Kind/namespace/name 或 Kind/name 形式的绑定描述
+func (b *rbacBinding) ref() string {
+	if b.cluster {
+		return KindClusterRoleBinding + "/" + b.name
+	}
+	return KindRoleBinding + "/" + b.namespace + "/" + b.name
+}
+
+// roleRefString 返回所引用角色的描述，RoleBinding 引用的 Role 带上命名空间
+func (b *rbacBinding) roleRefString() string {
+	if b.roleRef.Kind == KindRole {
+		return KindRole + "/" + b.namespace + "/" + b.roleRef.Name
+	}
+	return b.roleRef.Kind + "/" + b.roleRef.Name
+}
+
+func (b *rbacBinding) hasSubject(subject model.RBACSubject) bool {
+	for _, s := range b.subjects {
+		if subjectEquals(s, b.namespace, subject) {
+			return true
+		}
+	}
+	return false
+}
+
+// subjectEquals 判断绑定中的主体是否为指定主体；RoleBinding 中未写命名空间的 ServiceAccount 默认为绑定所在命名空间
+func subjectEquals(s rbacv1.Subject, bindingNamespace string, subject model.RBACSubject) bool {
+	if s.Kind != subject.Kind || s.Name != subject.Name {
+		return false
+	}
+	if s.Kind != rbacv1.ServiceAccountKind {
+		return true
+	}
+	ns := s.Namespace
+	if ns == "" {
+		ns = bindingNamespace
+	}
+	return ns == subject.Namespace
+}
+
+// fetchBindings 获取指定命名空间的RoleBinding（namespace 为空时为全部命名空间）及全部ClusterRoleBinding
+func fetchBindings(ctx context.Context, clientset *kubernetes.Clientset, namespace string, includeRoleBindings bool) ([]rbacBinding, error) {
+	crbList, err := clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	bindings := make([]rbacBinding, 0, len(crbList.Items))
+	for _, crb := range crbList.Items {
+		bindings = append(bindings, rbacBinding{cluster: true, name: crb.Name, roleRef: crb.RoleRef, subjects: crb.Subjects})
+	}
+	if !includeRoleBindings {
+		return bindings, nil
+	}
+	rbList, err := clientset.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, rb := range rbList.Items {
+		bindings = append(bindings, rbacBinding{namespace: rb.Namespace, name: rb.Name, roleRef: rb.RoleRef, subjects: rb.Subjects})
+	}
+	return bindings, nil
+}
+
+// listBindingsFor 返回满足条件的绑定描述，按名称排序
+func listBindingsFor(ctx context.Context, clientset *kubernetes.Clientset, namespace string, match func(*rbacBinding) bool) ([]string, error) {
+	bindings, err := fetchBindings(ctx, clientset, namespace, true)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, 0)
+	for i := range bindings {
+		if match(&bindings[i]) {
+			result = append(result, bindings[i].ref())
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
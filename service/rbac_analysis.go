@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/nick0323/K8sVision/model"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// who-can 确认时最多发起的 SubjectAccessReview 数量
+const maxAccessReviews = 50
+
+// 系统为已认证用户与 ServiceAccount 隐含添加的组
+const (
+	groupAuthenticated   = "system:authenticated"
+	groupServiceAccounts = "system:serviceaccounts"
+)
+
+// AccessAttributes 一次资源访问的属性，Subresource 如 log、exec；Name 为空表示不限定资源名
+type AccessAttributes struct {
+	Verb        string
+	APIGroup    string
+	Resource    string
+	Subresource string
+	Name        string
+	Namespace   string
+}
+
+// ParseResourceArg 解析 kubectl 风格的资源参数：resource[.group][/subresource]，如 pods/log、deployments.apps
+func ParseResourceArg(arg string) (resource, group, subresource string) {
+	resource, subresource, _ = strings.Cut(arg, "/")
+	resource, group, _ = strings.Cut(resource, ".")
+	return resource, group, subresource
+}
+
+// SubjectGroups 返回参与匹配的组：指定的组加上系统隐含的组
+func SubjectGroups(subject model.RBACSubject, groups []string) []string {
+	result := make([]string, 0, len(groups)+3)
+	add := func(g string) {
+		if g != "" && !ContainsString(result, g) {
+			result = append(result, g)
+		}
+	}
+	for _, g := range groups {
+		add(g)
+	}
+	switch subject.Kind {
+	case rbacv1.UserKind:
+		add(groupAuthenticated)
+	case rbacv1.ServiceAccountKind:
+		add(groupServiceAccounts)
+		add(groupServiceAccounts + ":" + subject.Namespace)
+		add(groupAuthenticated)
+	}
+	return result
+}
+
+// GetSubjectPermissions 汇总主体通过各绑定在命名空间中获得的规则（what-can）
+// namespace 为空时只统计 ClusterRoleBinding；filter 非 nil 时只保留允许该访问的规则，confirm 时再以 SubjectAccessReview 确认
+func GetSubjectPermissions(ctx context.Context, clientset *kubernetes.Clientset, subject model.RBACSubject, groups []string, namespace string, filter *AccessAttributes, confirm bool) (*model.SubjectPermissions, error) {
+	groups = SubjectGroups(subject, groups)
+	bindings, err := fetchBindings(ctx, clientset, namespace, namespace != "")
+	if err != nil {
+		return nil, err
+	}
+	roles, err := newRoleIndex(ctx, clientset, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.SubjectPermissions{
+		Subject:   subject,
+		Namespace: namespace,
+		Groups:    groups,
+		Rules:     make([]model.SubjectRule, 0),
+	}
+	for i := range bindings {
+		b := &bindings[i]
+		if !bindingAppliesTo(b, subject, groups) {
+			continue
+		}
+		scope := "cluster"
+		if !b.cluster {
+			scope = b.namespace
+		}
+		for _, rule := range roles.rules(b) {
+			if filter != nil && !ruleAllows(rule, filter) {
+				continue
+			}
+			result.Rules = append(result.Rules, model.SubjectRule{
+				PolicyRule: convertPolicyRule(rule),
+				Binding:    b.ref(),
+				Role:       b.roleRefString(),
+				Scope:      scope,
+				Via:        b.ref() + " -> " + b.roleRefString(),
+			})
+		}
+	}
+
+	if confirm && filter != nil {
+		result.Review = subjectAccessReview(ctx, clientset, subject, groups, filter)
+	}
+	return result, nil
+}
+
+// WhoCan 查找在命名空间中拥有指定访问权限的主体（who-can），namespace 为空时只统计 ClusterRoleBinding
+// confirm 时对每个主体发起 SubjectAccessReview，超过上限的主体不再确认
+func WhoCan(ctx context.Context, clientset *kubernetes.Clientset, attrs AccessAttributes, confirm bool) (*model.WhoCanResult, error) {
+	bindings, err := fetchBindings(ctx, clientset, attrs.Namespace, attrs.Namespace != "")
+	if err != nil {
+		return nil, err
+	}
+	roles, err := newRoleIndex(ctx, clientset, attrs.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := attrs.Resource
+	if attrs.Subresource != "" {
+		resource += "/" + attrs.Subresource
+	}
+	result := &model.WhoCanResult{
+		Verb:         attrs.Verb,
+		Resource:     resource,
+		APIGroup:     attrs.APIGroup,
+		ResourceName: attrs.Name,
+		Namespace:    attrs.Namespace,
+		Subjects:     make([]model.WhoCanSubject, 0),
+	}
+
+	found := make(map[string]*model.WhoCanSubject)
+	for i := range bindings {
+		b := &bindings[i]
+		allowed := false
+		for _, rule := range roles.rules(b) {
+			if ruleAllows(rule, &attrs) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			continue
+		}
+		via := b.ref() + " -> " + b.roleRefString()
+		for _, s := range b.subjects {
+			subject := model.RBACSubject{Kind: s.Kind, Name: s.Name, Namespace: s.Namespace}
+			if subject.Kind == rbacv1.ServiceAccountKind && subject.Namespace == "" {
+				subject.Namespace = b.namespace
+			}
+			key := formatSubject(subject)
+			entry, exists := found[key]
+			if !exists {
+				entry = &model.WhoCanSubject{RBACSubject: subject, Via: make([]string, 0, 1)}
+				found[key] = entry
+			}
+			if !ContainsString(entry.Via, via) {
+				entry.Via = append(entry.Via, via)
+			}
+		}
+	}
+
+	for _, entry := range found {
+		result.Subjects = append(result.Subjects, *entry)
+	}
+	sort.Slice(result.Subjects, func(i, j int) bool {
+		a, b := result.Subjects[i], result.Subjects[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return formatSubject(a.RBACSubject) < formatSubject(b.RBACSubject)
+	})
+
+	if confirm {
+		for i := range result.Subjects {
+			if i >= maxAccessReviews {
+				result.Truncated = true
+				break
+			}
+			subject := result.Subjects[i].RBACSubject
+			result.Subjects[i].Review = subjectAccessReview(ctx, clientset, subject, SubjectGroups(subject, nil), &attrs)
+		}
+	}
+	return result, nil
+}
+
+// bindingAppliesTo 判断绑定是否作用于主体：直接列出该主体，或列出其所属的组
+func bindingAppliesTo(b *rbacBinding, subject model.RBACSubject, groups []string) bool {
+	for _, s := range b.subjects {
+		if subjectEquals(s, b.namespace, subject) {
+			return true
+		}
+		if s.Kind == rbacv1.GroupKind && ContainsString(groups, s.Name) {
+			return true
+		}
+		// ServiceAccount 也可以以用户名形式出现在绑定中
+		if subject.Kind == rbacv1.ServiceAccountKind && s.Kind == rbacv1.UserKind &&
+			s.Name == serviceAccountUsername(subject) {
+			return true
+		}
+	}
+	return false
+}
+
+func serviceAccountUsername(subject model.RBACSubject) string {
+	return "system:serviceaccount:" + subject.Namespace + ":" + subject.Name
+}
+
+// ruleAllows 按 RBAC 授权器的匹配规则判断规则是否允许该访问
+func ruleAllows(rule rbacv1.PolicyRule, attrs *AccessAttributes) bool {
+	if !containsOrWildcard(rule.Verbs, attrs.Verb) || !containsOrWildcard(rule.APIGroups, attrs.APIGroup) {
+		return false
+	}
+	resourceMatched := false
+	combined := attrs.Resource
+	if attrs.Subresource != "" {
+		combined += "/" + attrs.Subresource
+	}
+	for _, r := range rule.Resources {
+		if r == rbacv1.ResourceAll || r == combined || (attrs.Subresource != "" && r == "*/"+attrs.Subresource) {
+			resourceMatched = true
+			break
+		}
+	}
+	if !resourceMatched {
+		return false
+	}
+	return len(rule.ResourceNames) == 0 || ContainsString(rule.ResourceNames, attrs.Name)
+}
+
+func containsOrWildcard(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// roleIndex 缓存分析所需的 Role 与 ClusterRole
+type roleIndex struct {
+	roles        map[string][]rbacv1.PolicyRule
+	clusterRoles map[string][]rbacv1.PolicyRule
+}
+
+func newRoleIndex(ctx context.Context, clientset *kubernetes.Clientset, namespace string) (*roleIndex, error) {
+	idx := &roleIndex{
+		roles:        make(map[string][]rbacv1.PolicyRule),
+		clusterRoles: make(map[string][]rbacv1.PolicyRule),
+	}
+	crList, err := clientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, cr := range crList.Items {
+		idx.clusterRoles[cr.Name] = cr.Rules
+	}
+	if namespace == "" {
+		return idx, nil
+	}
+	roleList, err := clientset.RbacV1().Roles(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roleList.Items {
+		idx.roles[r.Name] = r.Rules
+	}
+	return idx, nil
+}
+
+// rules 返回绑定所引用角色的规则，角色不存在时为空
+func (idx *roleIndex) rules(b *rbacBinding) []rbacv1.PolicyRule {
+	switch b.roleRef.Kind {
+	case KindClusterRole:
+		return idx.clusterRoles[b.roleRef.Name]
+	case KindRole:
+		if b.cluster {
+			return nil
+		}
+		return idx.roles[b.roleRef.Name]
+	}
+	return nil
+}
+
+// subjectAccessReview 请求apiserver确认主体是否拥有该访问权限，失败时记录在 Error 中
+func subjectAccessReview(ctx context.Context, clientset *kubernetes.Clientset, subject model.RBACSubject, groups []string, attrs *AccessAttributes) *model.AccessReviewResult {
+	spec := authorizationv1.SubjectAccessReviewSpec{
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace:   attrs.Namespace,
+			Verb:        attrs.Verb,
+			Group:       attrs.APIGroup,
+			Resource:    attrs.Resource,
+			Subresource: attrs.Subresource,
+			Name:        attrs.Name,
+		},
+		Groups: groups,
+	}
+	switch subject.Kind {
+	case rbacv1.UserKind:
+		spec.User = subject.Name
+	case rbacv1.ServiceAccountKind:
+		spec.User = serviceAccountUsername(subject)
+	case rbacv1.GroupKind:
+		spec.Groups = []string{subject.Name}
+	}
+
+	review, err := clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{Spec: spec}, metav1.CreateOptions{})
+	if err != nil {
+		return &model.AccessReviewResult{Error: err.Error()}
+	}
+	return &model.AccessReviewResult{
+		Allowed: review.Status.Allowed,
+		Denied:  review.Status.Denied,
+		Reason:  review.Status.Reason,
+	}
+}
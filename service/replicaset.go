@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nick0323/K8sVision/model"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func ListReplicaSets(ctx context.Context, clientset *kubernetes.Clientset, namespace string) ([]model.ReplicaSetStatus, error) {
+	rsList, err := clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]model.ReplicaSetStatus, 0, len(rsList.Items))
+	for _, rs := range rsList.Items {
+		desired := SafeInt32Ptr(rs.Spec.Replicas, 1)
+		result = append(result, model.ReplicaSetStatus{
+			Namespace: rs.Namespace,
+			Name:      rs.Name,
+			Owner:     controllerOwner(rs.OwnerReferences),
+			Revision:  rs.Annotations[deploymentRevisionAnnotation],
+			Desired:   desired,
+			Current:   rs.Status.Replicas,
+			Ready:     rs.Status.ReadyReplicas,
+			Image:     containerImages(rs.Spec.Template.Spec.Containers),
+			// 历史版本的副本数为0，使用 GetResourceStatus 显示为 Scaled to zero 而非异常
+			Status: GetResourceStatus(rs.Status.ReadyReplicas, desired),
+			Age:    FormatAge(rs.CreationTimestamp.Time),
+		})
+	}
+	return result, nil
+}
+
+// GetReplicaSetDetail 获取ReplicaSet详情及其当前管理的Pod
+func GetReplicaSetDetail(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.ReplicaSetDetail, error) {
+	rs, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.ReplicaSetDetail{}, err
+	}
+
+	pods := make([]string, 0)
+	if rs.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(rs.Spec.Selector)
+		if err != nil {
+			return model.ReplicaSetDetail{}, err
+		}
+		podList, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return model.ReplicaSetDetail{}, err
+		}
+		for _, pod := range podList.Items {
+			if ownedBy(pod.OwnerReferences, rs.UID) {
+				pods = append(pods, pod.Name)
+			}
+		}
+	}
+
+	image := ""
+	if len(rs.Spec.Template.Spec.Containers) > 0 {
+		image = rs.Spec.Template.Spec.Containers[0].Image
+	}
+	var selector map[string]string
+	if rs.Spec.Selector != nil {
+		selector = rs.Spec.Selector.MatchLabels
+	}
+	desired := SafeInt32Ptr(rs.Spec.Replicas, 1)
+
+	return model.ReplicaSetDetail{
+		WorkloadCommonFields: model.WorkloadCommonFields{
+			CommonResourceFields: model.CommonResourceFields{
+				Namespace: rs.Namespace,
+				Name:      rs.Name,
+				Status:    GetResourceStatus(rs.Status.ReadyReplicas, desired),
+				BaseMetadata: model.BaseMetadata{
+					Labels:      rs.Labels,
+					Annotations: rs.Annotations,
+				},
+			},
+			Available: rs.Status.AvailableReplicas,
+			Desired:   desired,
+			Selector:  selector,
+			Image:     image,
+		},
+		Owner:      controllerOwner(rs.OwnerReferences),
+		Revision:   rs.Annotations[deploymentRevisionAnnotation],
+		Current:    rs.Status.Replicas,
+		Ready:      rs.Status.ReadyReplicas,
+		CreatedAt:  rs.CreationTimestamp.Local().Format(model.TimeFormat),
+		Pods:       pods,
+		Conditions: replicaSetConditions(rs.Status.Conditions),
+	}, nil
+}
+
+func replicaSetConditions(conditions []appsv1.ReplicaSetCondition) []model.ResourceCondition {
+	result := make([]model.ResourceCondition, 0, len(conditions))
+	for _, c := range conditions {
+		result = append(result, model.ResourceCondition{
+			Type:               string(c.Type),
+			Status:             string(c.Status),
+			Reason:             c.Reason,
+			Message:            c.Message,
+			LastTransitionTime: formatConditionTime(c.LastTransitionTime),
+		})
+	}
+	return result
+}
+
+// controllerOwner 返回控制者的 Kind/Name，没有控制者时返回空字符串
+func controllerOwner(refs []metav1.OwnerReference) string {
+	for _, ref := range refs {
+		if ref.Controller != nil && *ref.Controller {
+			return ref.Kind + "/" + ref.Name
+		}
+	}
+	return ""
+}
+
+func formatConditionTime(t metav1.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(model.TimeFormat)
+}
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/nick0323/K8sVision/model"
 
@@ -16,18 +15,67 @@ func ListServices(ctx context.Context, clientset *kubernetes.Clientset, namespac
 		return nil, err
 	}
 	svcStatuses := make([]model.ServiceStatus, 0, len(svcs.Items))
-	for _, svc := range svcs.Items {
-		ports := make([]string, 0, len(svc.Spec.Ports))
-		for _, p := range svc.Spec.Ports {
-			ports = append(ports, fmt.Sprintf("%d/%s", p.Port, p.Protocol))
-		}
+	for i := range svcs.Items {
+		svc := &svcs.Items[i]
 		svcStatuses = append(svcStatuses, model.ServiceStatus{
 			Namespace: svc.Namespace,
 			Name:      svc.Name,
 			Type:      string(svc.Spec.Type),
 			ClusterIP: svc.Spec.ClusterIP,
-			Ports:     ports,
+			Ports:     formatServicePorts(svc),
 		})
 	}
 	return svcStatuses, nil
 }
+
+// GetServiceDetail 获取Service详情，包括所属的 EndpointSlice 以及没有后端时的诊断信息
+func GetServiceDetail(ctx context.Context, clientset *kubernetes.Clientset, namespace, name string) (model.ServiceDetail, error) {
+	svc, err := clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return model.ServiceDetail{}, err
+	}
+	status := model.StatusActive
+	backends, err := getServiceBackends(ctx, clientset, svc)
+	if err != nil {
+		// 无权列举 EndpointSlice 或Pod时仍返回 Service 本身，后端状态未知
+		status = model.StatusUnknown
+		backends = &serviceBackends{
+			slices: make([]model.EndpointSliceInfo, 0),
+			diagnostics: []model.ServiceDiagnostic{{
+				Level:   DiagnosticWarning,
+				Code:    DiagBackendLookupFailed,
+				Message: "无法查询后端端点: " + err.Error(),
+			}},
+		}
+	}
+
+	for _, d := range backends.diagnostics {
+		if d.Level == DiagnosticError {
+			status = model.StatusAbnormal
+			break
+		}
+	}
+
+	return model.ServiceDetail{
+		CommonResourceFields: model.CommonResourceFields{
+			Namespace: svc.Namespace,
+			Name:      svc.Name,
+			Status:    status,
+			BaseMetadata: model.BaseMetadata{
+				Labels:      svc.Labels,
+				Annotations: svc.Annotations,
+			},
+		},
+		Type:              string(svc.Spec.Type),
+		ClusterIP:         svc.Spec.ClusterIP,
+		Ports:             formatServicePorts(svc),
+		Selector:          svc.Spec.Selector,
+		PortDetails:       servicePorts(svc),
+		EndpointSlices:    backends.slices,
+		ReadyEndpoints:    backends.ready,
+		NotReadyEndpoints: backends.notReady,
+		MatchedPods:       backends.matchedPods,
+		ReadyPods:         backends.readyPods,
+		Diagnostics:       backends.diagnostics,
+	}, nil
+}
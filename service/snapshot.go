@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nick0323/K8sVision/archive"
+	"github.com/nick0323/K8sVision/model"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CollectClusterSnapshot 通过各资源列表函数采集告警评估所需的集群状态，以服务自身身份访问
+func CollectClusterSnapshot(ctx context.Context) (*model.ClusterSnapshot, error) {
+	clientset, _, err := GetK8sClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &model.ClusterSnapshot{Time: time.Now()}
+
+	if snapshot.Deployments, err = ListDeployments(ctx, clientset, ""); err != nil {
+		return nil, fmt.Errorf("获取Deployment列表失败: %w", err)
+	}
+
+	_, pods, err := ListPodsWithRaw(ctx, clientset, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("获取Pod列表失败: %w", err)
+	}
+	snapshot.Pods = make([]model.PodRestartStatus, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		status := model.PodRestartStatus{Namespace: pod.Namespace, Name: pod.Name}
+		for _, cs := range pod.Status.ContainerStatuses {
+			status.Restarts += cs.RestartCount
+		}
+		snapshot.Pods = append(snapshot.Pods, status)
+	}
+
+	if snapshot.Nodes, err = ListNodes(ctx, clientset, pods, nil); err != nil {
+		return nil, fmt.Errorf("获取节点列表失败: %w", err)
+	}
+	if snapshot.PVCs, err = ListPVCs(ctx, clientset, ""); err != nil {
+		return nil, fmt.Errorf("获取PVC列表失败: %w", err)
+	}
+	events, err := clientset.CoreV1().Events("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("获取事件列表失败: %w", err)
+	}
+	snapshot.Events = make([]model.SnapshotEvent, 0, len(events.Items))
+	for i := range events.Items {
+		// 与事件归档一致，LastTimestamp 为空时回退到 Series/EventTime
+		record := archive.RecordFromEvent(&events.Items[i])
+		snapshot.Events = append(snapshot.Events, model.SnapshotEvent{
+			Namespace: record.Namespace,
+			Name:      record.Name,
+			Reason:    record.Reason,
+			Message:   record.Message,
+			Type:      record.Type,
+			LastSeen:  record.LastSeen,
+		})
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nick0323/K8sVision/archive"
+	"github.com/nick0323/K8sVision/model"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// 工作负载类型，用于时间线、HPA 伸缩目标等
+const (
+	WorkloadDeployment  = "Deployment"
+	WorkloadStatefulSet = "StatefulSet"
+	WorkloadDaemonSet   = "DaemonSet"
+	WorkloadJob         = "Job"
+	WorkloadCronJob     = "CronJob"
+	WorkloadReplicaSet  = "ReplicaSet"
+)
+
+const deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// timelineBuilder 收集工作负载及其下属对象，并汇总各类时间线条目
+type timelineBuilder struct {
+	namespace string
+	from, to  time.Time
+
+	uids    map[types.UID]bool
+	objects map[string]bool
+	// podOwners 直接创建Pod的对象名，用于匹配已删除Pod遗留的事件
+	podOwners map[types.UID]string
+	// cronJob 非空时还匹配该CronJob已被清理的Job（名称为 <cronjob>-<调度时间>）
+	cronJob string
+	entries []timelineItem
+}
+
+type timelineItem struct {
+	at    time.Time
+	entry model.TimelineEntry
+}
+
+// GetWorkloadTimeline 沿 ownerReferences 找到工作负载下属的ReplicaSet、Job和Pod，
+// 合并事件（含归档事件）、容器重启、版本变更和状态变化，按时间升序返回；from/to 为零值时不限制
+func GetWorkloadTimeline(ctx context.Context, clientset *kubernetes.Clientset, kind, namespace, name string, from, to time.Time) (*model.WorkloadTimeline, error) {
+	b := &timelineBuilder{
+		namespace: namespace,
+		from:      from,
+		to:        to,
+		uids:      make(map[types.UID]bool),
+		objects:   make(map[string]bool),
+		podOwners: make(map[types.UID]string),
+	}
+
+	var err error
+	switch kind {
+	case WorkloadDeployment:
+		err = b.collectDeployment(ctx, clientset, name)
+	case WorkloadStatefulSet:
+		err = b.collectStatefulSet(ctx, clientset, name)
+	case WorkloadDaemonSet:
+		err = b.collectDaemonSet(ctx, clientset, name)
+	case WorkloadJob:
+		err = b.collectJob(ctx, clientset, name)
+	case WorkloadCronJob:
+		err = b.collectCronJob(ctx, clientset, name)
+	default:
+		err = fmt.Errorf("不支持的工作负载类型: %s", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.collectPods(ctx, clientset); err != nil {
+		return nil, err
+	}
+	if err := b.collectEvents(ctx, clientset); err != nil {
+		return nil, err
+	}
+	return b.result(kind, name), nil
+}
+
+func (b *timelineBuilder) addObject(kind, name string, uid types.UID) string {
+	object := kind + "/" + name
+	b.uids[uid] = true
+	b.objects[object] = true
+	return object
+}
+
+func (b *timelineBuilder) add(at time.Time, entryType, severity, object, reason, message string, count int32) {
+	if at.IsZero() || (!b.from.IsZero() && at.Before(b.from)) || (!b.to.IsZero() && at.After(b.to)) {
+		return
+	}
+	b.entries = append(b.entries, timelineItem{at: at, entry: model.TimelineEntry{
+		Time:      at.Local().Format(model.TimeFormat),
+		Timestamp: at.Unix(),
+		Type:      entryType,
+		Severity:  severity,
+		Object:    object,
+		Reason:    reason,
+		Message:   message,
+		Count:     count,
+	}})
+}
+
+// addCondition 记录一次状态变化；ReplicaFailure、Failed 等负面条件为 True 时、其余条件为 False 时标记为 Warning
+func (b *timelineBuilder) addCondition(object, condType string, status corev1.ConditionStatus, at metav1.Time, reason, message string) {
+	severity := corev1.EventTypeNormal
+	negative := condType == "ReplicaFailure" || condType == "Failed" || condType == "FailureTarget"
+	if (negative && status == corev1.ConditionTrue) || (!negative && status == corev1.ConditionFalse) {
+		severity = corev1.EventTypeWarning
+	}
+	text := fmt.Sprintf("%s=%s", condType, status)
+	if message != "" {
+		text += ": " + message
+	}
+	b.add(at.Time, model.TimelineStatus, severity, object, reason, text, 0)
+}
+
+func (b *timelineBuilder) addCreated(object string, at metav1.Time, message string) {
+	b.add(at.Time, model.TimelineStatus, corev1.EventTypeNormal, object, "Created", message, 0)
+}
+
+func (b *timelineBuilder) collectDeployment(ctx context.Context, clientset *kubernetes.Clientset, name string) error {
+	dep, err := clientset.AppsV1().Deployments(b.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	object := b.addObject(WorkloadDeployment, dep.Name, dep.UID)
+	b.addCreated(object, dep.CreationTimestamp, "Deployment 已创建")
+	for _, cond := range dep.Status.Conditions {
+		b.addCondition(object, string(cond.Type), cond.Status, cond.LastTransitionTime, cond.Reason, cond.Message)
+	}
+
+	rsList, err := clientset.AppsV1().ReplicaSets(b.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	current := dep.Annotations[deploymentRevisionAnnotation]
+	for _, rs := range rsList.Items {
+		if !ownedBy(rs.OwnerReferences, dep.UID) {
+			continue
+		}
+		rsObject := b.addObject(WorkloadReplicaSet, rs.Name, rs.UID)
+		b.podOwners[rs.UID] = rs.Name
+
+		revision := rs.Annotations[deploymentRevisionAnnotation]
+		message := fmt.Sprintf("版本 %s，镜像 %s", revision, containerImages(rs.Spec.Template.Spec.Containers))
+		if revision != "" && revision == current {
+			message += "（当前版本）"
+		}
+		b.add(rs.CreationTimestamp.Time, model.TimelineRevision, corev1.EventTypeNormal, rsObject, "RevisionCreated", message, 0)
+		for _, cond := range rs.Status.Conditions {
+			b.addCondition(rsObject, string(cond.Type), cond.Status, cond.LastTransitionTime, cond.Reason, cond.Message)
+		}
+	}
+	return nil
+}
+
+func (b *timelineBuilder) collectStatefulSet(ctx context.Context, clientset *kubernetes.Clientset, name string) error {
+	sts, err := clientset.AppsV1().StatefulSets(b.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	object := b.addObject(WorkloadStatefulSet, sts.Name, sts.UID)
+	b.podOwners[sts.UID] = sts.Name
+	b.addCreated(object, sts.CreationTimestamp, "StatefulSet 已创建")
+	for _, cond := range sts.Status.Conditions {
+		b.addCondition(object, string(cond.Type), cond.Status, cond.LastTransitionTime, cond.Reason, cond.Message)
+	}
+	return b.collectControllerRevisions(ctx, clientset, object, sts.UID, sts.Status.UpdateRevision)
+}
+
+func (b *timelineBuilder) collectDaemonSet(ctx context.Context, clientset *kubernetes.Clientset, name string) error {
+	ds, err := clientset.AppsV1().DaemonSets(b.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	object := b.addObject(WorkloadDaemonSet, ds.Name, ds.UID)
+	b.podOwners[ds.UID] = ds.Name
+	b.addCreated(object, ds.CreationTimestamp, "DaemonSet 已创建")
+	for _, cond := range ds.Status.Conditions {
+		b.addCondition(object, string(cond.Type), cond.Status, cond.LastTransitionTime, cond.Reason, cond.Message)
+	}
+	return b.collectControllerRevisions(ctx, clientset, object, ds.UID, "")
+}
+
+// collectControllerRevisions StatefulSet、DaemonSet 的历史版本保存在 ControllerRevision 中
+func (b *timelineBuilder) collectControllerRevisions(ctx context.Context, clientset *kubernetes.Clientset, object string, owner types.UID, current string) error {
+	revisions, err := clientset.AppsV1().ControllerRevisions(b.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	latest := int64(-1)
+	for _, rev := range revisions.Items {
+		if ownedBy(rev.OwnerReferences, owner) && rev.Revision > latest {
+			latest = rev.Revision
+		}
+	}
+	for _, rev := range revisions.Items {
+		if !ownedBy(rev.OwnerReferences, owner) {
+			continue
+		}
+		message := fmt.Sprintf("版本 %d（%s）", rev.Revision, rev.Name)
+		if rev.Name == current || (current == "" && rev.Revision == latest) {
+			message += "（当前版本）"
+		}
+		b.add(rev.CreationTimestamp.Time, model.TimelineRevision, corev1.EventTypeNormal, object, "RevisionCreated", message, 0)
+	}
+	return nil
+}
+
+func (b *timelineBuilder) collectJob(ctx context.Context, clientset *kubernetes.Clientset, name string) error {
+	job, err := clientset.BatchV1().Jobs(b.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	b.addJob(job.Name, job.UID, job.CreationTimestamp, job.Status.StartTime, job.Status.CompletionTime, job.Status.Conditions)
+	return nil
+}
+
+func (b *timelineBuilder) collectCronJob(ctx context.Context, clientset *kubernetes.Clientset, name string) error {
+	cj, err := clientset.BatchV1().CronJobs(b.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	object := b.addObject(WorkloadCronJob, cj.Name, cj.UID)
+	b.cronJob = cj.Name
+	b.addCreated(object, cj.CreationTimestamp, "CronJob 已创建")
+
+	jobs, err := clientset.BatchV1().Jobs(b.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs.Items {
+		if ownedBy(job.OwnerReferences, cj.UID) {
+			b.addJob(job.Name, job.UID, job.CreationTimestamp, job.Status.StartTime, job.Status.CompletionTime, job.Status.Conditions)
+		}
+	}
+	return nil
+}
+
+func (b *timelineBuilder) addJob(name string, uid types.UID, created metav1.Time, start, completion *metav1.Time, conditions []batchv1.JobCondition) {
+	object := b.addObject(WorkloadJob, name, uid)
+	b.podOwners[uid] = name
+	b.addCreated(object, created, "Job 已创建")
+	if start != nil {
+		b.add(start.Time, model.TimelineStatus, corev1.EventTypeNormal, object, "Started", "Job 开始运行", 0)
+	}
+	if completion != nil {
+		b.add(completion.Time, model.TimelineStatus, corev1.EventTypeNormal, object, "Completed", "Job 运行完成", 0)
+	}
+	for _, cond := range conditions {
+		b.addCondition(object, string(cond.Type), cond.Status, cond.LastTransitionTime, cond.Reason, cond.Message)
+	}
+}
+
+// collectPods 记录属于已收集对象的Pod的创建、状态变化和容器重启
+func (b *timelineBuilder) collectPods(ctx context.Context, clientset *kubernetes.Clientset) error {
+	pods, err := clientset.CoreV1().Pods(b.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods.Items {
+		owned := false
+		for _, ref := range pod.OwnerReferences {
+			if _, ok := b.podOwners[ref.UID]; ok {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			continue
+		}
+
+		object := b.addObject("Pod", pod.Name, pod.UID)
+		message := "Pod 已创建"
+		if pod.Spec.NodeName != "" {
+			message += "，调度到节点 " + pod.Spec.NodeName
+		}
+		b.addCreated(object, pod.CreationTimestamp, message)
+		for _, cond := range pod.Status.Conditions {
+			b.addCondition(object, string(cond.Type), cond.Status, cond.LastTransitionTime, cond.Reason, cond.Message)
+		}
+
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, cs := range statuses {
+			terminated := cs.LastTerminationState.Terminated
+			if cs.RestartCount == 0 || terminated == nil {
+				continue
+			}
+			b.add(terminated.FinishedAt.Time, model.TimelineRestart, corev1.EventTypeWarning, object, terminated.Reason,
+				fmt.Sprintf("容器 %s 已重启 %d 次，上次退出码 %d", cs.Name, cs.RestartCount, terminated.ExitCode), cs.RestartCount)
+		}
+	}
+	return nil
+}
+
+// collectEvents 合并实时事件和归档事件，按事件UID去重
+func (b *timelineBuilder) collectEvents(ctx context.Context, clientset *kubernetes.Clientset) error {
+	events, err := clientset.CoreV1().Events(b.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(events.Items))
+	for i := range events.Items {
+		e := &events.Items[i]
+		if !b.uids[e.InvolvedObject.UID] && !b.matchObject(e.InvolvedObject.Kind, e.InvolvedObject.Name) {
+			continue
+		}
+		seen[string(e.UID)] = true
+		b.addEvent(archive.RecordFromEvent(e))
+	}
+
+	if st := archive.Default(); st != nil {
+		for _, r := range st.Query(archive.Query{Namespace: b.namespace, From: b.from, To: b.to}) {
+			if !seen[r.UID] && b.matchObject(r.ObjectKind, r.ObjectName) {
+				b.addEvent(r)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *timelineBuilder) addEvent(r archive.Record) {
+	b.add(r.LastSeen, model.TimelineEvent, r.Type, r.Object(), r.Reason, r.Message, r.Count)
+}
+
+// matchObject 匹配已收集的对象；Pod 还匹配由已收集对象创建、但已被删除的Pod（名称为 <owner>-<后缀>）
+func (b *timelineBuilder) matchObject(kind, name string) bool {
+	if b.objects[kind+"/"+name] {
+		return true
+	}
+	switch kind {
+	case "Pod":
+		for _, owner := range b.podOwners {
+			if suffix, ok := strings.CutPrefix(name, owner+"-"); ok && suffix != "" && !strings.Contains(suffix, "-") {
+				return true
+			}
+		}
+		if rest, ok := strings.CutPrefix(name, b.cronJob+"-"); ok && b.cronJob != "" {
+			job, _, found := strings.Cut(rest, "-")
+			return found && isDigits(job)
+		}
+	case WorkloadJob:
+		if b.cronJob != "" {
+			suffix, ok := strings.CutPrefix(name, b.cronJob+"-")
+			return ok && isDigits(suffix)
+		}
+	}
+	return false
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *timelineBuilder) result(kind, name string) *model.WorkloadTimeline {
+	sort.SliceStable(b.entries, func(i, j int) bool { return b.entries[i].at.Before(b.entries[j].at) })
+
+	timeline := &model.WorkloadTimeline{
+		Kind:      kind,
+		Namespace: b.namespace,
+		Name:      name,
+		Objects:   make([]string, 0, len(b.objects)),
+		Entries:   make([]model.TimelineEntry, 0, len(b.entries)),
+	}
+	for object := range b.objects {
+		timeline.Objects = append(timeline.Objects, object)
+	}
+	sort.Strings(timeline.Objects)
+	for _, item := range b.entries {
+		timeline.Entries = append(timeline.Entries, item.entry)
+	}
+	return timeline
+}
+
+func ownedBy(refs []metav1.OwnerReference, uid types.UID) bool {
+	for _, ref := range refs {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func containerImages(containers []corev1.Container) string {
+	images := make([]string, 0, len(containers))
+	for _, c := range containers {
+		images = append(images, c.Image)
+	}
+	return strings.Join(images, ", ")
+}
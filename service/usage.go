@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nick0323/K8sVision/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CollectUsage 从metrics-server读取节点与Pod的当前用量，并按命名空间汇总Pod用量
+// 以服务自身身份访问，供后台采样使用
+func CollectUsage(ctx context.Context) ([]model.UsageSample, error) {
+	_, metricsClient, err := GetK8sClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeMetrics, err := metricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("获取节点用量失败: %w", err)
+	}
+	podMetrics, err := metricsClient.MetricsV1beta1().PodMetricses("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("获取Pod用量失败: %w", err)
+	}
+
+	samples := make([]model.UsageSample, 0, len(nodeMetrics.Items)+len(podMetrics.Items))
+	for _, m := range nodeMetrics.Items {
+		samples = append(samples, model.UsageSample{
+			Kind:   model.UsageKindNode,
+			Name:   m.Name,
+			CPU:    float64(m.Usage.Cpu().MilliValue()),
+			Memory: float64(m.Usage.Memory().Value()),
+		})
+	}
+
+	namespaces := make(map[string]*model.UsageSample)
+	for _, m := range podMetrics.Items {
+		pod := model.UsageSample{Kind: model.UsageKindPod, Namespace: m.Namespace, Name: m.Name}
+		for _, c := range m.Containers {
+			pod.CPU += float64(c.Usage.Cpu().MilliValue())
+			pod.Memory += float64(c.Usage.Memory().Value())
+		}
+		samples = append(samples, pod)
+
+		ns, exists := namespaces[m.Namespace]
+		if !exists {
+			ns = &model.UsageSample{Kind: model.UsageKindNamespace, Name: m.Namespace}
+			namespaces[m.Namespace] = ns
+		}
+		ns.CPU += pod.CPU
+		ns.Memory += pod.Memory
+	}
+	for _, ns := range namespaces {
+		samples = append(samples, *ns)
+	}
+	return samples, nil
+}
@@ -39,6 +39,18 @@ function DetailItem({ label, value, type = 'text' }) {
         );
       
       case 'container':
+        // Pod 详情返回结构化的容器状态
+        if (typeof value === 'object') {
+          return (
+            <div className="container-item">
+              <span className="label-key">{value.name}</span>
+              <span className="label-value">{value.image}</span>
+              <span className="label-value">
+                {value.state}{value.reason ? ` (${value.reason})` : ''} · restarts {value.restartCount}
+              </span>
+            </div>
+          );
+        }
         // 检查是否包含镜像信息 (格式: "name (image)")
         const containerMatch = value.match(/^(.+?)\s*\((.+)\)$/);
         if (containerMatch) {
@@ -76,12 +88,16 @@ function DetailItem({ label, value, type = 'text' }) {
       case 'ports':
       case 'hosts':
       case 'role':
+      case 'taints':
+      case 'issues':
       case 'paths':
       case 'targetServices':
         if (Array.isArray(value)) {
           const label = type === 'ports' ? 'Port' : 
                        type === 'hosts' ? 'Host' : 
                        type === 'role' ? 'Role' : 
+                       type === 'taints' ? 'Taint' : 
+                       type === 'issues' ? 'Issue' : 
                        type === 'paths' ? 'Path' : 'Service';
           
           return (
@@ -134,7 +150,7 @@ function DetailItem({ label, value, type = 'text' }) {
               {Object.entries(value).map(([key, val]) => (
                 <div key={key} className="label-item">
                   <span className="label-key">{key}</span>
-                  <span className="label-value">{type === 'secretData' ? '***' : val}</span>
+                  <span className="label-value">{type === 'secretData' ? `*** (${val})` : val}</span>
                 </div>
               ))}
             </div>
@@ -198,6 +214,9 @@ export function PodDetails({ data }) {
         <DetailItem label="Pod IP" value={data.podIP} />
         <DetailItem label="Node" value={data.nodeName} />
         <DetailItem label="Start Time" value={data.startTime} />
+        <DetailItem label="Ready" value={data.ready} />
+        <DetailItem label="Restarts" value={data.restarts !== undefined ? String(data.restarts) : undefined} />
+        <DetailItem label="QoS Class" value={data.qosClass} />
       </DetailCard>
 
       <DetailCard title="Containers">
@@ -409,6 +428,26 @@ export function IngressDetails({ data }) {
         <DetailItem label="Target Services" value={data.targetServices || data.targetService || data.service} type="targetServices" />
       </DetailCard>
 
+      {data.tls && data.tls.length > 0 && (
+        <DetailCard title="TLS">
+          {data.tls.map((tls, index) => (
+            <DetailItem
+              key={index}
+              label={tls.secretName || '(default)'}
+              value={tls.error || (tls.certificate
+                ? `${tls.certificate.subject} · expires ${tls.certificate.notAfter} (${tls.certificate.daysRemaining}d)`
+                : '-')}
+            />
+          ))}
+        </DetailCard>
+      )}
+
+      {data.diagnostics && data.diagnostics.length > 0 && (
+        <DetailCard title="Diagnostics">
+          <DetailItem label="" value={data.diagnostics.map(d => `[${d.level}] ${d.message}`)} type="issues" />
+        </DetailCard>
+      )}
+
       <DetailCard title="Labels">
         <DetailItem label="" value={data.labels} type="labels" />
       </DetailCard>
@@ -431,12 +470,29 @@ export function NodeDetails({ data }) {
         <DetailItem label="Memory Usage" value={data.memoryUsage} type="percentage" />
         <DetailItem label="Pods Used" value={data.podsUsed} />
         <DetailItem label="Pods Capacity" value={data.podsCapacity} />
+        <DetailItem label="Schedulable" value={data.unschedulable ? 'No (cordoned)' : 'Yes'} />
+        <DetailItem label="CPU Requests" value={data.allocated?.cpuRequestsPercent} type="percentage" />
+        <DetailItem label="CPU Limits" value={data.allocated?.cpuLimitsPercent} type="percentage" />
+        <DetailItem label="Memory Requests" value={data.allocated?.memoryRequestsPercent} type="percentage" />
+        <DetailItem label="Memory Limits" value={data.allocated?.memoryLimitsPercent} type="percentage" />
+      </DetailCard>
+
+      <DetailCard title="System Info">
+        <DetailItem label="Kubelet" value={data.systemInfo?.kubeletVersion} />
+        <DetailItem label="Container Runtime" value={data.systemInfo?.containerRuntimeVersion} />
+        <DetailItem label="OS Image" value={data.systemInfo?.osImage} />
+        <DetailItem label="Kernel" value={data.systemInfo?.kernelVersion} />
+        <DetailItem label="Architecture" value={data.systemInfo?.architecture} />
       </DetailCard>
 
       <DetailCard title="Role">
         <DetailItem label="" value={data.role} type="role" />
       </DetailCard>
 
+      <DetailCard title="Taints">
+        <DetailItem label="" value={data.taints} type="taints" />
+      </DetailCard>
+
       <DetailCard title="Labels">
         <DetailItem label="" value={data.labels} type="labels" />
       </DetailCard>
@@ -502,7 +558,11 @@ export function SecretDetails({ data }) {
       </DetailCard>
 
       <DetailCard title="Data">
-        <DetailItem label="" value={data.data} type="secretData" />
+        <DetailItem
+          label=""
+          value={Object.fromEntries((data.entries || []).map(e => [e.key, `${e.size} bytes`]))}
+          type="secretData"
+        />
       </DetailCard>
 
       <DetailCard title="Labels">
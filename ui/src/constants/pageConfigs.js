@@ -17,7 +17,10 @@ const createResourceConfig = (title, resourceType, columns, statusMap = {}, tabl
 export const PODS_CONFIG = createResourceConfig('Pods', 'pods', [
   PREDEFINED_COLUMNS.name(),
   PREDEFINED_COLUMNS.namespace(),
+  PREDEFINED_COLUMNS.ready(),
   PREDEFINED_COLUMNS.status(),
+  PREDEFINED_COLUMNS.restarts(),
+  PREDEFINED_COLUMNS.age(),
   PREDEFINED_COLUMNS.cpuUsage(),
   PREDEFINED_COLUMNS.memoryUsage(),
   PREDEFINED_COLUMNS.podIP(),
@@ -124,6 +124,9 @@ export const PREDEFINED_COLUMNS = {
   availableReplicas: (options = {}) => createColumn('Available', 'availableReplicas', COLUMN_TYPES.NUMBER, options),
   desiredReplicas: (options = {}) => createColumn('Desired', 'desiredReplicas', COLUMN_TYPES.NUMBER, options),
   readyReplicas: (options = {}) => createColumn('Ready', 'readyReplicas', COLUMN_TYPES.NUMBER, options),
+  // Pod 就绪容器数（x/y）与重启次数
+  ready: (options = {}) => createColumn('Ready', 'ready', COLUMN_TYPES.TEXT, options),
+  restarts: (options = {}) => createColumn('Restarts', 'restarts', COLUMN_TYPES.NUMBER, options),
   
   // 资源使用列
   cpuUsage: (options = {}) => createColumn('CPU Usage', 'cpuUsage', COLUMN_TYPES.USAGE, options),